@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package action
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"math/rand"
+	"path/filepath"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defcore"
@@ -35,6 +37,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/credential"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/health"
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/printer"
 	"github.com/spf13/pflag"
 )
@@ -52,6 +56,7 @@ type ArgStruct struct {
 
 // Action is the base implementation of the Action interface.
 type Action struct {
+	config         *cliconfig.CLIConfig
 	flags          *pflag.FlagSet
 	sdk            *fabsdk.FabricSDK
 	endpointConfig fab.EndpointConfig
@@ -59,24 +64,45 @@ type Action struct {
 	peers          []fab.Peer
 	orgIDByPeer    map[string]string
 	printer        printer.Printer
+	output         printer.Writer
 	initError      error
 	Writer         io.Writer
 	sessions       map[string]context.ClientProvider
+	credential     *credential.Credential
+	identities     map[string]mspapi.SigningIdentity
+	healthMonitor  *health.Monitor
 }
 
-// Initialize initializes the action using the given flags
-func (action *Action) Initialize(flags *pflag.FlagSet) error {
+// Initialize initializes the action using the given configuration and flags
+func (action *Action) Initialize(config *cliconfig.CLIConfig, flags *pflag.FlagSet) error {
 
 	action.sessions = make(map[string]context.ClientProvider)
+	action.identities = make(map[string]mspapi.SigningIdentity)
+	action.config = config
 	action.flags = flags
 
-	if err := cliconfig.InitConfig(flags); err != nil {
+	if err := config.InitConfig(flags); err != nil {
 		return err
 	}
 
+	if err := action.preflight(); err != nil {
+		return err
+	}
+
+	// Load the credential before the SDK is initialized so that a passphrase prompt isn't interleaved with log output
+	if source, err := credentialSource(config); err != nil {
+		return err
+	} else if source != nil {
+		action.config.Logger().Debugf("Loading signing identity from %s\n", source)
+		action.credential, err = source.Load()
+		if err != nil {
+			return errors.WithMessagef(err, "error loading signing identity from %s", source)
+		}
+	}
+
 	var opts []fabsdk.Option
-	if cliconfig.Config().SelectionProvider() != cliconfig.AutoDetectSelectionProvider {
-		svcPackage, err := newServiceProviderFactory()
+	if action.config.SelectionProvider() != cliconfig.AutoDetectSelectionProvider || action.config.Verbose() {
+		svcPackage, err := newServiceProviderFactory(config)
 		if err != nil {
 			return err
 		}
@@ -84,7 +110,7 @@ func (action *Action) Initialize(flags *pflag.FlagSet) error {
 	}
 	opts = append(opts, fabsdk.WithCorePkg(&cryptoSuiteProviderFactory{}))
 
-	sdk, err := fabsdk.New(cliconfig.Provider(), opts...)
+	sdk, err := fabsdk.New(config.Provider(), opts...)
 	if err != nil {
 		return errors.Errorf("Error initializing SDK: %s", err)
 	}
@@ -99,7 +125,7 @@ func (action *Action) Initialize(flags *pflag.FlagSet) error {
 
 	networkConfig := action.endpointConfig.NetworkConfig()
 
-	level := levelFromName(cliconfig.Config().LoggingLevel())
+	level := levelFromName(action.config.LoggingLevel())
 
 	logging.SetLevel("", level)
 
@@ -113,7 +139,7 @@ func (action *Action) Initialize(flags *pflag.FlagSet) error {
 			return errors.Errorf("failed to get peer configs for org [%s]", orgID)
 		}
 
-		cliconfig.Config().Logger().Debugf("Peers for org [%s]: %v\n", orgID, peersConfig)
+		action.config.Logger().Debugf("Peers for org [%s]: %v\n", orgID, peersConfig)
 
 		var peers []fab.Peer
 		for _, p := range peersConfig {
@@ -128,27 +154,27 @@ func (action *Action) Initialize(flags *pflag.FlagSet) error {
 		allPeers = append(allPeers, peers...)
 	}
 
-	if cliconfig.Config().IsLoggingEnabledFor(logging.DEBUG) {
-		cliconfig.Config().Logger().Debug("All Peers:")
+	if action.config.IsLoggingEnabledFor(logging.DEBUG) {
+		action.config.Logger().Debug("All Peers:")
 		for orgID, peers := range allPeersByOrg {
-			cliconfig.Config().Logger().Debugf("Org: %s\n", orgID)
+			action.config.Logger().Debugf("Org: %s\n", orgID)
 			for i, peer := range peers {
-				cliconfig.Config().Logger().Debugf("-- Peer[%d]: MSPID: %s, URL: %s\n", i, peer.MSPID(), peer.URL())
+				action.config.Logger().Debugf("-- Peer[%d]: MSPID: %s, URL: %s\n", i, peer.MSPID(), peer.URL())
 			}
 		}
 	}
 
 	// Filter peers by specified peers/orgs
-	peers, err := action.getPeers(allPeers, cliconfig.Config().PeerURLs(), cliconfig.Config().OrgIDs())
+	peers, err := action.getPeers(allPeers, action.config.PeerURLs(), action.config.OrgIDs())
 	if err != nil {
 		return err
 	}
 
 	// Organize peers by orgs
 	peersByOrg := make(map[string][]fab.Peer)
-	cliconfig.Config().Logger().Debugf("Selected Peers:\n")
+	action.config.Logger().Debugf("Selected Peers:\n")
 	for _, peer := range peers {
-		cliconfig.Config().Logger().Debugf("- URL: %s\n", peer.URL())
+		action.config.Logger().Debugf("- URL: %s\n", peer.URL())
 		orgID := action.orgIDByPeer[peer.URL()]
 		if orgID == "" {
 			return errors.Errorf("unable to find org for peer: %s", peer.URL())
@@ -159,20 +185,113 @@ func (action *Action) Initialize(flags *pflag.FlagSet) error {
 	action.peers = peers
 	action.peersByOrg = peersByOrg
 
+	return action.initPrinter()
+}
+
+// InitializeOffline initializes the action for commands that don't connect to the network. Only the
+// configuration and the printer are initialized, so a connection profile isn't required.
+func (action *Action) InitializeOffline(config *cliconfig.CLIConfig, flags *pflag.FlagSet) error {
+	action.config = config
+	action.flags = flags
+
+	if err := config.InitConfig(flags); err != nil {
+		return err
+	}
+
+	logging.SetLevel("", levelFromName(action.config.LoggingLevel()))
+
+	return action.initPrinter()
+}
+
+// initPrinter creates the printer (and the file writer, if any) from the output flags
+func (action *Action) initPrinter() error {
+	var decoders *printer.DecoderRegistry
+	var err error
+	if action.config.Decoders() != "" {
+		decoders, err = printer.LoadDecoders(action.config.Decoders())
+		if err != nil {
+			return err
+		}
+	}
+
+	writerType := printer.AsWriterType(action.config.Writer())
+	if writerType == printer.FILE {
+		action.output, err = printer.NewFileWriter(&printer.FileWriterOpts{
+			Path:           printer.WriterPath(action.config.Writer()),
+			MaxSize:        action.config.RotateSize(),
+			RotateInterval: action.config.RotateInterval(),
+			Compress:       action.config.Compress(),
+			Fsync:          action.config.Fsync(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	action.printer = printer.NewBlockPrinterWithOpts(
-		printer.AsOutputFormat(cliconfig.Config().PrintFormat()),
-		printer.AsWriterType(cliconfig.Config().Writer()),
-		&printer.FormatterOpts{Base64Encode: cliconfig.Config().Base64()})
+		printer.AsOutputFormat(action.config.PrintFormat()),
+		writerType,
+		&printer.FormatterOpts{
+			Base64Encode:   action.config.Base64(),
+			DecodePayloads: action.config.DecodePayloads(),
+			MessageTypes:   action.config.MessageTypes(),
+			Decoders:       decoders,
+			Writer:         action.output,
+			Color:          !action.config.NoColor() && action.output == nil && printer.IsTerminal(writerType),
+			Depth:          action.config.Depth(),
+			Logger:         action.config.Logger(),
+		})
 
 	return nil
 }
 
+// preflight validates the connection profile before the SDK is initialized so that all of the
+// problems are reported at once. Errors in the entities that the command uses (see Problem.Fatal)
+// fail the command and all other problems are logged as warnings.
+func (action *Action) preflight() error {
+	if action.config.SkipPreflight() || action.config.ConfigFile() == "" {
+		return nil
+	}
+
+	problems, err := cliconfig.ValidateConnectionProfile(action.config.ConfigFile())
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, problem := range problems {
+		if problem.Fatal(action.config.ChannelID()) {
+			errs = append(errs, fmt.Sprintf("%s: %s", problem.Location(action.config.ConfigFile()), problem))
+		} else {
+			action.config.Logger().Warnf("%s: %s\n", problem.Location(action.config.ConfigFile()), problem)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("invalid connection profile [%s] (use --%s to skip this check):\n%s",
+			action.config.ConfigFile(), cliconfig.SkipPreflightFlag, strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
 // Terminate closes any open connections. This function should be called at the end of every command invocation.
 func (action *Action) Terminate() {
+	if action.healthMonitor != nil {
+		action.healthMonitor.Stop()
+	}
 	if action.sdk != nil {
-		cliconfig.Config().Logger().Info("Closing SDK")
+		action.config.Logger().Info("Closing SDK")
 		action.sdk.Close()
 	}
+	if c, ok := action.output.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			action.config.Logger().Errorf("Error closing output: %s", err)
+		}
+	}
+}
+
+// Config returns the CLI configuration
+func (action *Action) Config() *cliconfig.CLIConfig {
+	return action.config
 }
 
 // Flags returns the flag-set
@@ -196,15 +315,50 @@ func (action *Action) ChannelClient(...channel.ClientOption) (*channel.Client, e
 		return nil, errors.Errorf("error getting session for user [%s,%s]: %v", user.Identifier().MSPID, user.Identifier().ID, err)
 	}
 	channelProvider := func() (context.Channel, error) {
-		return contextImpl.NewChannel(session, cliconfig.Config().ChannelID())
+		return contextImpl.NewChannel(session, action.config.ChannelID())
 	}
 	return channel.New(channelProvider)
 }
 
+// ChannelClients returns a channel client for each of the identities in the manifest given by --identities
+// or, if no manifest is specified, a single channel client for the current user
+func (action *Action) ChannelClients() ([]*channel.Client, error) {
+	path := action.config.IdentityManifest()
+	if path == "" {
+		channelClient, err := action.ChannelClient()
+		if err != nil {
+			return nil, err
+		}
+		return []*channel.Client{channelClient}, nil
+	}
+
+	manifest, err := credential.LoadManifest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var channelClients []*channel.Client
+	for _, name := range manifest.Names() {
+		user, err := action.OrgUser(manifest.OrgID, name)
+		if err != nil {
+			return nil, errors.Errorf("error getting user [%s] from manifest [%s]: %s", name, path, err)
+		}
+		channelClient, err := action.ClientForUser(action.config.ChannelID(), user)
+		if err != nil {
+			return nil, err
+		}
+		channelClients = append(channelClients, channelClient)
+	}
+
+	action.config.Logger().Infof("Using %d identities from manifest [%s]\n", len(channelClients), path)
+
+	return channelClients, nil
+}
+
 // OrgAdminChannelClient creates a new channel client for the given org in order to perform administrative functions
 func (action *Action) OrgAdminChannelClient(orgID string) (*channel.Client, error) {
-	channelID := cliconfig.Config().ChannelID()
-	cliconfig.Config().Logger().Debugf("Creating new channel client for channel [%s] and org [%s] ...", channelID, orgID)
+	channelID := action.config.ChannelID()
+	action.config.Logger().Debugf("Creating new channel client for channel [%s] and org [%s] ...", channelID, orgID)
 
 	user, err := action.OrgAdminUser(orgID)
 	if err != nil {
@@ -244,12 +398,12 @@ func (action *Action) LocalContext() (context.Local, error) {
 
 // ChannelProvider returns the ChannelProvider
 func (action *Action) ChannelProvider() (context.ChannelProvider, error) {
-	channelID := cliconfig.Config().ChannelID()
+	channelID := action.config.ChannelID()
 	user, err := action.User()
 	if err != nil {
 		return nil, err
 	}
-	cliconfig.Config().Logger().Debugf("creating channel provider for user [%s] in org [%s]...", user.Identifier().ID, user.Identifier().MSPID)
+	action.config.Logger().Debugf("creating channel provider for user [%s] in org [%s]...", user.Identifier().ID, user.Identifier().MSPID)
 	clientContext, err := action.context(user)
 	if err != nil {
 		return nil, errors.Errorf("error getting client context for user [%s,%s]: %v", user.Identifier().MSPID, user.Identifier().ID, err)
@@ -338,7 +492,7 @@ func (action *Action) ResourceMgmtClientForOrg(orgID string) (*resmgmt.Client, e
 
 // ClientForUser returns the Channel client for the given user
 func (action *Action) ClientForUser(channelID string, user mspapi.SigningIdentity) (*channel.Client, error) {
-	cliconfig.Config().Logger().Debugf("create resmgmt client for user [%s] in org [%s]...", user.Identifier().ID, user.Identifier().MSPID)
+	action.config.Logger().Debugf("create resmgmt client for user [%s] in org [%s]...", user.Identifier().ID, user.Identifier().MSPID)
 	session, err := action.context(user)
 	if err != nil {
 		return nil, errors.Errorf("error getting session for user [%s,%s]: %v", user.Identifier().MSPID, user.Identifier().ID, err)
@@ -355,7 +509,7 @@ func (action *Action) ClientForUser(channelID string, user mspapi.SigningIdentit
 
 // ResourceMgmtClientForUser returns the Fabric client for the given user
 func (action *Action) ResourceMgmtClientForUser(user mspapi.SigningIdentity) (*resmgmt.Client, error) {
-	cliconfig.Config().Logger().Debugf("create resmgmt client for user [%s] in org [%s]...", user.Identifier().ID, user.Identifier().MSPID)
+	action.config.Logger().Debugf("create resmgmt client for user [%s] in org [%s]...", user.Identifier().ID, user.Identifier().MSPID)
 	session, err := action.context(user)
 	if err != nil {
 		return nil, errors.Errorf("error getting session for user [%s,%s]: %v", user.Identifier().MSPID, user.Identifier().ID, err)
@@ -369,7 +523,7 @@ func (action *Action) ResourceMgmtClientForUser(user mspapi.SigningIdentity) (*r
 
 // ChannelMgmtClientForUser returns the Fabric client for the given user
 func (action *Action) ChannelMgmtClientForUser(channelID string, user mspapi.SigningIdentity) (*channel.Client, error) {
-	cliconfig.Config().Logger().Debugf("create channel client for user [%s] in org [%s]...", user.Identifier().ID, user.Identifier().MSPID)
+	action.config.Logger().Debugf("create channel client for user [%s] in org [%s]...", user.Identifier().ID, user.Identifier().MSPID)
 	session, err := action.context(user)
 	if err != nil {
 		return nil, errors.Errorf("error getting session for user [%s,%s]: %v", user.Identifier().MSPID, user.Identifier().ID, err)
@@ -389,7 +543,7 @@ func (action *Action) context(user mspapi.SigningIdentity) (context.ClientProvid
 	session := action.sessions[key]
 	if session == nil {
 		session = action.sdk.Context(fabsdk.WithIdentity(user))
-		cliconfig.Config().Logger().Debugf("Created session for user [%s] in org [%s]", user.Identifier().ID, user.Identifier().MSPID)
+		action.config.Logger().Debugf("Created session for user [%s] in org [%s]", user.Identifier().ID, user.Identifier().MSPID)
 		action.sessions[key] = session
 	}
 	return session, nil
@@ -423,9 +577,13 @@ func (action *Action) GetOrgID(mspID string) (string, error) {
 	return "", errors.Errorf("unable to find org ID for MSP [%s]", mspID)
 }
 
-// User returns the enrolled user. If the user doesn't exist then a new user is enrolled.
+// User returns the signing identity given by --certfile/--keyfile, --pkcs12 or --mspdir or, if none
+// of these are specified, the enrolled user from the credential store.
 func (action *Action) User() (mspapi.SigningIdentity, error) {
-	userName := cliconfig.Config().UserName()
+	if action.credential != nil {
+		return action.credentialIdentity(action.OrgID())
+	}
+	userName := action.config.UserName()
 	if userName == "" {
 		userName = defaultUser
 	}
@@ -434,14 +592,14 @@ func (action *Action) User() (mspapi.SigningIdentity, error) {
 
 func (action *Action) newUser(orgID, username, pwd string) (mspapi.SigningIdentity, error) {
 
-	cliconfig.Config().Logger().Infof("Enrolling user %s...\n", username)
+	action.config.Logger().Infof("Enrolling user %s...\n", username)
 
-	mspClient, err := msp.New(action.sdk.Context(), msp.WithOrg(orgID))
+	mspClient, err := action.MSPClient(orgID)
 	if err != nil {
-		return nil, errors.Errorf("error creating MSP client: %s", err)
+		return nil, err
 	}
 
-	cliconfig.Config().Logger().Infof("Creating new user %s...\n", username)
+	action.config.Logger().Infof("Creating new user %s...\n", username)
 	err = mspClient.Enroll(username, msp.WithSecret(pwd))
 	if err != nil {
 		return nil, errors.Errorf("Enroll returned error: %v", err)
@@ -452,7 +610,7 @@ func (action *Action) newUser(orgID, username, pwd string) (mspapi.SigningIdenti
 		return nil, errors.Errorf("GetSigningIdentity returned error: %v", err)
 	}
 
-	cliconfig.Config().Logger().Infof("Returning user [%s], MSPID [%s]\n", user.Identifier().ID, user.Identifier().MSPID)
+	action.config.Logger().Infof("Returning user [%s], MSPID [%s]\n", user.Identifier().ID, user.Identifier().MSPID)
 
 	return user, nil
 }
@@ -462,9 +620,9 @@ func (action *Action) OrgUser(orgID, username string) (mspapi.SigningIdentity, e
 	if username == "" {
 		return nil, errors.Errorf("no username specified")
 	}
-	mspClient, err := msp.New(action.sdk.Context(), msp.WithOrg(orgID))
+	mspClient, err := action.MSPClient(orgID)
 	if err != nil {
-		return nil, errors.Errorf("error creating MSP client: %s", err)
+		return nil, err
 	}
 
 	user, err := mspClient.GetSigningIdentity(username)
@@ -472,20 +630,123 @@ func (action *Action) OrgUser(orgID, username string) (mspapi.SigningIdentity, e
 		return nil, errors.Errorf("GetSigningIdentity returned error: %v", err)
 	}
 
-	cliconfig.Config().Logger().Infof("Returning user [%s], MSPID [%s]\n", user.Identifier().ID, user.Identifier().MSPID)
+	action.config.Logger().Infof("Returning user [%s], MSPID [%s]\n", user.Identifier().ID, user.Identifier().MSPID)
 
 	return user, nil
 }
 
-// OrgAdminUser returns the pre-enrolled administrative user for the given organization
+// OrgAdminUser returns the pre-enrolled administrative user for the given organization. If the signing identity
+// is given by --certfile/--keyfile, --pkcs12 or --mspdir then that identity is used instead.
 func (action *Action) OrgAdminUser(orgID string) (mspapi.SigningIdentity, error) {
-	userName := cliconfig.Config().UserName()
+	if action.credential != nil {
+		return action.credentialIdentity(orgID)
+	}
+	userName := action.config.UserName()
 	if userName == "" {
 		userName = adminUser
 	}
 	return action.OrgUser(orgID, userName)
 }
 
+// MSPClient returns a client for the CA of the given organization
+func (action *Action) MSPClient(orgID string) (*msp.Client, error) {
+	mspClient, err := msp.New(action.sdk.Context(), msp.WithOrg(orgID))
+	if err != nil {
+		return nil, errors.Errorf("error creating MSP client: %s", err)
+	}
+	return mspClient, nil
+}
+
+// ClientContext returns the SDK's client context, which provides the crypto suite and configuration
+func (action *Action) ClientContext() (context.Client, error) {
+	return action.sdk.Context()()
+}
+
+// credentialIdentity returns a signing identity in the given organization for the loaded credential
+func (action *Action) credentialIdentity(orgID string) (mspapi.SigningIdentity, error) {
+	if user, ok := action.identities[orgID]; ok {
+		return user, nil
+	}
+
+	if err := action.checkCredentialIssuer(orgID); err != nil {
+		return nil, err
+	}
+
+	mspClient, err := action.MSPClient(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := mspClient.CreateSigningIdentity(mspapi.WithCert(action.credential.Cert), mspapi.WithPrivateKey(action.credential.Key))
+	if err != nil {
+		return nil, errors.Errorf("error creating signing identity: %v", err)
+	}
+
+	action.config.Logger().Infof("Returning signing identity [%s], MSPID [%s]\n", user.Identifier().ID, user.Identifier().MSPID)
+
+	action.identities[orgID] = user
+	return user, nil
+}
+
+// checkCredentialIssuer returns an error if the certificate of the loaded credential wasn't issued by the CA of
+// the given organization, since the peers would reject its signatures. The CA certificates are read from the
+// organization's cryptoPath in the connection profile (for any user). If they aren't found then a warning is logged.
+func (action *Action) checkCredentialIssuer(orgID string) error {
+	orgConfig, ok := action.endpointConfig.NetworkConfig().Organizations[strings.ToLower(orgID)]
+	if !ok {
+		return errors.Errorf("organization [%s] not found in the connection profile", orgID)
+	}
+
+	var caCerts []*x509.Certificate
+	if orgConfig.CryptoPath != "" {
+		pattern := strings.NewReplacer("{userName}", "*", "{username}", "*").Replace(orgConfig.CryptoPath)
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(action.endpointConfig.CryptoConfigPath(), pattern)
+		}
+		var err error
+		if caCerts, err = credential.MSPCACerts(pattern); err != nil {
+			return err
+		}
+	}
+
+	if len(caCerts) == 0 {
+		action.config.Logger().Warnf("Unable to verify that the signing identity belongs to MSP [%s] of organization [%s] since its CA certificates weren't found\n", orgConfig.MSPID, orgID)
+		return nil
+	}
+
+	if err := action.credential.CheckIssuer(caCerts); err != nil {
+		return errors.WithMessagef(err, "the signing identity can't be used for organization [%s] (MSP [%s])", orgID, orgConfig.MSPID)
+	}
+	return nil
+}
+
+// credentialSource returns the source of the signing identity specified by the flags or nil if none was specified
+func credentialSource(config *cliconfig.CLIConfig) (credential.Source, error) {
+	var sources []credential.Source
+	if config.SigningCert() != "" || config.SigningKey() != "" {
+		if config.SigningCert() == "" || config.SigningKey() == "" {
+			return nil, errors.Errorf("--%s and --%s must be specified together", cliconfig.SigningCertFlag, cliconfig.SigningKeyFlag)
+		}
+		sources = append(sources, credential.FromPEM(config.SigningCert(), config.SigningKey(), credential.EnvOrPrompt))
+	}
+	if config.PKCS12() != "" {
+		sources = append(sources, credential.FromPKCS12(config.PKCS12(), credential.EnvOrPrompt))
+	}
+	if config.MSPDir() != "" {
+		sources = append(sources, credential.FromMSPDir(config.MSPDir(), credential.EnvOrPrompt))
+	}
+
+	switch len(sources) {
+	case 0:
+		return nil, nil
+	case 1:
+		return sources[0], nil
+	default:
+		return nil, errors.Errorf("only one of --%s/--%s, --%s or --%s may be specified",
+			cliconfig.SigningCertFlag, cliconfig.SigningKeyFlag, cliconfig.PKCS12Flag, cliconfig.MSPDirFlag)
+	}
+}
+
 // PeerFromURL returns the peer for the given URL
 func (action *Action) PeerFromURL(url string) (fab.Peer, bool) {
 	for _, peer := range action.peers {
@@ -499,7 +760,7 @@ func (action *Action) PeerFromURL(url string) (fab.Peer, bool) {
 // Orderers returns all Orderers from the set of configured Orderers
 func (action *Action) Orderers() ([]fab.Orderer, error) {
 	ordererConfigs := action.endpointConfig.OrderersConfig()
-	ordererURL := cliconfig.Config().OrdererURL()
+	ordererURL := action.config.OrdererURL()
 
 	var orderers []fab.Orderer
 	for _, ordererConfig := range ordererConfigs {
@@ -528,10 +789,15 @@ func (action *Action) RandomOrderer() (fab.Orderer, error) {
 }
 
 // ArgsArray returns an array of args used in chaincode invocations
-func ArgsArray() ([]ArgStruct, error) {
+func (action *Action) ArgsArray() ([]ArgStruct, error) {
+	return ParseArgs(action.config.Args())
+}
+
+// ParseArgs parses the given JSON string, which is either a single ArgStruct or an array of them
+func ParseArgs(args string) ([]ArgStruct, error) {
 	var argsArray []ArgStruct
-	argBytes := []byte(cliconfig.Config().Args())
-	if strings.HasPrefix(cliconfig.Config().Args(), "[") {
+	argBytes := []byte(args)
+	if strings.HasPrefix(args, "[") {
 		if err := json.Unmarshal(argBytes, &argsArray); err != nil {
 			return nil, errors.Errorf("Error unmarshaling JSON arg string: %v", err)
 		}
@@ -588,7 +854,7 @@ func (action *Action) getPeers(allPeers []fab.Peer, peerURLs []string, orgIDs []
 func (action *Action) PeerConfig() (*fab.PeerConfig, error) {
 	peersConfig, ok := action.endpointConfig.PeersConfig(action.OrgID())
 	if !ok {
-		return nil, errors.Errorf("Error reading peers config for %s: %v", action.OrgID())
+		return nil, errors.Errorf("Error reading peers config for %s", action.OrgID())
 	}
 
 	peer := action.Peer()
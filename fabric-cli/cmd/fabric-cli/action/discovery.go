@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package action
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	corecomm "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/pkg/errors"
+)
+
+// Discover sends the given queries to the discovery service of the first peer (see Peer) and returns the
+// results in the order of the queries. The queries are signed by the user (see User). The raw results are
+// returned since the SDK's discovery client only exposes the results that it uses for endorser selection.
+func (action *Action) Discover(ctx context.Context, queries ...*discovery.Query) ([]*discovery.QueryResult, error) {
+	peer := action.Peer()
+	if peer == nil {
+		return nil, errors.New("a peer must be specified")
+	}
+
+	peerConfig, ok := action.endpointConfig.PeerConfig(peer.URL())
+	if !ok {
+		return nil, errors.Errorf("peer config not found for [%s]", peer.URL())
+	}
+
+	user, err := action.User()
+	if err != nil {
+		return nil, errors.Errorf("error getting user: %s", err)
+	}
+
+	session, err := action.context(user)
+	if err != nil {
+		return nil, errors.Errorf("error getting session for user [%s,%s]: %v", user.Identifier().MSPID, user.Identifier().ID, err)
+	}
+
+	clientCtx, err := session()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating client context")
+	}
+
+	identity, err := clientCtx.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error serializing identity")
+	}
+
+	tlsCertHash, err := corecomm.TLSCertHash(action.endpointConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error computing TLS certificate hash")
+	}
+
+	payload, err := proto.Marshal(&discovery.Request{
+		Authentication: &discovery.AuthInfo{
+			ClientIdentity:    identity,
+			ClientTlsCertHash: tlsCertHash,
+		},
+		Queries: queries,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling discovery request")
+	}
+
+	signature, err := clientCtx.SigningManager().Sign(payload, clientCtx.PrivateKey())
+	if err != nil {
+		return nil, errors.WithMessage(err, "error signing discovery request")
+	}
+
+	opts := comm.OptsFromPeerConfig(peerConfig)
+	opts = append(opts, comm.WithConnectTimeout(action.endpointConfig.Timeout(fab.DiscoveryConnection)))
+	opts = append(opts, comm.WithParentContext(ctx))
+
+	conn, err := comm.NewConnection(clientCtx, peerConfig.URL, opts...)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error connecting to peer [%s]", peer.URL())
+	}
+	defer conn.Close()
+
+	response, err := discovery.NewDiscoveryClient(conn.ClientConn()).Discover(ctx, &discovery.SignedRequest{
+		Payload:   payload,
+		Signature: signature,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "discovery request to peer [%s] failed", peer.URL())
+	}
+
+	if len(response.Results) != len(queries) {
+		return nil, errors.Errorf("sent %d queries but received %d results", len(queries), len(response.Results))
+	}
+
+	return response.Results, nil
+}
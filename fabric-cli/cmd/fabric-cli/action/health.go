@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package action
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/health"
+)
+
+// NewHealthMonitor returns a health monitor for the given peers. A peer is healthy if it can be
+// connected to and responds to a query of the channel's blockchain info made by the current user,
+// so that no administrator credentials are needed for the checks.
+func (action *Action) NewHealthMonitor(peers []fab.Peer) (*health.Monitor, error) {
+	// The client is created up front since the checks are made concurrently
+	client, err := action.LedgerClient()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating client for health checks")
+	}
+
+	checker := func(ctx context.Context, peer fab.Peer) error {
+		_, err := client.QueryInfo(
+			ledger.WithTargets(peer),
+			ledger.WithParentContext(ctx),
+		)
+		return err
+	}
+
+	return health.New(action.config, peers, checker, action.config.HealthInterval(), action.config.Timeout(fab.PeerConnection)), nil
+}
+
+// StartHealthMonitor starts monitoring the health of the selected peers if --healthinterval is specified.
+// From then on Targets excludes the peers that are down. The monitor is stopped by Terminate.
+func (action *Action) StartHealthMonitor() error {
+	if action.config.HealthInterval() <= 0 || action.healthMonitor != nil {
+		return nil
+	}
+
+	monitor, err := action.NewHealthMonitor(action.peers)
+	if err != nil {
+		return err
+	}
+	monitor.Start()
+	action.healthMonitor = monitor
+
+	return nil
+}
+
+// Targets returns the selected peers excluding those that the health monitor has marked down
+func (action *Action) Targets() []fab.Peer {
+	if action.healthMonitor == nil {
+		return action.peers
+	}
+	return action.healthMonitor.Healthy(action.peers)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/fabricselection"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/staticselection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -17,19 +18,23 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/chpvdr"
 	"github.com/pkg/errors"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/selection"
 )
 
-// serviceProviderFactory is configured with either static or dynamic selection provider
+// serviceProviderFactory is configured with either the static, dynamic, fabric or rules selection provider.
+// In verbose mode the endorsers chosen by the selection provider are output for each request.
 type serviceProviderFactory struct {
 	defsvc.ProviderFactory
+	config *cliconfig.CLIConfig
 }
 
-func newServiceProviderFactory() (*serviceProviderFactory, error) {
-	return &serviceProviderFactory{}, nil
+func newServiceProviderFactory(config *cliconfig.CLIConfig) (*serviceProviderFactory, error) {
+	return &serviceProviderFactory{config: config}, nil
 }
 
 type fabricSelectionChannelProvider struct {
 	fab.ChannelProvider
+	config    *cliconfig.CLIConfig
 	service   fab.ChannelService
 	selection fab.SelectionService
 }
@@ -47,6 +52,7 @@ func (f *serviceProviderFactory) CreateChannelProvider(config fab.EndpointConfig
 	}
 	return &fabricSelectionChannelProvider{
 		ChannelProvider: chProvider,
+		config:          f.config,
 	}, nil
 }
 
@@ -84,37 +90,107 @@ func (cp *fabricSelectionChannelProvider) ChannelService(ctx fab.ClientContext,
 		return nil, err
 	}
 
-	discovery, err := chService.Discovery()
+	selectionService, err := cp.selectionService(ctx, channelID, chService)
 	if err != nil {
 		return nil, err
 	}
 
-	if cp.selection == nil {
-		switch cliconfig.Config().SelectionProvider() {
-		case cliconfig.StaticSelectionProvider:
-			cliconfig.Config().Logger().Debugf("Using static selection provider.")
-			cp.selection, err = staticselection.NewService(discovery)
-		case cliconfig.DynamicSelectionProvider:
-			cliconfig.Config().Logger().Debugf("Using dynamic selection provider.")
-			cp.selection, err = dynamicselection.NewService(ctx, channelID, discovery)
-		case cliconfig.FabricSelectionProvider:
-			cliconfig.Config().Logger().Debugf("Using Fabric selection provider.")
-			cp.selection, err = fabricselection.New(ctx, channelID, discovery)
-		default:
-			return nil, errors.Errorf("invalid selection provider: %s", cliconfig.Config().SelectionProvider())
-		}
-
-		if err != nil {
-			return nil, err
-		}
+	if cp.config.Verbose() {
+		selectionService = &tracingSelectionService{SelectionService: selectionService, logger: cp.config.Logger()}
 	}
 
 	return &fabricSelectionChannelService{
 		ChannelService: chService,
-		selection:      cp.selection,
+		selection:      selectionService,
 	}, nil
 }
 
+// selectionService returns the selection service of the configured selection provider. The auto-detected
+// selection service is created by the SDK's channel service.
+func (cp *fabricSelectionChannelProvider) selectionService(ctx fab.ClientContext, channelID string, chService fab.ChannelService) (fab.SelectionService, error) {
+	if cp.config.SelectionProvider() == cliconfig.AutoDetectSelectionProvider {
+		return chService.Selection()
+	}
+
+	if cp.selection != nil {
+		return cp.selection, nil
+	}
+
+	discovery, err := chService.Discovery()
+	if err != nil {
+		return nil, err
+	}
+
+	switch cp.config.SelectionProvider() {
+	case cliconfig.StaticSelectionProvider:
+		cp.config.Logger().Debugf("Using static selection provider.")
+		cp.selection, err = staticselection.NewService(discovery)
+	case cliconfig.DynamicSelectionProvider:
+		cp.config.Logger().Debugf("Using dynamic selection provider.")
+		cp.selection, err = dynamicselection.NewService(ctx, channelID, discovery)
+	case cliconfig.FabricSelectionProvider:
+		cp.config.Logger().Debugf("Using Fabric selection provider.")
+		cp.selection, err = fabricselection.New(ctx, channelID, discovery)
+	case cliconfig.RulesSelectionProvider:
+		cp.config.Logger().Debugf("Using rules selection provider.")
+		cp.selection, err = newRulesSelectionService(ctx, cp.config, discovery)
+	default:
+		return nil, errors.Errorf("invalid selection provider: %s", cp.config.SelectionProvider())
+	}
+
+	return cp.selection, err
+}
+
+func newRulesSelectionService(ctx fab.ClientContext, config *cliconfig.CLIConfig, discovery fab.DiscoveryService) (fab.SelectionService, error) {
+	if config.SelectionRules() == "" {
+		return nil, errors.Errorf("--%s must be specified for the %s selection provider", cliconfig.SelectionRulesFlag, cliconfig.RulesSelectionProvider)
+	}
+
+	rules, err := selection.LoadRules(config.SelectionRules())
+	if err != nil {
+		return nil, err
+	}
+
+	mspIDs := make(map[string]string)
+	for orgID, orgConfig := range ctx.EndpointConfig().NetworkConfig().Organizations {
+		mspIDs[orgID] = orgConfig.MSPID
+	}
+
+	return selection.NewService(discovery, rules, mspIDs)
+}
+
 func (cs *fabricSelectionChannelService) Selection() (fab.SelectionService, error) {
 	return cs.selection, nil
 }
+
+// tracingSelectionService logs the endorsers that are chosen for each request
+type tracingSelectionService struct {
+	fab.SelectionService
+	logger *logging.Logger
+}
+
+func (s *tracingSelectionService) GetEndorsersForChaincode(chaincodes []*fab.ChaincodeCall, opts ...options.Opt) ([]fab.Peer, error) {
+	var ccIDs []string
+	for _, cc := range chaincodes {
+		ccIDs = append(ccIDs, cc.ID)
+	}
+
+	peers, err := s.SelectionService.GetEndorsersForChaincode(chaincodes, opts...)
+	if err != nil {
+		s.logger.Warnf("Error selecting endorsers for %s: %s", ccIDs, err)
+		return nil, err
+	}
+
+	var urls []string
+	for _, peer := range peers {
+		urls = append(urls, peer.URL())
+	}
+	s.logger.Infof("Selected endorsers for %s: %s", ccIDs, urls)
+
+	return peers, nil
+}
+
+// Untraced returns the selection service without tracing
+func (s *tracingSelectionService) Untraced() fab.SelectionService {
+	return s.SelectionService
+}
@@ -0,0 +1,250 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package archive stores blocks exported from a channel in a directory, one file per block and format,
+// or in a gzipped tar archive. Blocks are written atomically so that an export which was interrupted
+// may be resumed by writing only the blocks that are missing. Blocks may be read back from an export,
+// from a peer's ledger block files or from a single block file (see ReadBlocks).
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Format is the format in which blocks are written
+type Format string
+
+const (
+	// Proto writes each block as a raw protobuf (.pb) file
+	Proto Format = "proto"
+
+	// JSON writes each block as a JSON (.json) file
+	JSON Format = "json"
+
+	// Both writes each block as both a protobuf and a JSON file
+	Both Format = "both"
+)
+
+const (
+	// ProtoExt is the file extension of blocks written as raw protobuf
+	ProtoExt = ".pb"
+
+	// JSONExt is the file extension of blocks written as JSON
+	JSONExt = ".json"
+
+	// partialSuffix is appended to the path of an archive to get the staging directory
+	partialSuffix = ".partial"
+)
+
+// AsFormat returns the Format given a format string
+func AsFormat(f string) (Format, error) {
+	switch format := Format(strings.ToLower(f)); format {
+	case Proto, JSON, Both:
+		return format, nil
+	default:
+		return "", errors.Errorf("invalid block format [%s] - must be proto, json or both", f)
+	}
+}
+
+// Extensions returns the file extensions that are written for the format
+func (f Format) Extensions() []string {
+	switch f {
+	case JSON:
+		return []string{JSONExt}
+	case Both:
+		return []string{ProtoExt, JSONExt}
+	default:
+		return []string{ProtoExt}
+	}
+}
+
+// IsArchive returns true if the path is that of a gzipped tar archive (.tar.gz or .tgz)
+func IsArchive(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// StagingDir returns the directory in which blocks are written before they're packed into
+// the given archive. The directory is kept if the export doesn't complete so that it may be resumed.
+func StagingDir(archivePath string) string {
+	return archivePath + partialSuffix
+}
+
+// FileName returns the name of the file of the given block and extension. Block numbers
+// are zero-padded so that the files sort in block order.
+func FileName(num uint64, ext string) string {
+	return fmt.Sprintf("block_%010d%s", num, ext)
+}
+
+// Dir is a directory of exported blocks
+type Dir struct {
+	path   string
+	format Format
+}
+
+// NewDir returns a Dir for the given path, creating the directory if it doesn't exist
+func NewDir(path string, format Format) (*Dir, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, errors.Wrapf(err, "error creating directory [%s]", path)
+	}
+	return &Dir{path: path, format: format}, nil
+}
+
+// Path returns the path of the directory
+func (d *Dir) Path() string {
+	return d.path
+}
+
+// Format returns the format in which blocks are written
+func (d *Dir) Format() Format {
+	return d.format
+}
+
+// Contains returns true if all of the files of the given block have been written
+func (d *Dir) Contains(num uint64) bool {
+	for _, ext := range d.format.Extensions() {
+		if _, err := os.Stat(filepath.Join(d.path, FileName(num, ext))); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Missing returns the numbers of the blocks in the range [from, to] that haven't been written
+func (d *Dir) Missing(from, to uint64) []uint64 {
+	var missing []uint64
+	for num := from; num <= to; num++ {
+		if !d.Contains(num) {
+			missing = append(missing, num)
+		}
+		if num == to {
+			// Avoid overflow if to is the maximum uint64
+			break
+		}
+	}
+	return missing
+}
+
+// Write writes the file of the given block and extension. The data is written to a temporary
+// file which is then renamed so that a partially written file is never mistaken for a block.
+func (d *Dir) Write(num uint64, ext string, data []byte) error {
+	path := filepath.Join(d.path, FileName(num, ext))
+
+	tmp, err := ioutil.TempFile(d.path, FileName(num, ext)+".tmp")
+	if err != nil {
+		return errors.Wrapf(err, "error creating temporary file for [%s]", path)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "error writing [%s]", path)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "error closing [%s]", path)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrapf(err, "error renaming temporary file to [%s]", path)
+	}
+	return nil
+}
+
+// Pack writes the block files in the directory to a gzipped tar archive at the given path.
+// Temporary files that were left by an interrupted write are skipped.
+func (d *Dir) Pack(archivePath string) error {
+	infos, err := ioutil.ReadDir(d.path)
+	if err != nil {
+		return errors.Wrapf(err, "error reading directory [%s]", d.path)
+	}
+
+	var names []string
+	for _, info := range infos {
+		ext := filepath.Ext(info.Name())
+		if info.Mode().IsRegular() && (ext == ProtoExt || ext == JSONExt) {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+
+	tmpPath := archivePath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return errors.Wrapf(err, "error creating archive [%s]", tmpPath)
+	}
+
+	if err := d.writeArchive(f, names); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrapf(err, "error closing archive [%s]", tmpPath)
+	}
+
+	if err := os.Rename(tmpPath, archivePath); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrapf(err, "error renaming archive to [%s]", archivePath)
+	}
+	return nil
+}
+
+func (d *Dir) writeArchive(w io.Writer, names []string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, name := range names {
+		if err := d.addFile(tw, name); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "error closing tar writer")
+	}
+	if err := gw.Close(); err != nil {
+		return errors.Wrap(err, "error closing gzip writer")
+	}
+	return nil
+}
+
+func (d *Dir) addFile(tw *tar.Writer, name string) error {
+	f, err := os.Open(filepath.Join(d.path, name))
+	if err != nil {
+		return errors.Wrapf(err, "error opening [%s]", name)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "error getting info of [%s]", name)
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return errors.Wrapf(err, "error creating tar header for [%s]", name)
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return errors.Wrapf(err, "error writing tar header for [%s]", name)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return errors.Wrapf(err, "error adding [%s] to archive", name)
+	}
+	return nil
+}
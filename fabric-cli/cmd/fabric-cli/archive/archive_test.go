@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsFormat(t *testing.T) {
+	format, err := AsFormat("JSON")
+	require.NoError(t, err)
+	assert.Equal(t, JSON, format)
+	assert.Equal(t, []string{ProtoExt, JSONExt}, Both.Extensions())
+
+	_, err = AsFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestDir(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	dir, err := NewDir(filepath.Join(tmp, "blocks"), Both)
+	require.NoError(t, err)
+
+	require.NoError(t, dir.Write(1, ProtoExt, []byte("block1")))
+	require.NoError(t, dir.Write(1, JSONExt, []byte("{}")))
+	require.NoError(t, dir.Write(2, ProtoExt, []byte("block2")))
+
+	assert.True(t, dir.Contains(1))
+	assert.False(t, dir.Contains(2), "block 2 is missing its JSON file")
+	assert.Equal(t, []uint64{0, 2, 3}, dir.Missing(0, 3))
+
+	archivePath := filepath.Join(tmp, "blocks.tar.gz")
+	require.True(t, IsArchive(archivePath))
+	require.NoError(t, dir.Pack(archivePath))
+
+	f, err := os.Open(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, header.Name)
+	}
+	assert.Equal(t, []string{FileName(1, JSONExt), FileName(1, ProtoExt), FileName(2, ProtoExt)}, names)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/pkg/errors"
+)
+
+// blockFilePrefix is the prefix of the names of the block files in a peer's or orderer's ledger directory
+const blockFilePrefix = "blockfile_"
+
+// ReadBlocks reads the blocks at the given path and invokes fn with each block in order.
+// The path may be one of the following:
+// - a directory of blocks exported by 'query blocks' (the .pb files are read)
+// - a gzipped tar archive of blocks exported by 'query blocks'
+// - a block file from the ledger directory of a peer or orderer (blockfile_nnnnnn)
+// - a file containing a single marshalled block, e.g. a genesis or config .block file
+// Reading stops at the first error returned by fn.
+func ReadBlocks(path string, fn func(block *common.Block) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrapf(err, "error reading [%s]", path)
+	}
+
+	switch {
+	case info.IsDir():
+		return readDir(path, fn)
+	case IsArchive(path):
+		return readArchive(path, fn)
+	case strings.HasPrefix(filepath.Base(path), blockFilePrefix):
+		return readBlockFile(path, fn)
+	default:
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "error reading [%s]", path)
+		}
+		block, err := unmarshalBlock(data)
+		if err != nil {
+			return errors.WithMessagef(err, "error reading [%s]", path)
+		}
+		return fn(block)
+	}
+}
+
+func readDir(path string, fn func(block *common.Block) error) error {
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return errors.Wrapf(err, "error reading directory [%s]", path)
+	}
+
+	var names []string
+	for _, info := range infos {
+		if info.Mode().IsRegular() && filepath.Ext(info.Name()) == ProtoExt {
+			names = append(names, info.Name())
+		}
+	}
+	if len(names) == 0 {
+		return errors.Errorf("no %s block files found in [%s]", ProtoExt, path)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return errors.Wrapf(err, "error reading [%s]", name)
+		}
+		block, err := unmarshalBlock(data)
+		if err != nil {
+			return errors.WithMessagef(err, "error reading [%s]", name)
+		}
+		if err := fn(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readArchive(path string, fn func(block *common.Block) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "error opening archive [%s]", path)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return errors.Wrapf(err, "error reading archive [%s]", path)
+	}
+	defer gr.Close()
+
+	// The entries are written in order by Dir.Pack
+	found := false
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrapf(err, "error reading archive [%s]", path)
+		}
+		if filepath.Ext(header.Name) != ProtoExt {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return errors.Wrapf(err, "error reading [%s] from archive", header.Name)
+		}
+		block, err := unmarshalBlock(data)
+		if err != nil {
+			return errors.WithMessagef(err, "error reading [%s] from archive", header.Name)
+		}
+		found = true
+		if err := fn(block); err != nil {
+			return err
+		}
+	}
+
+	if !found {
+		return errors.Errorf("no %s block files found in archive [%s]", ProtoExt, path)
+	}
+	return nil
+}
+
+// readBlockFile reads a ledger block file. Each block in the file is prefixed with its length
+// (a varint) and is serialized in Fabric's block storage format rather than as a protobuf message.
+// A block that was only partly written, e.g. by a peer that crashed, results in an error after
+// the preceding blocks have been read.
+func readBlockFile(path string, fn func(block *common.Block) error) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "error reading block file [%s]", path)
+	}
+
+	offset := 0
+	for offset < len(data) {
+		length, n := proto.DecodeVarint(data[offset:])
+		if n == 0 {
+			return errors.Errorf("block file [%s] is truncated at offset %d: invalid block length", path, offset)
+		}
+		if uint64(len(data)-offset-n) < length {
+			return errors.Errorf("block file [%s] is truncated at offset %d: expecting %d bytes but only %d remain", path, offset, length, len(data)-offset-n)
+		}
+
+		start := offset + n
+		block, err := deserializeBlock(data[start : start+int(length)])
+		if err != nil {
+			return errors.WithMessagef(err, "error reading block at offset %d of block file [%s]", offset, path)
+		}
+		if err := fn(block); err != nil {
+			return err
+		}
+
+		offset = start + int(length)
+	}
+	return nil
+}
+
+// deserializeBlock decodes a block that was serialized by Fabric's block storage: the header
+// (number, data hash and previous hash), the number of transactions followed by each transaction
+// envelope and the number of metadata entries followed by each entry
+func deserializeBlock(data []byte) (*common.Block, error) {
+	buf := proto.NewBuffer(data)
+
+	header := &common.BlockHeader{}
+	var err error
+	if header.Number, err = buf.DecodeVarint(); err != nil {
+		return nil, errors.Wrap(err, "error decoding block number")
+	}
+	if header.DataHash, err = buf.DecodeRawBytes(false); err != nil {
+		return nil, errors.Wrap(err, "error decoding data hash")
+	}
+	if header.PreviousHash, err = buf.DecodeRawBytes(false); err != nil {
+		return nil, errors.Wrap(err, "error decoding previous hash")
+	}
+	if len(header.PreviousHash) == 0 {
+		header.PreviousHash = nil
+	}
+
+	blockData, err := decodeByteSlices(buf)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error decoding block data")
+	}
+	metadata, err := decodeByteSlices(buf)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error decoding block metadata")
+	}
+
+	return &common.Block{
+		Header:   header,
+		Data:     &common.BlockData{Data: blockData},
+		Metadata: &common.BlockMetadata{Metadata: metadata},
+	}, nil
+}
+
+// decodeByteSlices decodes a count followed by that number of length-prefixed byte slices
+func decodeByteSlices(buf *proto.Buffer) ([][]byte, error) {
+	count, err := buf.DecodeVarint()
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding count")
+	}
+
+	var items [][]byte
+	for i := uint64(0); i < count; i++ {
+		item, err := buf.DecodeRawBytes(false)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error decoding item %d of %d", i, count)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func unmarshalBlock(data []byte) (*common.Block, error) {
+	block := &common.Block{}
+	if err := proto.Unmarshal(data, block); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling block")
+	}
+	if block.Header == nil {
+		return nil, errors.New("not a block: the block header is missing")
+	}
+	return block, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBlocks(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	block0 := newBlock(0, nil, "genesis")
+	block1 := newBlock(1, []byte("hash0"), "tx1", "tx2")
+
+	t.Run("Block file", func(t *testing.T) {
+		path := filepath.Join(tmp, "blockfile_000000")
+		data := append(serializeBlock(block0), serializeBlock(block1)...)
+		require.NoError(t, ioutil.WriteFile(path, data, 0644))
+
+		assert.Equal(t, []uint64{0, 1}, readNumbers(t, path))
+
+		// A block that was only partly written
+		require.NoError(t, ioutil.WriteFile(path, data[:len(data)-3], 0644))
+		var numbers []uint64
+		err := ReadBlocks(path, func(block *common.Block) error {
+			assert.Equal(t, [][]byte{[]byte("genesis")}, block.Data.Data)
+			numbers = append(numbers, block.Header.Number)
+			return nil
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "truncated")
+		assert.Equal(t, []uint64{0}, numbers)
+	})
+
+	t.Run("Single block", func(t *testing.T) {
+		path := filepath.Join(tmp, "genesis.block")
+		data, err := proto.Marshal(block0)
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(path, data, 0644))
+
+		assert.Equal(t, []uint64{0}, readNumbers(t, path))
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		dir, err := NewDir(filepath.Join(tmp, "blocks"), Both)
+		require.NoError(t, err)
+		for _, block := range []*common.Block{block1, block0} {
+			data, err := proto.Marshal(block)
+			require.NoError(t, err)
+			require.NoError(t, dir.Write(block.Header.Number, ProtoExt, data))
+			require.NoError(t, dir.Write(block.Header.Number, JSONExt, []byte("{}")))
+		}
+
+		assert.Equal(t, []uint64{0, 1}, readNumbers(t, dir.Path()))
+
+		archivePath := filepath.Join(tmp, "blocks.tgz")
+		require.NoError(t, dir.Pack(archivePath))
+		assert.Equal(t, []uint64{0, 1}, readNumbers(t, archivePath))
+	})
+}
+
+func readNumbers(t *testing.T, path string) []uint64 {
+	var numbers []uint64
+	require.NoError(t, ReadBlocks(path, func(block *common.Block) error {
+		numbers = append(numbers, block.Header.Number)
+		return nil
+	}))
+	return numbers
+}
+
+func newBlock(num uint64, previousHash []byte, txs ...string) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: num, PreviousHash: previousHash, DataHash: []byte("datahash")},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{Metadata: [][]byte{[]byte("signatures"), {}}},
+	}
+	for _, tx := range txs {
+		block.Data.Data = append(block.Data.Data, []byte(tx))
+	}
+	return block
+}
+
+// serializeBlock serializes a block in the same way as Fabric's block storage, prefixed with its length
+func serializeBlock(block *common.Block) []byte {
+	buf := proto.NewBuffer(nil)
+	buf.EncodeVarint(block.Header.Number)
+	buf.EncodeRawBytes(block.Header.DataHash)
+	buf.EncodeRawBytes(block.Header.PreviousHash)
+	buf.EncodeVarint(uint64(len(block.Data.Data)))
+	for _, data := range block.Data.Data {
+		buf.EncodeRawBytes(data)
+	}
+	buf.EncodeVarint(uint64(len(block.Metadata.Metadata)))
+	for _, metadata := range block.Metadata.Metadata {
+		buf.EncodeRawBytes(metadata)
+	}
+	return append(proto.EncodeVarint(uint64(len(buf.Bytes()))), buf.Bytes()...)
+}
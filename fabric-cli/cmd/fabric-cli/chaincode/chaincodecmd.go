@@ -11,25 +11,25 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var chaincodeCmd = &cobra.Command{
-	Use:   "chaincode",
-	Short: "Chaincode commands",
-	Long:  "Chaincode commands",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.HelpFunc()(cmd, args)
-	},
-}
-
 // Cmd returns the chaincode command
-func Cmd() *cobra.Command {
-	cliconfig.InitChannelID(chaincodeCmd.Flags())
+func Cmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	chaincodeCmd := &cobra.Command{
+		Use:   "chaincode",
+		Short: "Chaincode commands",
+		Long:  "Chaincode commands",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	cfg.InitChannelID(chaincodeCmd.Flags())
 
-	chaincodeCmd.AddCommand(getInstallCmd())
-	chaincodeCmd.AddCommand(getInstantiateCmd())
-	chaincodeCmd.AddCommand(getInvokeCmd())
-	chaincodeCmd.AddCommand(getQueryCmd())
-	chaincodeCmd.AddCommand(getGetInfoCmd())
-	chaincodeCmd.AddCommand(getUpgradeCmd())
+	chaincodeCmd.AddCommand(getInstallCmd(cfg))
+	chaincodeCmd.AddCommand(getInstantiateCmd(cfg))
+	chaincodeCmd.AddCommand(getInvokeCmd(cfg))
+	chaincodeCmd.AddCommand(getQueryCmd(cfg))
+	chaincodeCmd.AddCommand(getGetInfoCmd(cfg))
+	chaincodeCmd.AddCommand(getUpgradeCmd(cfg))
 
 	return chaincodeCmd
 }
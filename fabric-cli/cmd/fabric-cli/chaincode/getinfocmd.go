@@ -28,36 +28,36 @@ const (
 	getCollConfigFunc = "getcollectionsconfig"
 )
 
-var getInfoCmd = &cobra.Command{
-	Use:   "info",
-	Short: "Get chaincode info",
-	Long:  "Retrieves details about the chaincode",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cliconfig.Config().ChaincodeID() == "" {
-			fmt.Printf("\nMust specify the chaincode ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		action, err := newGetInfoAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing getAction: %v", err)
-			return
-		}
-
-		defer action.Terminate()
-
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running getAction: %v", err)
-		}
-	},
-}
+func getGetInfoCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	getInfoCmd := &cobra.Command{
+		Use:   "info",
+		Short: "Get chaincode info",
+		Long:  "Retrieves details about the chaincode",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChaincodeID() == "" {
+				fmt.Printf("\nMust specify the chaincode ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			action, err := newGetInfoAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing getAction: %v", err)
+				return
+			}
+
+			defer action.Terminate()
+
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running getAction: %v", err)
+			}
+		},
+	}
 
-func getGetInfoCmd() *cobra.Command {
 	flags := getInfoCmd.Flags()
-	cliconfig.InitPeerURL(flags)
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitChaincodeID(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitChaincodeID(flags)
 	return getInfoCmd
 }
 
@@ -65,9 +65,9 @@ type getInfoAction struct {
 	action.Action
 }
 
-func newGetInfoAction(flags *pflag.FlagSet) (*getInfoAction, error) {
+func newGetInfoAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*getInfoAction, error) {
 	action := &getInfoAction{}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	if len(action.Peers()) == 0 {
 		return nil, errors.New("a peer must be specified")
 	}
@@ -99,11 +99,11 @@ func (action *getInfoAction) invoke() error {
 
 func (action *getInfoAction) getCCData(channelClient *channel.Client) (*ccprovider.ChaincodeData, error) {
 	var args [][]byte
-	args = append(args, []byte(cliconfig.Config().ChannelID()))
-	args = append(args, []byte(cliconfig.Config().ChaincodeID()))
+	args = append(args, []byte(action.Config().ChannelID()))
+	args = append(args, []byte(action.Config().ChaincodeID()))
 
 	peer := action.Peer()
-	fmt.Printf("querying chaincode info for %s on peer: %s...\n", cliconfig.Config().ChaincodeID(), peer.URL())
+	fmt.Printf("querying chaincode info for %s on peer: %s...\n", action.Config().ChaincodeID(), peer.URL())
 
 	response, err := channelClient.Query(
 		channel.Request{ChaincodeID: lifecycleSCC, Fcn: getCCDataFunc, Args: args},
@@ -122,10 +122,10 @@ func (action *getInfoAction) getCCData(channelClient *channel.Client) (*ccprovid
 
 func (action *getInfoAction) getCollConfig(channelClient *channel.Client) (*pb.CollectionConfigPackage, error) {
 	var args [][]byte
-	args = append(args, []byte(cliconfig.Config().ChaincodeID()))
+	args = append(args, []byte(action.Config().ChaincodeID()))
 
 	peer := action.Peer()
-	fmt.Printf("querying collections config for %s on peer: %s...\n", cliconfig.Config().ChaincodeID(), peer.URL())
+	fmt.Printf("querying collections config for %s on peer: %s...\n", action.Config().ChaincodeID(), peer.URL())
 
 	response, err := channelClient.Query(
 		channel.Request{ChaincodeID: lifecycleSCC, Fcn: getCollConfigFunc, Args: args},
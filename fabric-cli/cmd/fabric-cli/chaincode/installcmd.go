@@ -20,44 +20,44 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var installCmd = &cobra.Command{
-	Use:   "install",
-	Short: "Install chaincode.",
-	Long:  "Install chaincode",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cliconfig.Config().ChaincodeID() == "" {
-			fmt.Printf("\nMust specify the chaincode ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		if cliconfig.Config().ChaincodePath() == "" {
-			fmt.Printf("\nMust specify the path of the chaincode\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		action, err := newInstallAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing installAction: %v", err)
-			return
-		}
-
-		defer action.Terminate()
-
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running installAction: %v", err)
-		}
-	},
-}
+func getInstallCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install chaincode.",
+		Long:  "Install chaincode",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChaincodeID() == "" {
+				fmt.Printf("\nMust specify the chaincode ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			if cfg.ChaincodePath() == "" {
+				fmt.Printf("\nMust specify the path of the chaincode\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			action, err := newInstallAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing installAction: %v", err)
+				return
+			}
+
+			defer action.Terminate()
+
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running installAction: %v", err)
+			}
+		},
+	}
 
-func getInstallCmd() *cobra.Command {
 	flags := installCmd.Flags()
-	cliconfig.InitPeerURL(flags, "", "The URL of the peer on which to install the chaincode, e.g. localhost:7051")
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitChaincodeID(flags)
-	cliconfig.InitChaincodePath(flags)
-	cliconfig.InitChaincodeVersion(flags)
-	cliconfig.InitGoPath(flags)
+	cfg.InitPeerURL(flags, "", "The URL of the peer on which to install the chaincode, e.g. localhost:7051")
+	cfg.InitChannelID(flags)
+	cfg.InitChaincodeID(flags)
+	cfg.InitChaincodePath(flags)
+	cfg.InitChaincodeVersion(flags)
+	cfg.InitGoPath(flags)
 	return installCmd
 }
 
@@ -65,16 +65,16 @@ type installAction struct {
 	action.Action
 }
 
-func newInstallAction(flags *pflag.FlagSet) (*installAction, error) {
+func newInstallAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*installAction, error) {
 	action := &installAction{}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
 func (action *installAction) invoke() error {
 	var lastErr error
 	for orgID, peers := range action.PeersByOrg() {
-		fmt.Printf("Installing chaincode %s on org[%s] peers:\n", cliconfig.Config().ChaincodeID(), orgID)
+		fmt.Printf("Installing chaincode %s on org[%s] peers:\n", action.Config().ChaincodeID(), orgID)
 		for _, peer := range peers {
 			fmt.Printf("-- %s\n", peer.URL())
 		}
@@ -94,14 +94,14 @@ func (action *installAction) installChaincode(orgID string, targets []fab.Peer)
 		return err
 	}
 
-	ccPkg, err := gopackager.NewCCPackage(cliconfig.Config().ChaincodePath(), cliconfig.Config().GoPath())
+	ccPkg, err := gopackager.NewCCPackage(action.Config().ChaincodePath(), action.Config().GoPath())
 	if err != nil {
 		return err
 	}
 	req := resmgmt.InstallCCRequest{
-		Name:    cliconfig.Config().ChaincodeID(),
-		Path:    cliconfig.Config().ChaincodePath(),
-		Version: cliconfig.Config().ChaincodeVersion(),
+		Name:    action.Config().ChaincodeID(),
+		Path:    action.Config().ChaincodePath(),
+		Version: action.Config().ChaincodeVersion(),
 		Package: ccPkg,
 	}
 	responses, err := resMgmtClient.InstallCC(req, resmgmt.WithTargets(targets...))
@@ -109,7 +109,7 @@ func (action *installAction) installChaincode(orgID string, targets []fab.Peer)
 		return errors.Errorf("InstallChaincode returned error: %v", err)
 	}
 
-	ccIDVersion := cliconfig.Config().ChaincodeID() + "." + cliconfig.Config().ChaincodeVersion()
+	ccIDVersion := action.Config().ChaincodeID() + "." + action.Config().ChaincodeVersion()
 
 	var errs []error
 	for _, resp := range responses {
@@ -123,7 +123,7 @@ func (action *installAction) installChaincode(orgID string, targets []fab.Peer)
 	}
 
 	if len(errs) > 0 {
-		cliconfig.Config().Logger().Warnf("Errors returned from InstallCC: %v\n", errs)
+		action.Config().Logger().Warnf("Errors returned from InstallCC: %v\n", errs)
 		return errs[0]
 	}
 
@@ -24,47 +24,46 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var instantiateCmd = &cobra.Command{
-	Use:   "instantiate",
-	Short: "Instantiate chaincode.",
-	Long:  "Instantiates the chaincode",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cliconfig.Config().ChaincodeID() == "" {
-			fmt.Printf("\nMust specify the chaincode ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		if cliconfig.Config().ChaincodePath() == "" {
-			fmt.Printf("\nMust specify the path of the chaincode\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		action, err := newInstantiateAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing instantiateAction: %v", err)
-			return
-		}
-
-		defer action.Terminate()
-
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running instantiateAction: %v", err)
-		}
-	},
-}
+func getInstantiateCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	instantiateCmd := &cobra.Command{
+		Use:   "instantiate",
+		Short: "Instantiate chaincode.",
+		Long:  "Instantiates the chaincode",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChaincodeID() == "" {
+				fmt.Printf("\nMust specify the chaincode ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			if cfg.ChaincodePath() == "" {
+				fmt.Printf("\nMust specify the path of the chaincode\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			action, err := newInstantiateAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing instantiateAction: %v", err)
+				return
+			}
+
+			defer action.Terminate()
+
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running instantiateAction: %v", err)
+			}
+		},
+	}
 
-func getInstantiateCmd() *cobra.Command {
 	flags := instantiateCmd.Flags()
-	cliconfig.InitPeerURL(flags)
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitChaincodeID(flags)
-	cliconfig.InitChaincodePath(flags)
-	cliconfig.InitChaincodeVersion(flags)
-	cliconfig.InitArgs(flags)
-	cliconfig.InitChaincodePolicy(flags)
-	cliconfig.InitCollectionConfigFile(flags)
-	cliconfig.InitTimeout(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitChaincodeID(flags)
+	cfg.InitChaincodePath(flags)
+	cfg.InitChaincodeVersion(flags)
+	cfg.InitArgs(flags)
+	cfg.InitChaincodePolicy(flags)
+	cfg.InitCollectionConfigFile(flags)
 	return instantiateCmd
 }
 
@@ -72,9 +71,9 @@ type instantiateAction struct {
 	action.Action
 }
 
-func newInstantiateAction(flags *pflag.FlagSet) (*instantiateAction, error) {
+func newInstantiateAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*instantiateAction, error) {
 	action := &instantiateAction{}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	if len(action.Peers()) == 0 {
 		return nil, errors.Errorf("a peer must be specified")
 	}
@@ -82,7 +81,7 @@ func newInstantiateAction(flags *pflag.FlagSet) (*instantiateAction, error) {
 }
 
 func (a *instantiateAction) invoke() error {
-	s := cliconfig.Config().Args()
+	s := a.Config().Args()
 	argBytes := []byte(s)
 	args := &action.ArgStruct{}
 
@@ -95,7 +94,7 @@ func (a *instantiateAction) invoke() error {
 		return err
 	}
 
-	cliconfig.Config().Logger().Infof("Sending instantiate %s ...\n", cliconfig.Config().ChaincodeID())
+	a.Config().Logger().Infof("Sending instantiate %s ...\n", a.Config().ChaincodeID())
 
 	chaincodePolicy, err := a.newChaincodePolicy()
 	if err != nil {
@@ -105,43 +104,43 @@ func (a *instantiateAction) invoke() error {
 	// Private Data Collection Configuration
 	// - see fixtures/config/pvtdatacollection.json for sample config file
 	var collConfig []*pb.CollectionConfig
-	collConfigFile := cliconfig.Config().CollectionConfigFile()
+	collConfigFile := a.Config().CollectionConfigFile()
 	if collConfigFile != "" {
-		collConfig, err = getCollectionConfigFromFile(cliconfig.Config().CollectionConfigFile())
+		collConfig, err = getCollectionConfigFromFile(a.Config().CollectionConfigFile())
 		if err != nil {
-			return errors.Wrapf(err, "error getting private data collection configuration from file [%s]", cliconfig.Config().CollectionConfigFile())
+			return errors.Wrapf(err, "error getting private data collection configuration from file [%s]", a.Config().CollectionConfigFile())
 		}
 	}
 
 	req := resmgmt.InstantiateCCRequest{
-		Name:       cliconfig.Config().ChaincodeID(),
-		Path:       cliconfig.Config().ChaincodePath(),
-		Version:    cliconfig.Config().ChaincodeVersion(),
-		Args:       utils.AsBytes(utils.NewContext(), args.Args),
+		Name:       a.Config().ChaincodeID(),
+		Path:       a.Config().ChaincodePath(),
+		Version:    a.Config().ChaincodeVersion(),
+		Args:       utils.AsBytes(a.Config(), utils.NewContext(), args.Args),
 		Policy:     chaincodePolicy,
 		CollConfig: collConfig,
 	}
 
-	_, err = resMgmtClient.InstantiateCC(cliconfig.Config().ChannelID(), req, resmgmt.WithTargets(a.Peer()))
+	_, err = resMgmtClient.InstantiateCC(a.Config().ChannelID(), req, resmgmt.WithTargets(a.Peer()))
 	if err != nil {
-		if strings.Contains(err.Error(), "chaincode exists "+cliconfig.Config().ChaincodeID()) {
+		if strings.Contains(err.Error(), "chaincode exists "+a.Config().ChaincodeID()) {
 			// Ignore
-			cliconfig.Config().Logger().Infof("Chaincode %s already instantiated.", cliconfig.Config().ChaincodeID())
-			fmt.Printf("...chaincode %s already instantiated.\n", cliconfig.Config().ChaincodeID())
+			a.Config().Logger().Infof("Chaincode %s already instantiated.", a.Config().ChaincodeID())
+			fmt.Printf("...chaincode %s already instantiated.\n", a.Config().ChaincodeID())
 			return nil
 		}
 		return errors.Errorf("error instantiating chaincode: %v", err)
 	}
 
-	fmt.Printf("...successfuly instantiated chaincode %s on channel %s.\n", cliconfig.Config().ChaincodeID(), cliconfig.Config().ChannelID())
+	fmt.Printf("...successfuly instantiated chaincode %s on channel %s.\n", a.Config().ChaincodeID(), a.Config().ChannelID())
 
 	return nil
 }
 
 func (a *instantiateAction) newChaincodePolicy() (*common.SignaturePolicyEnvelope, error) {
-	if cliconfig.Config().ChaincodePolicy() != "" {
+	if a.Config().ChaincodePolicy() != "" {
 		// Create a signature policy from the policy expression passed in
-		return newChaincodePolicy(cliconfig.Config().ChaincodePolicy())
+		return newChaincodePolicy(a.Config().ChaincodePolicy())
 	}
 
 	// Default policy is 'signed by any member' for all known orgs
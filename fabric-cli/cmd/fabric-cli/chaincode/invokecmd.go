@@ -8,227 +8,153 @@ package chaincode
 
 import (
 	"fmt"
-	"strconv"
-	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
-	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
-	"github.com/pkg/errors"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/invoketask"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/multitask"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/task"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/utils"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/executor"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-var invokeCmd = &cobra.Command{
-	Use:   "invoke",
-	Short: "invoke chaincode.",
-	Long:  "invoke chaincode",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cliconfig.Config().ChaincodeID() == "" {
-			fmt.Printf("\nMust specify the chaincode ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		action, err := newInvokeAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing invokeAction: %v", err)
-			return
-		}
+func getInvokeCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	invokeCmd := &cobra.Command{
+		Use:   "invoke",
+		Short: "invoke chaincode.",
+		Long:  "invoke chaincode",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChaincodeID() == "" {
+				fmt.Printf("\nMust specify the chaincode ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			action, err := newInvokeAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing invokeAction: %v", err)
+				return
+			}
 
-		defer action.Terminate()
+			defer action.Close()
 
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running invokeAction: %v", err)
-		}
-	},
-}
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running invokeAction: %v", err)
+			}
+		},
+	}
 
-func getInvokeCmd() *cobra.Command {
 	flags := invokeCmd.Flags()
-	cliconfig.InitPeerURL(flags)
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitChaincodeID(flags)
-	cliconfig.InitArgs(flags)
-	cliconfig.InitIterations(flags)
-	cliconfig.InitSleepTime(flags)
-	cliconfig.InitTimeout(flags)
-	cliconfig.InitPrintPayloadOnly(flags)
-	cliconfig.InitConcurrency(flags)
-	cliconfig.InitMaxAttempts(flags)
-	cliconfig.InitInitialBackoff(flags)
-	cliconfig.InitMaxBackoff(flags)
-	cliconfig.InitBackoffFactor(flags)
-	cliconfig.InitVerbosity(flags)
-	cliconfig.InitSelectionProvider(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitChaincodeID(flags)
+	cfg.InitArgs(flags)
+	cfg.InitIterations(flags)
+	cfg.InitSleepTime(flags)
+	cfg.InitPrintPayloadOnly(flags)
+	cfg.InitConcurrency(flags)
+	cfg.InitMaxAttempts(flags)
+	cfg.InitInitialBackoff(flags)
+	cfg.InitMaxBackoff(flags)
+	cfg.InitBackoffFactor(flags)
+	cfg.InitVerbosity(flags)
+	cfg.InitSelectionProvider(flags)
+	cfg.InitSelectionRules(flags)
+	cfg.InitIdentityManifest(flags)
+	cfg.InitHealthInterval(flags)
+	cfg.InitTargetStrategy(flags)
 	return invokeCmd
 }
 
 type invokeAction struct {
-	action.Action
-	numInvoked uint32
-	done       chan bool
-}
-
-func newInvokeAction(flags *pflag.FlagSet) (*invokeAction, error) {
-	action := &invokeAction{done: make(chan bool)}
-	err := action.Initialize(flags)
-	return action, err
+	*fabriccli.Client
 }
 
-func (a *invokeAction) invoke() error {
-	channelClient, err := a.ChannelClient()
+func newInvokeAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*invokeAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
 	if err != nil {
-		return errors.Errorf("Error getting channel client: %v", err)
+		return nil, err
 	}
+	return &invokeAction{Client: client}, nil
+}
 
-	argsArray, err := action.ArgsArray()
+func (a *invokeAction) invoke() error {
+	argsArray, err := fabriccli.ParseArgs(a.Config().Args())
 	if err != nil {
 		return err
 	}
 
-	executor := executor.NewConcurrent("Invoke Chaincode", cliconfig.Config().Concurrency())
-	executor.Start()
-	defer executor.Stop(true)
+	verbose := a.Config().Verbose() || a.Config().Iterations() == 1
 
-	success := 0
-	var errs []error
-	var successDurations []time.Duration
-	var failDurations []time.Duration
+	ctx := a.Config().Context()
 
-	var targets []fab.Peer
-	if len(cliconfig.Config().PeerURL()) > 0 || len(cliconfig.Config().OrgIDs()) > 0 {
-		targets = a.Peers()
-	}
-
-	var wg sync.WaitGroup
-	var mutex sync.RWMutex
-	var tasks []task.Task
-	var taskID int
-	for i := 0; i < cliconfig.Config().Iterations(); i++ {
-		ctxt := utils.NewContext()
-		multiTask := multitask.New(wg.Done)
-		for _, args := range argsArray {
-			taskID++
-			var startTime time.Time
-			cargs := args
-			task := invoketask.New(
-				ctxt,
-				strconv.Itoa(taskID), channelClient, targets,
-				cliconfig.Config().ChaincodeID(),
-				&cargs, executor,
-				retry.Opts{
-					Attempts:       cliconfig.Config().MaxAttempts(),
-					InitialBackoff: cliconfig.Config().InitialBackoff(),
-					MaxBackoff:     cliconfig.Config().MaxBackoff(),
-					BackoffFactor:  cliconfig.Config().BackoffFactor(),
-					RetryableCodes: retry.ChannelClientRetryableCodes,
-				},
-				cliconfig.Config().Verbose() || cliconfig.Config().Iterations() == 1,
-				cliconfig.Config().PrintPayloadOnly(), a.Printer(),
-
-				func() {
-					startTime = time.Now()
-				},
-				func(err error) {
-					duration := time.Since(startTime)
-					mutex.Lock()
-					defer mutex.Unlock()
-					if err != nil {
-						errs = append(errs, err)
-						failDurations = append(failDurations, duration)
-					} else {
-						success++
-						successDurations = append(successDurations, duration)
-					}
-				})
-			multiTask.Add(task)
+	if a.Config().Verbose() {
+		endorsers, err := a.QueryEndorsers(ctx, a.Config().ChaincodeID())
+		if err != nil {
+			a.Config().Logger().Warnf("Unable to explain the endorser selection: %v", err)
+		} else {
+			a.Printer().PrintEndorsers(endorsers.Policy, endorsers.Groups, endorsers.Selected)
 		}
-		tasks = append(tasks, multiTask)
 	}
-
-	wg.Add(len(tasks))
-
-	numInvocations := len(tasks) * len(argsArray)
-
-	done := make(chan bool)
-	go func() {
-		ticker := time.NewTicker(10 * time.Second)
-		for {
-			select {
-			case <-ticker.C:
-				mutex.RLock()
-				if len(errs) > 0 {
-					fmt.Printf("*** %d failed invocation(s) out of %d\n", len(errs), numInvocations)
-				}
-				fmt.Printf("*** %d successfull invocation(s) out of %d\n", success, numInvocations)
-				mutex.RUnlock()
-			case <-done:
-				return
+	report, err := a.InvokeLoad(ctx, fabriccli.InvokeLoadRequest{
+		ChaincodeID: a.Config().ChaincodeID(),
+		Args:        argsArray,
+		Iterations:  a.Config().Iterations(),
+		Concurrency: a.Config().Concurrency(),
+		Sleep:       time.Duration(a.Config().SleepTime()) * time.Millisecond,
+		Retry: retry.Opts{
+			Attempts:       a.Config().MaxAttempts(),
+			InitialBackoff: a.Config().InitialBackoff(),
+			MaxBackoff:     a.Config().MaxBackoff(),
+			BackoffFactor:  a.Config().BackoffFactor(),
+			RetryableCodes: retry.ChannelClientRetryableCodes,
+		},
+		OnResult: func(result fabriccli.InvocationResult) {
+			if verbose && result.Response != nil {
+				a.Printer().PrintTxProposalResponses(result.Response.Responses, a.Config().PrintPayloadOnly())
 			}
-		}
-	}()
-
-	startTime := time.Now()
-	sleepTime := time.Duration(cliconfig.Config().SleepTime()) * time.Millisecond
-
-	for _, task := range tasks {
-		if err := executor.Submit(task); err != nil {
-			return errors.Errorf("error submitting task: %s", err)
-		}
-		if sleepTime > 0 {
-			time.Sleep(sleepTime)
-		}
+		},
+		OnProgress: func(progress fabriccli.Progress) {
+			if progress.Failed > 0 {
+				fmt.Printf("*** %d failed invocation(s) out of %d\n", progress.Failed, progress.Invocations)
+			}
+			fmt.Printf("*** %d successfull invocation(s) out of %d\n", progress.Successful, progress.Invocations)
+		},
+	})
+	if report == nil || (err != nil && ctx.Err() == nil) {
+		// The report is nil if the command was interrupted before any invocations were made
+		return err
 	}
 
-	// Wait for all tasks to complete
-	wg.Wait()
-	done <- true
-
-	duration := time.Now().Sub(startTime)
-
-	var allErrs []error
-	var attempts int
-	for _, task := range tasks {
-		attempts = attempts + task.Attempts()
-		if task.LastError() != nil {
-			allErrs = append(allErrs, task.LastError())
-		}
+	if ctx.Err() != nil {
+		fmt.Printf("\n*** Interrupted after %d of %d invocations\n", report.Invocations, report.Iterations*len(argsArray))
 	}
 
-	if len(errs) > 0 {
-		fmt.Printf("\n*** %d errors invoking chaincode:\n", len(errs))
-		for _, err := range errs {
+	if len(report.Errors) > 0 {
+		fmt.Printf("\n*** %d errors invoking chaincode:\n", len(report.Errors))
+		for _, err := range report.Errors {
 			fmt.Printf("%s\n", err)
 		}
-	} else if len(allErrs) > 0 {
-		fmt.Printf("\n*** %d transient errors invoking chaincode:\n", len(allErrs))
-		for _, err := range allErrs {
+	} else if len(report.TransientErrors) > 0 {
+		fmt.Printf("\n*** %d transient errors invoking chaincode:\n", len(report.TransientErrors))
+		for _, err := range report.TransientErrors {
 			fmt.Printf("%s\n", err)
 		}
 	}
 
-	if numInvocations/len(argsArray) > 1 {
+	if report.Iterations > 1 {
 		fmt.Printf("\n")
 		fmt.Printf("*** ---------- Summary: ----------\n")
-		fmt.Printf("***   - Invocations:     %d\n", numInvocations)
-		fmt.Printf("***   - Concurrency:     %d\n", cliconfig.Config().Concurrency())
-		fmt.Printf("***   - Successfull:     %d\n", success)
-		fmt.Printf("***   - Total attempts:  %d\n", attempts)
-		fmt.Printf("***   - Duration:        %2.2fs\n", duration.Seconds())
-		fmt.Printf("***   - Rate:            %2.2f/s\n", float64(numInvocations)/duration.Seconds())
-		fmt.Printf("***   - Average:         %2.2fs\n", average(append(successDurations, failDurations...)))
-		fmt.Printf("***   - Average Success: %2.2fs\n", average(successDurations))
-		fmt.Printf("***   - Average Fail:    %2.2fs\n", average(failDurations))
-		fmt.Printf("***   - Min Success:     %2.2fs\n", min(successDurations))
-		fmt.Printf("***   - Max Success:     %2.2fs\n", max(successDurations))
+		fmt.Printf("***   - Invocations:     %d\n", report.Invocations)
+		fmt.Printf("***   - Concurrency:     %d\n", report.Concurrency)
+		fmt.Printf("***   - Successfull:     %d\n", report.Successful)
+		fmt.Printf("***   - Total attempts:  %d\n", report.Attempts)
+		fmt.Printf("***   - Duration:        %2.2fs\n", report.Duration.Seconds())
+		fmt.Printf("***   - Rate:            %2.2f/s\n", report.Rate())
+		fmt.Printf("***   - Average:         %2.2fs\n", report.Average().Seconds())
+		fmt.Printf("***   - Average Success: %2.2fs\n", report.AverageSuccess().Seconds())
+		fmt.Printf("***   - Average Fail:    %2.2fs\n", report.AverageFail().Seconds())
+		fmt.Printf("***   - Min Success:     %2.2fs\n", report.MinSuccess().Seconds())
+		fmt.Printf("***   - Max Success:     %2.2fs\n", report.MaxSuccess().Seconds())
 		fmt.Printf("*** ------------------------------\n")
 	}
 
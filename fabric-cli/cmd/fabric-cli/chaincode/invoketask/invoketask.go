@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package invoketask
 
 import (
+	"context"
+	"time"
+
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
@@ -17,15 +20,17 @@ import (
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/utils"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/executor"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/printer"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/target"
 )
 
 // Task is a Task that invokes a chaincode
 type Task struct {
+	config        *cliconfig.CLIConfig
 	ctxt          utils.Context
 	executor      *executor.Executor
 	channelClient *channel.Client
-	targets       []fab.Peer
+	targets       func() []fab.Peer
+	selector      target.Selector
 	id            string
 	ccID          string
 	args          *action.ArgStruct
@@ -34,31 +39,26 @@ type Task struct {
 	lastErr       error
 	startedCB     func()
 	completedCB   func(err error)
-	verbose       bool
-	printer       printer.Printer
 	txID          string
-	payloadOnly   bool
+	response      *channel.Response
 }
 
-// New returns a new Task
-func New(ctxt utils.Context, id string, channelClient *channel.Client, targets []fab.Peer, ccID string, args *action.ArgStruct,
-	executor *executor.Executor, retryOpts retry.Opts, verbose bool,
-	payloadOnly bool, p printer.Printer, startedCB func(), completedCB func(err error)) *Task {
+// New returns a new Task. targets returns the target peers of each attempt (if nil then the peers are chosen by the selection service).
+func New(config *cliconfig.CLIConfig, ctxt utils.Context, id string, channelClient *channel.Client, targets func() []fab.Peer, selector target.Selector, ccID string, args *action.ArgStruct,
+	executor *executor.Executor, retryOpts retry.Opts, startedCB func(), completedCB func(err error)) *Task {
 	return &Task{
+		config:        config,
 		ctxt:          ctxt,
 		id:            id,
 		channelClient: channelClient,
 		targets:       targets,
-		printer:       p,
+		selector:      selector,
 		ccID:          ccID,
 		args:          args,
 		executor:      executor,
 		retryOpts:     retryOpts,
 		startedCB:     startedCB,
 		completedCB:   completedCB,
-		attempt:       1,
-		verbose:       verbose,
-		payloadOnly:   payloadOnly,
 	}
 }
 
@@ -73,58 +73,81 @@ func (t *Task) LastError() error {
 	return t.lastErr
 }
 
-// Invoke invokes the task
-func (t *Task) Invoke() {
+// TxID returns the ID of the last transaction that was submitted
+func (t *Task) TxID() string {
+	return t.txID
+}
+
+// Response returns the response of the last invocation or nil if no response was received
+func (t *Task) Response() *channel.Response {
+	return t.response
+}
+
+// Invoke invokes the task unless the context is done, in which case the task isn't started
+func (t *Task) Invoke(ctx context.Context) {
+	if ctx.Err() != nil {
+		t.config.Logger().Debugf("(%s) - Not started: %s\n", t.id, ctx.Err())
+		return
+	}
+
+	t.attempt = 1
 	t.startedCB()
-	if err := t.doInvoke(); err != nil {
+	if err := t.doInvoke(ctx); err != nil {
 		t.lastErr = err
 		t.completedCB(err)
 	} else {
-		cliconfig.Config().Logger().Debugf("(%s) - Successfully invoked chaincode\n", t.id)
+		t.config.Logger().Debugf("(%s) - Successfully invoked chaincode\n", t.id)
 		t.completedCB(nil)
 	}
 }
 
-func (t *Task) doInvoke() error {
-	cliconfig.Config().Logger().Debugf("(%s) - Invoking chaincode: %s, function: %s, args: %+v. Attempt #%d...\n",
+func (t *Task) doInvoke(ctx context.Context) error {
+	t.config.Logger().Debugf("(%s) - Invoking chaincode: %s, function: %s, args: %+v. Attempt #%d...\n",
 		t.id, t.ccID, t.args.Func, t.args.Args, t.attempt)
 
 	var opts []channel.RequestOption
+	// The parent context stops the SDK's retries if the command is interrupted
+	opts = append(opts, channel.WithParentContext(ctx))
 	opts = append(opts, channel.WithRetry(t.retryOpts))
 	opts = append(opts, channel.WithBeforeRetry(func(err error) {
 		t.attempt++
 	}))
-	if len(t.targets) > 0 {
-		opts = append(opts, channel.WithTargets(t.targets...))
+	var targets []fab.Peer
+	if t.targets != nil {
+		if targets = t.targets(); len(targets) > 0 {
+			targets = t.selector.Select(targets)
+			opts = append(opts, channel.WithTargets(targets...))
+		}
 	}
 
+	start := time.Now()
 	response, err := t.channelClient.Execute(
 		channel.Request{
 			ChaincodeID: t.ccID,
 			Fcn:         t.args.Func,
-			Args:        utils.AsBytes(t.ctxt, t.args.Args),
+			Args:        utils.AsBytes(t.config, t.ctxt, t.args.Args),
 		},
 		opts...,
 	)
+	if len(targets) > 0 {
+		t.selector.Done(targets, time.Since(start), err)
+	}
 	if err != nil {
 		return invokeerror.Errorf(invokeerror.TransientError, "SendTransactionProposal return error: %v", err)
 	}
 
-	if t.verbose {
-		t.printer.PrintTxProposalResponses(response.Responses, t.payloadOnly)
-	}
-
+	t.response = &response
 	t.txID = string(response.TransactionID)
 
 	switch pb.TxValidationCode(response.TxValidationCode) {
 	case pb.TxValidationCode_VALID:
-		cliconfig.Config().Logger().Debugf("(%s) - Successfully committed transaction [%s] ...\n", t.id, response.TransactionID)
+		t.config.Logger().Debugf("(%s) - Successfully committed transaction [%s] ...\n", t.id, response.TransactionID)
 		return nil
 	case pb.TxValidationCode_DUPLICATE_TXID, pb.TxValidationCode_MVCC_READ_CONFLICT, pb.TxValidationCode_PHANTOM_READ_CONFLICT:
-		cliconfig.Config().Logger().Debugf("(%s) - Transaction commit failed for [%s] with code [%s]. This is most likely a transient error.\n", t.id, response.TransactionID, response.TxValidationCode)
+		t.config.Logger().Debugf("(%s) - Transaction commit failed for [%s] with code [%s]. This is most likely a transient error.\n", t.id, response.TransactionID, response.TxValidationCode)
 		return invokeerror.Wrapf(invokeerror.TransientError, errors.New("Duplicate TxID"), "invoke Error received from eventhub for TxID [%s]. Code: %s", response.TransactionID, response.TxValidationCode)
 	default:
-		cliconfig.Config().Logger().Debugf("(%s) - Transaction commit failed for [%s] with code [%s].\n", t.id, response.TransactionID, response.TxValidationCode)
+		t.config.Logger().Debugf("(%s) - Transaction commit failed for [%s] with code [%s].\n", t.id, response.TransactionID, response.TxValidationCode)
 		return invokeerror.Wrapf(invokeerror.PersistentError, errors.New("error"), "invoke Error received from eventhub for TxID [%s]. Code: %s", response.TransactionID, response.TxValidationCode)
 	}
 
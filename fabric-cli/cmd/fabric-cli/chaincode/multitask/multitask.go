@@ -6,7 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 
 package multitask
 
-import "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/task"
+import (
+	"context"
+
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/task"
+)
 
 // MultiTask contains a set of Tasks to be invoked synchronously
 type MultiTask struct {
@@ -26,12 +30,16 @@ func (m *MultiTask) Add(task task.Task) {
 	m.tasks = append(m.tasks, task)
 }
 
-// Invoke invokes the task
-func (m *MultiTask) Invoke() {
+// Invoke invokes the tasks in order. The remaining tasks are not invoked once the context is done
+// although the completion callback is always called.
+func (m *MultiTask) Invoke(ctx context.Context) {
 	defer m.completedCB()
 
 	for _, task := range m.tasks {
-		task.Invoke()
+		if ctx.Err() != nil {
+			return
+		}
+		task.Invoke(ctx)
 	}
 }
 
@@ -22,49 +22,53 @@ import (
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/utils"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/executor"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/target"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-var queryCmd = &cobra.Command{
-	Use:   "query",
-	Short: "Query chaincode.",
-	Long:  "Query chaincode",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cliconfig.Config().ChaincodeID() == "" {
-			fmt.Printf("\nMust specify the chaincode ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		action, err := newQueryAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing queryAction: %v", err)
-			return
-		}
+func getQueryCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryCmd := &cobra.Command{
+		Use:   "query",
+		Short: "Query chaincode.",
+		Long:  "Query chaincode",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChaincodeID() == "" {
+				fmt.Printf("\nMust specify the chaincode ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			action, err := newQueryAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryAction: %v", err)
+				return
+			}
 
-		defer action.Terminate()
+			defer action.Terminate()
 
-		err = action.query()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running queryAction: %v", err)
-		}
-	},
-}
+			err = action.query()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryAction: %v", err)
+			}
+		},
+	}
 
-func getQueryCmd() *cobra.Command {
 	flags := queryCmd.Flags()
-	cliconfig.InitPeerURL(flags)
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitChaincodeID(flags)
-	cliconfig.InitArgs(flags)
-	cliconfig.InitIterations(flags)
-	cliconfig.InitSleepTime(flags)
-	cliconfig.InitTimeout(flags)
-	cliconfig.InitPrintPayloadOnly(flags)
-	cliconfig.InitConcurrency(flags)
-	cliconfig.InitVerbosity(flags)
-	cliconfig.InitSelectionProvider(flags)
-	cliconfig.InitValidate(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitChaincodeID(flags)
+	cfg.InitArgs(flags)
+	cfg.InitIterations(flags)
+	cfg.InitSleepTime(flags)
+	cfg.InitPrintPayloadOnly(flags)
+	cfg.InitConcurrency(flags)
+	cfg.InitVerbosity(flags)
+	cfg.InitSelectionProvider(flags)
+	cfg.InitSelectionRules(flags)
+	cfg.InitValidate(flags)
+	cfg.InitIdentityManifest(flags)
+	cfg.InitHealthInterval(flags)
+	cfg.InitTargetStrategy(flags)
 	return queryCmd
 }
 
@@ -74,33 +78,41 @@ type queryAction struct {
 	done       chan bool
 }
 
-func newQueryAction(flags *pflag.FlagSet) (*queryAction, error) {
+func newQueryAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryAction, error) {
 	action := &queryAction{done: make(chan bool)}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
 func (a *queryAction) query() error {
-	channelClient, err := a.ChannelClient()
+	selector, err := target.NewSelector(a.Config().TargetStrategy())
+	if err != nil {
+		return err
+	}
+
+	channelClients, err := a.ChannelClients()
 	if err != nil {
 		return errors.Errorf("Error getting channel client: %v", err)
 	}
 
-	argsArray, err := action.ArgsArray()
+	argsArray, err := a.ArgsArray()
 	if err != nil {
 		return err
 	}
 
-	var targets []fab.Peer
-	if len(cliconfig.Config().PeerURL()) > 0 || len(cliconfig.Config().OrgIDs()) > 0 {
-		targets = a.Peers()
+	var targets func() []fab.Peer
+	if len(a.Config().PeerURL()) > 0 || len(a.Config().OrgIDs()) > 0 {
+		if err := a.StartHealthMonitor(); err != nil {
+			return err
+		}
+		targets = a.Targets
 	}
 
-	executor := executor.NewConcurrent("Query Chaincode", cliconfig.Config().Concurrency())
+	executor := executor.NewConcurrent(a.Config(), "Query Chaincode", a.Config().Concurrency())
 	executor.Start()
 	defer executor.Stop(true)
 
-	verbose := cliconfig.Config().Verbose() || cliconfig.Config().Iterations() == 1
+	verbose := a.Config().Verbose() || a.Config().Iterations() == 1
 
 	var mutex sync.RWMutex
 	var tasks []task.Task
@@ -108,10 +120,11 @@ func (a *queryAction) query() error {
 	var wg sync.WaitGroup
 	var taskID int
 	var success int
+	var numStarted int
 	var successDurations []time.Duration
 	var failDurations []time.Duration
 
-	for i := 0; i < cliconfig.Config().Iterations(); i++ {
+	for i := 0; i < a.Config().Iterations(); i++ {
 		ctxt := utils.NewContext()
 		multiTask := multitask.New(wg.Done)
 		for _, args := range argsArray {
@@ -119,20 +132,23 @@ func (a *queryAction) query() error {
 			var startTime time.Time
 			cargs := args
 			task := querytask.New(
-				ctxt,
-				strconv.Itoa(taskID), channelClient, targets, &cargs, a.Printer(),
+				a.Config(), ctxt,
+				strconv.Itoa(taskID), channelClients[(taskID-1)%len(channelClients)], targets, selector, &cargs, a.Printer(),
 				retry.Opts{
-					Attempts:       cliconfig.Config().MaxAttempts(),
-					InitialBackoff: cliconfig.Config().InitialBackoff(),
-					MaxBackoff:     cliconfig.Config().MaxBackoff(),
-					BackoffFactor:  cliconfig.Config().BackoffFactor(),
+					Attempts:       a.Config().MaxAttempts(),
+					InitialBackoff: a.Config().InitialBackoff(),
+					MaxBackoff:     a.Config().MaxBackoff(),
+					BackoffFactor:  a.Config().BackoffFactor(),
 					RetryableCodes: retry.ChannelClientRetryableCodes,
 				},
 				verbose,
-				cliconfig.Config().PrintPayloadOnly(),
-				cliconfig.Config().Validate(),
+				a.Config().PrintPayloadOnly(),
+				a.Config().Validate(),
 				func() {
 					startTime = time.Now()
+					mutex.Lock()
+					numStarted++
+					mutex.Unlock()
 				},
 				func(err error) {
 					duration := time.Since(startTime)
@@ -150,8 +166,6 @@ func (a *queryAction) query() error {
 		tasks = append(tasks, multiTask)
 	}
 
-	wg.Add(len(tasks))
-
 	numInvocations := len(tasks) * len(argsArray)
 
 	done := make(chan bool)
@@ -173,21 +187,38 @@ func (a *queryAction) query() error {
 	}()
 
 	startTime := time.Now()
-	sleepTime := time.Duration(cliconfig.Config().SleepTime()) * time.Millisecond
+	sleepTime := time.Duration(a.Config().SleepTime()) * time.Millisecond
 
+	ctx := a.Config().Context()
 	for _, task := range tasks {
-		if err := executor.Submit(task); err != nil {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		if err := executor.Submit(ctx, task); err != nil {
+			wg.Done()
+			if ctx.Err() != nil {
+				break
+			}
 			return errors.Errorf("error submitting task: %s", err)
 		}
 		if sleepTime > 0 {
-			time.Sleep(sleepTime)
+			select {
+			case <-time.After(sleepTime):
+			case <-ctx.Done():
+			}
 		}
 	}
 
-	// Wait for all tasks to complete
+	// Wait for all tasks to complete. Tasks that were queued when the command
+	// was interrupted complete without being started.
 	wg.Wait()
 	done <- true
 
+	if ctx.Err() != nil {
+		fmt.Printf("\n*** Interrupted after %d of %d queries\n", numStarted, numInvocations)
+	}
+
 	duration := time.Now().Sub(startTime)
 
 	var allErrs []error
@@ -214,12 +245,12 @@ func (a *queryAction) query() error {
 	if numInvocations/len(argsArray) > 1 {
 		fmt.Printf("\n")
 		fmt.Printf("*** ---------- Summary: ----------\n")
-		fmt.Printf("***   - Queries:         %d\n", numInvocations)
-		fmt.Printf("***   - Concurrency:     %d\n", cliconfig.Config().Concurrency())
+		fmt.Printf("***   - Queries:         %d\n", numStarted)
+		fmt.Printf("***   - Concurrency:     %d\n", a.Config().Concurrency())
 		fmt.Printf("***   - Successfull:     %d\n", success)
 		fmt.Printf("***   - Total attempts:  %d\n", attempts)
 		fmt.Printf("***   - Duration:        %2.2fs\n", duration.Seconds())
-		fmt.Printf("***   - Rate:            %2.2f/s\n", float64(numInvocations)/duration.Seconds())
+		fmt.Printf("***   - Rate:            %2.2f/s\n", float64(numStarted)/duration.Seconds())
 		fmt.Printf("***   - Average:         %2.2fs\n", average(append(successDurations, failDurations...)))
 		fmt.Printf("***   - Average Success: %2.2fs\n", average(successDurations))
 		fmt.Printf("***   - Average Fail:    %2.2fs\n", average(failDurations))
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package querytask
 
 import (
+	"context"
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
@@ -15,13 +18,16 @@ import (
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/utils"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/printer"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/target"
 )
 
 // Task is the query task
 type Task struct {
+	config        *cliconfig.CLIConfig
 	ctxt          utils.Context
 	channelClient *channel.Client
-	targets       []fab.Peer
+	targets       func() []fab.Peer
+	selector      target.Selector
 	retryOpts     retry.Opts
 	id            string
 	args          *action.ArgStruct
@@ -35,19 +41,20 @@ type Task struct {
 	lastErr       error
 }
 
-// New creates a new query Task
-func New(ctxt utils.Context, id string, channelClient *channel.Client, targets []fab.Peer, args *action.ArgStruct, printer printer.Printer,
+// New creates a new query Task. targets returns the target peers of each attempt (if nil then the peers are chosen by the selection service).
+func New(config *cliconfig.CLIConfig, ctxt utils.Context, id string, channelClient *channel.Client, targets func() []fab.Peer, selector target.Selector, args *action.ArgStruct, printer printer.Printer,
 	retryOpts retry.Opts, verbose bool, payloadOnly bool, validate bool, startedCB func(), completedCB func(err error)) *Task {
 	return &Task{
+		config:        config,
 		ctxt:          ctxt,
 		id:            id,
 		channelClient: channelClient,
 		targets:       targets,
+		selector:      selector,
 		retryOpts:     retryOpts,
 		args:          args,
 		startedCB:     startedCB,
 		completedCB:   completedCB,
-		attempt:       1,
 		printer:       printer,
 		verbose:       verbose,
 		payloadOnly:   payloadOnly,
@@ -55,23 +62,35 @@ func New(ctxt utils.Context, id string, channelClient *channel.Client, targets [
 	}
 }
 
-// Invoke invokes the query task
-func (t *Task) Invoke() {
+// Invoke invokes the query task unless the context is done, in which case the task isn't started
+func (t *Task) Invoke(ctx context.Context) {
+	if ctx.Err() != nil {
+		t.config.Logger().Debugf("(%s) - Not started: %s\n", t.id, ctx.Err())
+		return
+	}
+
+	t.attempt = 1
 	t.startedCB()
 
 	var opts []channel.RequestOption
+	// The parent context stops the SDK's retries if the command is interrupted
+	opts = append(opts, channel.WithParentContext(ctx))
 	opts = append(opts, channel.WithRetry(t.retryOpts))
 	opts = append(opts, channel.WithBeforeRetry(func(err error) {
 		t.attempt++
 	}))
-	if len(t.targets) > 0 {
-		opts = append(opts, channel.WithTargets(t.targets...))
+	var targets []fab.Peer
+	if t.targets != nil {
+		if targets = t.targets(); len(targets) > 0 {
+			targets = t.selector.Select(targets)
+			opts = append(opts, channel.WithTargets(targets...))
+		}
 	}
 
 	request := channel.Request{
-		ChaincodeID: cliconfig.Config().ChaincodeID(),
+		ChaincodeID: t.config.ChaincodeID(),
 		Fcn:         t.args.Func,
-		Args:        utils.AsBytes(t.ctxt, t.args.Args),
+		Args:        utils.AsBytes(t.config, t.ctxt, t.args.Args),
 	}
 
 	var additionalHandlers []invoke.Handler
@@ -84,17 +103,21 @@ func (t *Task) Invoke() {
 		)
 	}
 
+	start := time.Now()
 	response, err := t.channelClient.InvokeHandler(
 		invoke.NewProposalProcessorHandler(
 			invoke.NewEndorsementHandler(additionalHandlers...),
 		),
 		request, opts...)
+	if len(targets) > 0 {
+		t.selector.Done(targets, time.Since(start), err)
+	}
 	if err != nil {
-		cliconfig.Config().Logger().Debugf("(%s) - Error querying chaincode: %s\n", t.id, err)
+		t.config.Logger().Debugf("(%s) - Error querying chaincode: %s\n", t.id, err)
 		t.lastErr = err
 		t.completedCB(err)
 	} else {
-		cliconfig.Config().Logger().Debugf("(%s) - Chaincode query was successful\n", t.id)
+		t.config.Logger().Debugf("(%s) - Chaincode query was successful\n", t.id)
 
 		if t.verbose {
 			t.printer.PrintTxProposalResponses(response.Responses, t.payloadOnly)
@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package querytask
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const channelID = "orgchannel"
+
+func TestInvokeCancelled(t *testing.T) {
+	cfg := cliconfig.New()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.InitChaincodeID(flags)
+	require.NoError(t, flags.Parse([]string{"--ccid", "examplecc"}))
+
+	channelClient := newChannelClient(t, &blockingPeer{MockPeer: mocks.NewMockPeer("peer0", "peer0.org1:7051")})
+
+	var completedErr error
+	task := New(cfg, nil, "query1", channelClient, nil, nil, &action.ArgStruct{Func: "query"}, nil,
+		retry.DefaultChannelOpts, false, false, false, func() {}, func(err error) { completedErr = err })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	// The query is abandoned when the context is cancelled rather than when the endorsement times out
+	start := time.Now()
+	task.Invoke(ctx)
+	assert.True(t, time.Since(start) < 5*time.Second)
+	assert.Error(t, completedErr)
+	assert.Equal(t, 1, task.Attempts())
+
+	// A task isn't started if the context is already done
+	completedErr = nil
+	task.Invoke(ctx)
+	assert.NoError(t, completedErr)
+}
+
+// blockingPeer doesn't respond until the request is cancelled or times out
+type blockingPeer struct {
+	*mocks.MockPeer
+}
+
+func (p *blockingPeer) ProcessTransactionProposal(ctx context.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func newChannelClient(t *testing.T, peers ...fab.Peer) *channel.Client {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+
+	chProvider, err := mocks.NewMockChannelProvider(ctx)
+	require.NoError(t, err)
+	chService, err := chProvider.ChannelService(ctx, channelID)
+	require.NoError(t, err)
+
+	mockChService := chService.(*mocks.MockChannelService)
+	mockChService.SetTransactor(&txnmocks.MockTransactor{Ctx: ctx, ChannelID: channelID, Orderers: []fab.Orderer{mocks.NewMockOrderer("", nil)}})
+	mockChService.SetDiscovery(txnmocks.NewMockDiscoveryService(nil))
+	mockChService.SetSelection(txnmocks.NewMockSelectionService(nil, peers...))
+	ctx.MockProviderContext.ChannelProvider().(*mocks.MockChannelProvider).SetCustomChannelService(chService)
+
+	client, err := channel.New(func() (contextApi.Channel, error) {
+		return contextImpl.NewChannel(func() (contextApi.Client, error) { return ctx, nil }, channelID)
+	})
+	require.NoError(t, err)
+	return client
+}
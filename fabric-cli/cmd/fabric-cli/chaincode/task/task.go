@@ -6,10 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 
 package task
 
+import "context"
+
 // Task is an invocable unit of work
 type Task interface {
-	// Invoke invokes the task
-	Invoke()
+	// Invoke invokes the task. The task is not started if the context is done.
+	Invoke(ctx context.Context)
 
 	// Attempts returns the number of invocation attempts that were made
 	// in order to achieve a successful response
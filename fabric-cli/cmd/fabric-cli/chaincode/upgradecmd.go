@@ -23,47 +23,46 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var upgradeCmd = &cobra.Command{
-	Use:   "upgrade",
-	Short: "Upgrade chaincode.",
-	Long:  "Upgrades the chaincode",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cliconfig.Config().ChaincodeID() == "" {
-			fmt.Printf("\nMust specify the chaincode ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		if cliconfig.Config().ChaincodePath() == "" {
-			fmt.Printf("\nMust specify the path of the chaincode\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		action, err := newUpgradeAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing upgradeAction: %v", err)
-			return
-		}
-
-		defer action.Terminate()
-
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running upgradeAction: %v", err)
-		}
-	},
-}
+func getUpgradeCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade chaincode.",
+		Long:  "Upgrades the chaincode",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChaincodeID() == "" {
+				fmt.Printf("\nMust specify the chaincode ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			if cfg.ChaincodePath() == "" {
+				fmt.Printf("\nMust specify the path of the chaincode\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			action, err := newUpgradeAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing upgradeAction: %v", err)
+				return
+			}
+
+			defer action.Terminate()
+
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running upgradeAction: %v", err)
+			}
+		},
+	}
 
-func getUpgradeCmd() *cobra.Command {
 	flags := upgradeCmd.Flags()
-	cliconfig.InitPeerURL(flags)
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitChaincodeID(flags)
-	cliconfig.InitChaincodePath(flags)
-	cliconfig.InitChaincodeVersion(flags)
-	cliconfig.InitArgs(flags)
-	cliconfig.InitChaincodePolicy(flags)
-	cliconfig.InitCollectionConfigFile(flags)
-	cliconfig.InitTimeout(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitChaincodeID(flags)
+	cfg.InitChaincodePath(flags)
+	cfg.InitChaincodeVersion(flags)
+	cfg.InitArgs(flags)
+	cfg.InitChaincodePolicy(flags)
+	cfg.InitCollectionConfigFile(flags)
 	return upgradeCmd
 }
 
@@ -71,9 +70,9 @@ type upgradeAction struct {
 	action.Action
 }
 
-func newUpgradeAction(flags *pflag.FlagSet) (*upgradeAction, error) {
+func newUpgradeAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*upgradeAction, error) {
 	action := &upgradeAction{}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	if len(action.Peers()) == 0 {
 		return nil, errors.Errorf("a peer must be specified")
 	}
@@ -81,7 +80,7 @@ func newUpgradeAction(flags *pflag.FlagSet) (*upgradeAction, error) {
 }
 
 func (a *upgradeAction) invoke() error {
-	argBytes := []byte(cliconfig.Config().Args())
+	argBytes := []byte(a.Config().Args())
 	args := &action.ArgStruct{}
 
 	if err := json.Unmarshal(argBytes, args); err != nil {
@@ -93,7 +92,7 @@ func (a *upgradeAction) invoke() error {
 		return err
 	}
 
-	cliconfig.Config().Logger().Infof("Sending upgrade %s ...\n", cliconfig.Config().ChaincodeID())
+	a.Config().Logger().Infof("Sending upgrade %s ...\n", a.Config().ChaincodeID())
 
 	chaincodePolicy, err := a.newChaincodePolicy()
 	if err != nil {
@@ -103,48 +102,48 @@ func (a *upgradeAction) invoke() error {
 	// Private Data Collection Configuration
 	// - see fixtures/config/pvtdatacollection.json for sample config file
 	var collConfig []*pb.CollectionConfig
-	collConfigFile := cliconfig.Config().CollectionConfigFile()
+	collConfigFile := a.Config().CollectionConfigFile()
 	if collConfigFile != "" {
-		collConfig, err = getCollectionConfigFromFile(cliconfig.Config().CollectionConfigFile())
+		collConfig, err = getCollectionConfigFromFile(a.Config().CollectionConfigFile())
 		if err != nil {
-			return errors.Wrapf(err, "error getting private data collection configuration from file [%s]", cliconfig.Config().CollectionConfigFile())
+			return errors.Wrapf(err, "error getting private data collection configuration from file [%s]", a.Config().CollectionConfigFile())
 		}
 	}
 
 	req := resmgmt.UpgradeCCRequest{
-		Name:       cliconfig.Config().ChaincodeID(),
-		Path:       cliconfig.Config().ChaincodePath(),
-		Version:    cliconfig.Config().ChaincodeVersion(),
-		Args:       utils.AsBytes(utils.NewContext(), args.Args),
+		Name:       a.Config().ChaincodeID(),
+		Path:       a.Config().ChaincodePath(),
+		Version:    a.Config().ChaincodeVersion(),
+		Args:       utils.AsBytes(a.Config(), utils.NewContext(), args.Args),
 		Policy:     chaincodePolicy,
 		CollConfig: collConfig,
 	}
 
-	_, err = resMgmtClient.UpgradeCC(cliconfig.Config().ChannelID(), req, resmgmt.WithTargets(a.Peers()...))
+	_, err = resMgmtClient.UpgradeCC(a.Config().ChannelID(), req, resmgmt.WithTargets(a.Peers()...))
 	if err != nil {
-		if strings.Contains(err.Error(), "chaincode exists "+cliconfig.Config().ChaincodeID()) {
+		if strings.Contains(err.Error(), "chaincode exists "+a.Config().ChaincodeID()) {
 			// Ignore
-			cliconfig.Config().Logger().Infof("Chaincode %s already instantiated.", cliconfig.Config().ChaincodeID())
-			fmt.Printf("...chaincode %s already instantiated.\n", cliconfig.Config().ChaincodeID())
+			a.Config().Logger().Infof("Chaincode %s already instantiated.", a.Config().ChaincodeID())
+			fmt.Printf("...chaincode %s already instantiated.\n", a.Config().ChaincodeID())
 			return nil
 		}
 		return errors.Errorf("error instantiating chaincode: %v", err)
 	}
 
-	fmt.Printf("...successfuly upgraded chaincode %s on channel %s.\n", cliconfig.Config().ChaincodeID(), cliconfig.Config().ChannelID())
+	fmt.Printf("...successfuly upgraded chaincode %s on channel %s.\n", a.Config().ChaincodeID(), a.Config().ChannelID())
 
 	return nil
 }
 
 func (a *upgradeAction) newChaincodePolicy() (*fabricCommon.SignaturePolicyEnvelope, error) {
-	if cliconfig.Config().ChaincodePolicy() != "" {
+	if a.Config().ChaincodePolicy() != "" {
 		// Create a signature policy from the policy expression passed in
-		return newChaincodePolicy(cliconfig.Config().ChaincodePolicy())
+		return newChaincodePolicy(a.Config().ChaincodePolicy())
 	}
 
 	// Default policy is 'signed my any member' for all known orgs
 	var mspIDs []string
-	for _, orgID := range cliconfig.Config().OrgIDs() {
+	for _, orgID := range a.Config().OrgIDs() {
 		orgConfig, ok := a.EndpointConfig().NetworkConfig().Organizations[orgID]
 		if !ok {
 			return nil, errors.Errorf("Unable to get the MSP ID from org ID %s", orgID)
@@ -50,16 +50,16 @@ type Context interface {
 // - "key$seq()" -> "key1", "key2", "key2", ...
 // - "val$pad($seq(),X)" -> "valX", "valXX", "valXX", "valXXX", ...
 // - "Key_$set(x,$seq())=Val_${x}" -> Key_1=Val_1, Key_2=Val_2, ...
-func AsBytes(ctxt Context, args []string) [][]byte {
+func AsBytes(cfg *cliconfig.CLIConfig, ctxt Context, args []string) [][]byte {
 	rand := rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
 	bytes := make([][]byte, len(args))
 
-	if cliconfig.Config().Verbose() {
+	if cfg.Verbose() {
 		fmt.Printf("Args:\n")
 	}
 	for i, a := range args {
-		arg := getArg(ctxt, rand, a)
-		if cliconfig.Config().Verbose() {
+		arg := getArg(cfg, ctxt, rand, a)
+		if cfg.Verbose() {
 			fmt.Printf("- [%d]=%s\n", i, arg)
 		}
 		bytes[i] = []byte(arg)
@@ -67,11 +67,11 @@ func AsBytes(ctxt Context, args []string) [][]byte {
 	return bytes
 }
 
-func getArg(ctxt Context, r *rand.Rand, arg string) string {
+func getArg(cfg *cliconfig.CLIConfig, ctxt Context, r *rand.Rand, arg string) string {
 	arg = evaluateSeqExpression(arg)
 	arg = evaluateRandExpression(r, arg)
 	arg = evaluatePadExpression(arg)
-	arg = evaluateFileExpression(arg)
+	arg = evaluateFileExpression(cfg, arg)
 	arg = evaluateSetExpression(ctxt, arg)
 	arg = evaluateVarExpression(ctxt, arg)
 	return arg
@@ -123,10 +123,10 @@ func evaluatePadExpression(arg string) string {
 }
 
 // evaluateFileExpression replaces occurrences of $file(path) with the contents of the file
-func evaluateFileExpression(arg string) string {
+func evaluateFileExpression(cfg *cliconfig.CLIConfig, arg string) string {
 	return evaluateExpression(arg, fileFunc, ")",
 		func(expression string) (string, error) {
-			return readFile(expression)
+			return readFile(cfg, expression)
 		})
 }
 
@@ -213,7 +213,7 @@ func (c *defaultContext) GetVar(k string) (string, bool) {
 	return value, ok
 }
 
-func readFile(filePath string) (string, error) {
+func readFile(cfg *cliconfig.CLIConfig, filePath string) (string, error) {
 	fmt.Printf("Reading file: [%s]", filePath)
 	file, err := os.Open(filepath.Clean(filePath))
 	if err != nil {
@@ -222,7 +222,7 @@ func readFile(filePath string) (string, error) {
 	defer func() {
 		fileErr := file.Close()
 		if fileErr != nil {
-			cliconfig.Config().Logger().Errorf("Failed to close file : %s", fileErr)
+			cfg.Logger().Errorf("Failed to close file : %s", fileErr)
 		}
 	}()
 
@@ -12,6 +12,8 @@ import (
 	"math/rand"
 	"testing"
 	"time"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
 )
 
 func TestEvaluatePadExpression(t *testing.T) {
@@ -73,7 +75,7 @@ func TestFailEvaluateSeqExpression(t *testing.T) {
 }
 
 func TestEvaluateFileExpression(t *testing.T) {
-	assert.Equal(t, `{"Field1": "Value1"}`, evaluateFileExpression("$file(./test.json)"))
+	assert.Equal(t, `{"Field1": "Value1"}`, evaluateFileExpression(cliconfig.New(), "$file(./test.json)"))
 }
 
 func TestFailEvaluateFileExpression(t *testing.T) {
@@ -97,34 +99,34 @@ func TestFailEvaluateSetExpression(t *testing.T) {
 
 func TestGetArg(t *testing.T) {
 	rand := rand.New(rand.NewSource(time.Now().UTC().UnixNano()))
-	result := getArg(NewContext(), rand, "Value_$pad(3,X)!")
+	result := getArg(cliconfig.New(), NewContext(), rand, "Value_$pad(3,X)!")
 	assert.Equal(t, "Value_XXX!", result)
 
 	for i := 0; i < 5; i++ {
-		result := getArg(NewContext(), rand, "Value_$rand(2)!")
+		result := getArg(cliconfig.New(), NewContext(), rand, "Value_$rand(2)!")
 		assert.True(t, result == "Value_0!" || result == "Value_1!")
 	}
 
 	for i := 0; i < 5; i++ {
-		result := getArg(NewContext(), rand, "Value_$pad(3,X)_$rand(2)!")
+		result := getArg(cliconfig.New(), NewContext(), rand, "Value_$pad(3,X)_$rand(2)!")
 		assert.True(t, result == "Value_XXX_0!" || result == "Value_XXX_1!")
 	}
 
 	for i := 0; i < 5; i++ {
-		result := getArg(NewContext(), rand, "Value_$pad($rand(3),X)!")
+		result := getArg(cliconfig.New(), NewContext(), rand, "Value_$pad($rand(3),X)!")
 		assert.True(t, result == "Value_!" || result == "Value_X!" || result == "Value_XX!")
 	}
 
 	n := sequence + 1
 	for i := n; i <= n+5; i++ {
-		result := getArg(NewContext(), rand, "Value_$seq()!")
+		result := getArg(cliconfig.New(), NewContext(), rand, "Value_$seq()!")
 		assert.True(t, result == fmt.Sprintf("Value_%d!", i))
 	}
 
 	n = sequence + 1
 	for i := n; i <= n+5; i++ {
 		ctxt := NewContext()
-		assert.Equal(t, fmt.Sprintf("Key_%d=Val_%d", i, i), getArg(ctxt, rand, "Key_$set(x,$seq())=Val_${x}"))
-		assert.Equal(t, fmt.Sprintf("Value_%d!", i), getArg(ctxt, rand, "Value_${x}!"))
+		assert.Equal(t, fmt.Sprintf("Key_%d=Val_%d", i, i), getArg(cliconfig.New(), ctxt, rand, "Key_$set(x,$seq())=Val_${x}"))
+		assert.Equal(t, fmt.Sprintf("Value_%d!", i), getArg(cliconfig.New(), ctxt, rand, "Value_${x}!"))
 	}
 }
@@ -11,21 +11,21 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var channelCmd = &cobra.Command{
-	Use:   "channel",
-	Short: "Channel commands",
-	Long:  "Channel commands",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.HelpFunc()(cmd, args)
-	},
-}
-
 // Cmd returns the channel command
-func Cmd() *cobra.Command {
-	cliconfig.InitChannelID(channelCmd.Flags())
+func Cmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	channelCmd := &cobra.Command{
+		Use:   "channel",
+		Short: "Channel commands",
+		Long:  "Channel commands",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	cfg.InitChannelID(channelCmd.Flags())
 
-	channelCmd.AddCommand(getChannelCreateCmd())
-	channelCmd.AddCommand(getChannelJoinCmd())
+	channelCmd.AddCommand(getChannelCreateCmd(cfg))
+	channelCmd.AddCommand(getChannelJoinCmd(cfg))
 
 	return channelCmd
 }
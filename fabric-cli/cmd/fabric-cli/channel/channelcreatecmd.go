@@ -18,31 +18,31 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var channelCreateCmd = &cobra.Command{
-	Use:   "create",
-	Short: "Create Channel",
-	Long:  "Create a new channel",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newChannelCreateAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing channelCreateAction: %v", err)
-			return
-		}
-
-		defer action.Terminate()
-
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running channelCreateAction: %v", err)
-		}
-	},
-}
+func getChannelCreateCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	channelCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create Channel",
+		Long:  "Create a new channel",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newChannelCreateAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing channelCreateAction: %v", err)
+				return
+			}
+
+			defer action.Terminate()
+
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running channelCreateAction: %v", err)
+			}
+		},
+	}
 
-func getChannelCreateCmd() *cobra.Command {
 	flags := channelCreateCmd.Flags()
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitOrdererURL(flags)
-	cliconfig.InitTxFile(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitOrdererURL(flags)
+	cfg.InitTxFile(flags)
 	return channelCreateCmd
 }
 
@@ -50,9 +50,9 @@ type channelCreateAction struct {
 	action.Action
 }
 
-func newChannelCreateAction(flags *pflag.FlagSet) (*channelCreateAction, error) {
+func newChannelCreateAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*channelCreateAction, error) {
 	a := &channelCreateAction{}
-	err := a.Initialize(flags)
+	err := a.Initialize(cfg, flags)
 	return a, err
 }
 
@@ -67,11 +67,11 @@ func (a *channelCreateAction) invoke() error {
 		return err
 	}
 
-	fmt.Printf("Attempting to create/update channel: %s\n", cliconfig.Config().ChannelID())
+	fmt.Printf("Attempting to create/update channel: %s\n", a.Config().ChannelID())
 
 	req := resmgmt.SaveChannelRequest{
-		ChannelID:         cliconfig.Config().ChannelID(),
-		ChannelConfigPath: cliconfig.Config().TxFile(),
+		ChannelID:         a.Config().ChannelID(),
+		ChannelConfigPath: a.Config().TxFile(),
 		SigningIdentities: []msp.SigningIdentity{user},
 	}
 
@@ -85,7 +85,7 @@ func (a *channelCreateAction) invoke() error {
 		return errors.Errorf("Error from save channel: %s", err.Error())
 	}
 
-	fmt.Printf("Channel created/updated: %s\n", cliconfig.Config().ChannelID())
+	fmt.Printf("Channel created/updated: %s\n", a.Config().ChannelID())
 
 	return nil
 }
@@ -9,97 +9,68 @@ package channel
 import (
 	"fmt"
 
-	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
-	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
-	"github.com/pkg/errors"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-var chainJoinCmd = &cobra.Command{
-	Use:   "join",
-	Short: "Join Channel",
-	Long:  "Join a channel",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newChannelJoinAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing channelJoinAction: %v", err)
-			return
-		}
-
-		defer action.Terminate()
-
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running channelJoinAction: %v", err)
-		}
-	},
-}
+func getChannelJoinCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	chainJoinCmd := &cobra.Command{
+		Use:   "join",
+		Short: "Join Channel",
+		Long:  "Join a channel",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newChannelJoinAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing channelJoinAction: %v", err)
+				return
+			}
+
+			defer action.Close()
+
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running channelJoinAction: %v", err)
+			}
+		},
+	}
 
-func getChannelJoinCmd() *cobra.Command {
 	flags := chainJoinCmd.Flags()
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitOrdererURL(flags)
-	cliconfig.InitPeerURL(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitOrdererURL(flags)
+	cfg.InitPeerURL(flags)
 	return chainJoinCmd
 }
 
 type channelJoinAction struct {
-	action.Action
+	*fabriccli.Client
 }
 
-func newChannelJoinAction(flags *pflag.FlagSet) (*channelJoinAction, error) {
-	action := &channelJoinAction{}
-	err := action.Initialize(flags)
+func newChannelJoinAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*channelJoinAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
 	if err != nil {
 		return nil, err
 	}
-	if len(action.Peers()) == 0 {
-		return nil, errors.Errorf("at least one peer is required for join")
-	}
-	return action, nil
+	return &channelJoinAction{Client: client}, nil
 }
 
 func (a *channelJoinAction) invoke() error {
-	fmt.Printf("Attempting to join channel: %s\n", cliconfig.Config().ChannelID())
-
-	var lastErr error
-	for orgID, peers := range a.PeersByOrg() {
-		fmt.Printf("Joining channel %s on org[%s] peers:\n", cliconfig.Config().ChannelID(), orgID)
-		for _, peer := range peers {
-			fmt.Printf("-- %s\n", peer.URL())
-		}
-		err := a.joinChannel(orgID, peers)
-		if err != nil {
-			lastErr = err
-		}
-	}
-	return lastErr
-}
-
-func (a *channelJoinAction) joinChannel(orgID string, peers []fab.Peer) error {
-	cliconfig.Config().Logger().Debugf("Joining channel [%s]...\n", cliconfig.Config().ChannelID())
-
-	fmt.Printf("==========> JOIN ORG: %s\n", orgID)
-
-	resMgmtClient, err := a.ResourceMgmtClientForOrg(orgID)
-	if err != nil {
-		return err
-	}
-
-	orderer, err := a.RandomOrderer()
-	if err != nil {
-		return err
-	}
-
-	err = resMgmtClient.JoinChannel(cliconfig.Config().ChannelID(), resmgmt.WithTargets(peers...), resmgmt.WithOrderer(orderer))
-	if err != nil {
-		return errors.WithMessage(err, "Could not join channel: %v")
-	}
-
-	fmt.Printf("Channel %s joined!\n", cliconfig.Config().ChannelID())
-
-	return nil
+	fmt.Printf("Attempting to join channel: %s\n", a.Config().ChannelID())
+
+	_, err := a.JoinChannel(a.Config().Context(), fabriccli.JoinChannelRequest{
+		OnJoin: func(orgID string, peerURLs []string) {
+			fmt.Printf("Joining channel %s on org[%s] peers:\n", a.Config().ChannelID(), orgID)
+			for _, url := range peerURLs {
+				fmt.Printf("-- %s\n", url)
+			}
+			fmt.Printf("==========> JOIN ORG: %s\n", orgID)
+		},
+		OnResult: func(result fabriccli.JoinChannelResult) {
+			if result.Err == nil {
+				fmt.Printf("Channel %s joined!\n", a.Config().ChannelID())
+			}
+		},
+	})
+	return err
 }
@@ -7,22 +7,41 @@ SPDX-License-Identifier: Apache-2.0
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode"
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/channel"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/discover"
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/event"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/identity"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/inspect"
 	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/query"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/settings"
 	"github.com/spf13/cobra"
 )
 
-func newFabricCLICmd() *cobra.Command {
+// NewFabricCLICmd returns the fabric-cli command with its own configuration. Each invocation returns
+// an independent command so that several commands may be run in the same process.
+func NewFabricCLICmd() *cobra.Command {
+	return NewFabricCLICmdWithContext(context.Background())
+}
+
+// NewFabricCLICmdWithContext returns the fabric-cli command with its own configuration. Commands stop
+// starting new work when the given context is cancelled.
+func NewFabricCLICmdWithContext(ctx context.Context) *cobra.Command {
+	cfg := cliconfig.New()
+	cfg.SetContext(ctx)
 
 	mainCmd := &cobra.Command{
 		Use: "fabric-cli",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			return nil
+			// Flags that aren't specified are taken from environment variables or the active profile
+			return cfg.ApplySettings(cmd.Flags())
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.HelpFunc()(cmd, args)
@@ -30,26 +49,69 @@ func newFabricCLICmd() *cobra.Command {
 	}
 
 	flags := mainCmd.PersistentFlags()
-	cliconfig.InitConfigFile(flags)
-	cliconfig.InitLoggingLevel(flags)
-	cliconfig.InitUserName(flags)
-	cliconfig.InitUserPassword(flags)
-	cliconfig.InitOrdererTLSCertificate(flags)
-	cliconfig.InitPrintFormat(flags)
-	cliconfig.InitWriter(flags)
-	cliconfig.InitBase64(flags)
-	cliconfig.InitOrgIDs(flags)
-
-	mainCmd.AddCommand(chaincode.Cmd())
-	mainCmd.AddCommand(query.Cmd())
-	mainCmd.AddCommand(channel.Cmd())
-	mainCmd.AddCommand(event.Cmd())
+	cfg.InitProfile(flags)
+	cfg.InitConfigFile(flags)
+	cfg.InitSkipPreflight(flags)
+	cfg.InitLoggingLevel(flags)
+	cfg.InitUserName(flags)
+	cfg.InitUserPassword(flags)
+	cfg.InitSigningCert(flags)
+	cfg.InitSigningKey(flags)
+	cfg.InitPKCS12(flags)
+	cfg.InitMSPDir(flags)
+	cfg.InitOrdererTLSCertificate(flags)
+	cfg.InitPrintFormat(flags)
+	cfg.InitWriter(flags)
+	cfg.InitRotateSize(flags)
+	cfg.InitRotateInterval(flags)
+	cfg.InitCompress(flags)
+	cfg.InitFsync(flags)
+	cfg.InitBase64(flags)
+	cfg.InitDepth(flags)
+	cfg.InitNoColor(flags)
+	cfg.InitDecodePayloads(flags)
+	cfg.InitMessageTypes(flags)
+	cfg.InitDecoders(flags)
+	cfg.InitOrgIDs(flags)
+	cfg.InitTimeout(flags)
+	cfg.InitConnectionTimeout(flags)
+	cfg.InitEndorsementTimeout(flags)
+	cfg.InitCommitTimeout(flags)
+	cfg.InitOrdererTimeout(flags)
+	cfg.InitDiscoveryTimeout(flags)
+	cfg.InitQueryTimeout(flags)
+
+	mainCmd.AddCommand(chaincode.Cmd(cfg))
+	mainCmd.AddCommand(query.Cmd(cfg))
+	mainCmd.AddCommand(channel.Cmd(cfg))
+	mainCmd.AddCommand(event.Cmd(cfg))
+	mainCmd.AddCommand(identity.Cmd(cfg))
+	mainCmd.AddCommand(discover.Cmd(cfg))
+	mainCmd.AddCommand(inspect.Cmd(cfg))
+	mainCmd.AddCommand(settings.Cmd(cfg))
 
 	return mainCmd
 }
 
+// Execute runs the fabric-cli command. On the first interrupt the command's context is cancelled so
+// that it stops submitting requests, waits for the requests in flight, prints its results and closes
+// the SDK. A second interrupt exits immediately.
 func Execute() {
-	if newFabricCLICmd().Execute() != nil {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		fmt.Fprintln(os.Stderr, "\nInterrupted. Waiting for requests in flight to complete (interrupt again to exit immediately)...")
+		cancel()
+		<-signals
+		fmt.Fprintln(os.Stderr, "Exiting")
+		os.Exit(130)
+	}()
+
+	if NewFabricCLICmdWithContext(ctx).Execute() != nil {
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,61 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredFlagsFromProfile(t *testing.T) {
+	home, err := ioutil.TempDir("", "fabric-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	os.Setenv(cliconfig.HomeEnvVar, home)
+	defer os.Unsetenv(cliconfig.HomeEnvVar)
+
+	args := []string{"chaincode", "query", "--config", "/nonexistent/config.yaml"}
+
+	// Without a profile the chaincode ID is missing
+	assert.Contains(t, execute(t, args...), "Must specify the chaincode ID")
+
+	settings, err := cliconfig.LoadSettings()
+	require.NoError(t, err)
+	settings.Set("test", cliconfig.ChannelIDFlag, "orgchannel")
+	settings.Set("test", cliconfig.ChaincodeIDFlag, "examplecc")
+	settings.CurrentProfile = "test"
+	require.NoError(t, settings.Save())
+
+	// The profile supplies the channel and chaincode IDs so the command gets as far as reading the connection profile
+	assert.NotContains(t, execute(t, args...), "Must specify")
+}
+
+// execute runs the fabric-cli command with the given arguments and returns its standard output
+func execute(t *testing.T, args ...string) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	cmd := NewFabricCLICmd()
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	w.Close()
+	require.NoError(t, err)
+
+	out, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
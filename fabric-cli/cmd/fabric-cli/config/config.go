@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package config
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -40,6 +41,24 @@ const (
 
 	// FabricSelectionProvider indicates that the Fabric selection provider is to be used for selecting peers for invoke/query commands
 	FabricSelectionProvider = "fabric"
+
+	// RulesSelectionProvider indicates that peers for invoke/query commands are to be selected according to the rules in a local YAML file
+	RulesSelectionProvider = "rules"
+
+	// AllTargetStrategy indicates that each request is sent to all of the target peers
+	AllTargetStrategy = "all"
+
+	// RoundRobinTargetStrategy indicates that the target peers of each organization are chosen in turn
+	RoundRobinTargetStrategy = "round-robin"
+
+	// RandomTargetStrategy indicates that a target peer of each organization is chosen at random
+	RandomTargetStrategy = "random"
+
+	// LeastLatencyTargetStrategy indicates that the target peer of each organization with the lowest average latency is chosen
+	LeastLatencyTargetStrategy = "least-latency"
+
+	// LeastInflightTargetStrategy indicates that the target peer of each organization with the fewest requests in flight is chosen
+	LeastInflightTargetStrategy = "least-inflight"
 )
 
 // Flags
@@ -76,6 +95,10 @@ const (
 	chaincodePathDescription = "The chaincode path"
 	defaultChaincodePath     = ""
 
+	ProfileFlag        = "profile"
+	profileDescription = "The name of the profile in the settings file ($FABRIC_CLI_HOME/config.yaml or ~/.fabric-cli/config.yaml) from which to take the values of flags that aren't specified"
+	defaultProfile     = ""
+
 	ConfigFileFlag        = "config"
 	configFileDescription = "The path of the config.yaml file"
 	defaultConfigFile     = ""
@@ -92,15 +115,91 @@ const (
 	printFormatDescription = "The output format - display, json, raw"
 
 	WriterFlag        = "writer"
-	writerDescription = "The writer - stdout, stderr, log"
+	writerDescription = "The writer - stdout, stderr, log or file:<path> (e.g. file:/tmp/blocks.json)"
+
+	RotateSizeFlag        = "rotatesize"
+	rotateSizeDescription = "The size in MB after which the output file is rotated - 0 disables size-based rotation (only applies to the 'file' writer)"
+	defaultRotateSize     = "0"
+
+	RotateIntervalFlag        = "rotateinterval"
+	rotateIntervalDescription = "The interval in seconds after which the output file is rotated - 0 disables time-based rotation (only applies to the 'file' writer)"
+	defaultRotateInterval     = "0"
+
+	CompressFlag        = "compress"
+	compressDescription = "If true then rotated output files are compressed with gzip (only applies to the 'file' writer)"
+
+	FsyncFlag        = "fsync"
+	fsyncDescription = "The policy for syncing the output file to disk - 'never', 'always' (after every block/event) or the minimum number of milliseconds between syncs (only applies to the 'file' writer)"
+	defaultFsync     = "never"
 
 	Base64Flag        = "base64"
 	base64Description = "If true then binary values are encoded in base64 (only applies to 'display' format)"
 
+	DepthFlag        = "depth"
+	depthDescription = "The maximum depth of nested elements that are displayed - deeper elements are collapsed into a summary. 0 displays all elements (only applies to 'display' format)"
+	defaultDepth     = "0"
+
+	NoColorFlag        = "nocolor"
+	noColorDescription = "If true then output to a terminal is not colorized (only applies to 'display' format)"
+
+	SkipPreflightFlag        = "skippreflight"
+	skipPreflightDescription = "If true then the connection profile isn't validated before the command is run"
+
+	DecodePayloadsFlag        = "decode"
+	decodePayloadsDescription = "If true then the content of binary payloads (KV write values, chaincode responses and chaincode events) is detected and decoded as UTF-8 text, JSON, protobuf or a hex dump (only applies to 'display' format)"
+
+	MessageTypesFlag        = "msgtypes"
+	messageTypesDescription = "A comma-separated list of chaincode ID to protobuf message type mappings used when decoding payloads, e.g. 'examplecc=common.Envelope,othercc=protos.ChaincodeEvent' (only applies with --decode)"
+	defaultMessageTypes     = ""
+
+	DecodersFlag        = "decoders"
+	decodersDescription = "The path of a YAML file that maps chaincode IDs (or 'chaincodeID/keyPrefix') to payload decoders (json, protobuf, cbor, msgpack or exec). Implies --decode"
+	defaultDecoders     = ""
+
+	CryptoConfigFlag        = "cryptoconfig"
+	cryptoConfigDescription = "The path of the crypto-config directory (generated by cryptogen or Fabric CA) that contains the peerOrganizations and ordererOrganizations directories"
+	defaultCryptoConfig     = ""
+
+	EndpointsFlag        = "endpoints"
+	endpointsDescription = "A comma-separated list of host to URL mappings of peers, orderers and CAs, e.g. 'peer0.org1.example.com=localhost:7051,orderer.example.com=localhost:7050' (peers and orderers that aren't specified use host:7051 and host:7050)"
+	defaultEndpoints     = ""
+
+	MSPIDsFlag        = "mspids"
+	mspIDsDescription = "A comma-separated list of organization to MSP ID mappings, e.g. 'org1.example.com=Org1MSP' (by default the MSP ID is derived from the organization's domain)"
+	defaultMSPIDs     = ""
+
+	ChannelIDsFlag        = "channels"
+	channelIDsDescription = "A comma-separated list of channel IDs"
+	defaultChannelIDs     = ""
+
+	StateStoreFlag        = "statestore"
+	stateStoreDescription = "The path of the SDK credential store in the generated connection profile (the crypto store is its msp subdirectory)"
+	defaultStateStore     = "/tmp/state-store"
+
+	OutputFlag        = "output"
+	outputDescription = "The path of the output file (stdout if not specified)"
+	defaultOutput     = ""
+
 	CertificateFileFlag    = "cacert"
 	certificateDescription = "The path of the ca-cert.pem file"
 	defaultCertificate     = ""
 
+	SigningCertFlag        = "certfile"
+	signingCertDescription = "The path of the PEM enrollment certificate of the signing identity (used with --keyfile instead of a user from the credential store)"
+	defaultSigningCert     = ""
+
+	SigningKeyFlag        = "keyfile"
+	signingKeyDescription = "The path of the PEM private key of the signing identity (used with --certfile). If the key is encrypted then the passphrase is taken from FABRIC_CLI_KEY_PASSPHRASE or prompted for."
+	defaultSigningKey     = ""
+
+	PKCS12Flag        = "pkcs12"
+	pkcs12Description = "The path of a PKCS#12 bundle containing the certificate and private key of the signing identity. If the bundle is protected then the password is taken from FABRIC_CLI_KEY_PASSPHRASE or prompted for."
+	defaultPKCS12     = ""
+
+	MSPDirFlag        = "mspdir"
+	mspDirDescription = "The path of a directory in MSP layout (signcerts and keystore) containing the certificate and private key of the signing identity"
+	defaultMSPDir     = ""
+
 	ArgsFlag        = "args"
 	argsDescription = `The args in JSON format. Example: {"Func":"function","Args":["arg1","arg2"]}. Note that $rand(N) may be used anywhere within the value of the arg in order to generate a random value between 0 and N. For example {"Func":"function","Args":["arg_$rand(100)","$rand(10)"]}.`
 
@@ -149,8 +248,32 @@ const (
 	defaultCollectionConfigFile     = ""
 
 	TimeoutFlag        = "timeout"
-	timeoutDescription = "The timeout (in milliseconds) for the operation"
-	defaultTimeout     = "5000"
+	timeoutDescription = "The timeout (in milliseconds) of all network operations whose timeout isn't specified with its own flag. If not specified then the timeouts from the connection profile are used or, if they aren't in the connection profile, the defaults of the individual timeout flags."
+	defaultTimeout     = "0"
+
+	ConnectionTimeoutFlag        = "connectiontimeout"
+	connectionTimeoutDescription = "The timeout (in milliseconds) for establishing a connection to a peer, orderer or discovery service and for registering with the event service"
+	defaultConnectionTimeout     = "10000"
+
+	EndorsementTimeoutFlag        = "endorsementtimeout"
+	endorsementTimeoutDescription = "The timeout (in milliseconds) for a peer to respond to a proposal"
+	defaultEndorsementTimeout     = "30000"
+
+	CommitTimeoutFlag        = "committimeout"
+	commitTimeoutDescription = "The overall timeout (in milliseconds) of a transaction, including waiting for the commit event. This is also the overall timeout of resource management operations (e.g. join, install, instantiate)"
+	defaultCommitTimeout     = "60000"
+
+	OrdererTimeoutFlag        = "orderertimeout"
+	ordererTimeoutDescription = "The timeout (in milliseconds) for the orderer to respond to a broadcast or deliver request"
+	defaultOrdererTimeout     = "30000"
+
+	DiscoveryTimeoutFlag        = "discoverytimeout"
+	discoveryTimeoutDescription = "The timeout (in milliseconds) for the discovery service to respond"
+	defaultDiscoveryTimeout     = "15000"
+
+	QueryTimeoutFlag        = "querytimeout"
+	queryTimeoutDescription = "The overall timeout (in milliseconds) of a chaincode or ledger query"
+	defaultQueryTimeout     = "30000"
 
 	PrintPayloadOnlyFlag        = "payload"
 	printPayloadOnlyDescription = "If specified then only the payload from the transaction proposal response(s) will be output"
@@ -181,20 +304,137 @@ const (
 	defaultBackoffFactor     = "2"
 
 	VerboseFlag        = "verbose"
-	verboseDescription = "If specified then the transaction proposal responses will be output when iterations > 1, otherwise transaction proposal responses are only output when iterations = 1"
+	verboseDescription = "If specified then the transaction proposal responses will be output when iterations > 1, otherwise transaction proposal responses are only output when iterations = 1. The endorsers selected for each request are also output and, for invoke, the endorsement policy of the chaincode and the groups of peers that satisfy it"
 	defaultVerbosity   = "false"
 
 	SelectionProviderFlag        = "selectprovider"
-	selectionProviderDescription = "The peer selection provider for invoke/query commands. The possible values are: (1) static - Selects all peers; (2) dynamic - Uses the built-in selection service from the SDK to select a minimal set of peers according to the endorsement policy of the chaincode; (3) fabric - Uses Fabric's Discovery Service to select a minimal set of peers according to the endorsement/collection policy of the chaincode; (4) auto (default) - Automatically determines which selection service to use based on channel capabilities."
+	selectionProviderDescription = "The peer selection provider for invoke/query commands. The possible values are: (1) static - Selects all peers; (2) dynamic - Uses the built-in selection service from the SDK to select a minimal set of peers according to the endorsement policy of the chaincode; (3) fabric - Uses Fabric's Discovery Service to select a minimal set of peers according to the endorsement/collection policy of the chaincode; (4) rules - Selects peers deterministically according to the rules in the file given by --selectionrules; (5) auto (default) - Automatically determines which selection service to use based on channel capabilities."
 	defaultSelectionProvider     = AutoDetectSelectionProvider
 
 	GoPathFlag        = "gopath"
 	goPathDescription = "GOPATH for chaincode install command. If not set, GOPATH is taken from the environment"
 	defaultGoPath     = ""
-)
 
-var opts *options
-var instance *CLIConfig
+	EnrollmentIDFlag        = "name"
+	enrollmentIDDescription = "The enrollment ID of the identity"
+	defaultEnrollmentID     = ""
+
+	SecretFlag        = "secret"
+	secretDescription = "The enrollment secret of the identity. When registering, the CA generates a secret if none is specified."
+	defaultSecret     = ""
+
+	IdentityTypeFlag        = "type"
+	identityTypeDescription = "The type of the identity (e.g. client, peer, orderer, admin or user)"
+	defaultIdentityType     = "client"
+
+	AffiliationFlag        = "affiliation"
+	affiliationDescription = "The affiliation of the identity (e.g. org1.department1)"
+	defaultAffiliation     = ""
+
+	AttributesFlag        = "attrs"
+	attributesDescription = "A comma-separated list of attributes of the identity in the form name=value[:ecert], e.g. 'role=loadtester:ecert,level=2'. If :ecert is specified then the attribute is added to the enrollment certificate by default."
+	defaultAttributes     = ""
+
+	MaxEnrollmentsFlag        = "maxenrollments"
+	maxEnrollmentsDescription = "The number of times the secret may be used to enroll (0 uses the CA's default, -1 is unlimited)"
+	defaultMaxEnrollments     = "0"
+
+	RevocationReasonFlag        = "reason"
+	revocationReasonDescription = "The reason for revocation: unspecified, keycompromise, cacompromise, affiliationchange, superseded, cessationofoperation, certificatehold, removefromcrl, privilegewithdrawn or aacompromise"
+	defaultRevocationReason     = ""
+
+	SerialFlag        = "serial"
+	serialDescription = "The serial number (hex) of the certificate to revoke (used with --aki instead of --name)"
+	defaultSerial     = ""
+
+	AKIFlag        = "aki"
+	akiDescription = "The authority key identifier (hex) of the certificate to revoke (used with --serial)"
+	defaultAKI     = ""
+
+	CountFlag        = "count"
+	countDescription = "The number of identities to provision"
+	defaultCount     = "1"
+
+	PrefixFlag        = "prefix"
+	prefixDescription = "The prefix of the enrollment IDs of provisioned identities. A sequence number starting at 1 is appended to the prefix, e.g. user1, user2, ..."
+	defaultPrefix     = "user"
+
+	IdentityManifestFlag        = "identities"
+	identityManifestDescription = "The path of an identity manifest written by 'identity provision'. If specified then requests are spread across the identities in the manifest instead of being sent by a single user."
+	defaultIdentityManifest     = ""
+
+	HealthIntervalFlag        = "healthinterval"
+	healthIntervalDescription = "If greater than 0 then the target peers are health checked before and, at this interval (in milliseconds), during chaincode invoke/query. Peers that are down are excluded from the targets until they recover."
+	defaultHealthInterval     = "0"
+
+	TargetStrategyFlag        = "targetstrategy"
+	targetStrategyDescription = "The strategy used to choose the targets of each invoke/query request from the peers given by --peer or --orgid. The possible values are: (1) all (default) - Sends each request to all of the peers; (2) round-robin - Chooses the peers of each organization in turn; (3) random - Chooses a peer of each organization at random; (4) least-latency - Chooses the peer of each organization with the lowest average latency; (5) least-inflight - Chooses the peer of each organization with the fewest requests in flight. Except for 'all', one peer per organization is chosen for each request."
+	defaultTargetStrategy     = AllTargetStrategy
+
+	SelectionRulesFlag        = "selectionrules"
+	selectionRulesDescription = "The path of a YAML file containing the peer selection rules (preferred, priority and excluded peers per organization and the maximum number of peers per organization per chaincode). Used with --selectprovider=rules"
+	defaultSelectionRules     = ""
+
+	InvocationChainFlag        = "invocationchain"
+	invocationChainDescription = "A comma-separated chaincode-to-chaincode invocation chain, starting with the invoked chaincode. Each chaincode may be followed by the collections that it accesses, e.g. examplecc:coll1:coll2,othercc"
+	defaultInvocationChain     = ""
+
+	WatchIntervalFlag        = "watch"
+	watchIntervalDescription = "If greater than 0 then the command is repeated at this interval (in milliseconds) until interrupted"
+	defaultWatchInterval     = "0"
+
+	FromBlockFlag        = "from"
+	fromBlockDescription = "The number of the first block in the range"
+	defaultFromBlock     = "0"
+
+	ToBlockFlag        = "to"
+	toBlockDescription = "The number of the last block in the range (the last block on the channel if not specified)"
+	defaultToBlock     = "0"
+
+	BlockFormatFlag        = "blockformat"
+	blockFormatDescription = "The format in which blocks are written - proto (raw protobuf), json or both"
+	defaultBlockFormat     = "proto"
+
+	InputFlag        = "input"
+	inputDescription = "The path of blocks on disk (a block file, a .block file, or a directory or archive written by 'query blocks') that are read instead of querying the channel"
+	defaultInput     = ""
+
+	FunctionFlag        = "function"
+	functionDescription = "Only include transactions that invoked this chaincode function"
+	defaultFunction     = ""
+
+	CreatorMSPFlag        = "creatormsp"
+	creatorMSPDescription = "Only include transactions created by an identity of this MSP"
+	defaultCreatorMSP     = ""
+
+	ValidationCodeFlag        = "validationcode"
+	validationCodeDescription = "Only include transactions with this validation code - valid, invalid (any code other than VALID) or a validation code name, e.g. MVCC_READ_CONFLICT"
+	defaultValidationCode     = ""
+
+	WriteKeyFlag        = "writekey"
+	writeKeyDescription = "Only include transactions that wrote this key"
+	defaultWriteKey     = ""
+
+	WriteKeyPrefixFlag        = "writekeyprefix"
+	writeKeyPrefixDescription = "Only include transactions that wrote a key with this prefix"
+	defaultWriteKeyPrefix     = ""
+
+	SinceFlag        = "since"
+	sinceDescription = "Only include transactions with a timestamp at or after this time (RFC3339, e.g. 2020-01-02T15:04:05Z)"
+	defaultSince     = ""
+
+	UntilFlag        = "until"
+	untilDescription = "Only include transactions with a timestamp at or before this time (RFC3339, e.g. 2020-01-02T15:04:05Z)"
+	defaultUntil     = ""
+
+	FullFlag        = "full"
+	fullDescription = "Print the full transactions instead of summaries"
+	defaultFull     = "false"
+
+	StateKeyFlag        = "key"
+	stateKeyDescription = "The key in the chaincode's state"
+	defaultStateKey     = ""
+)
 
 type options struct {
 	certificate          string
@@ -216,6 +456,23 @@ type options struct {
 	printFormat          string
 	writer               string
 	base64               bool
+	decodePayloads       bool
+	messageTypes         string
+	decoders             string
+	profile              string
+	rotateSize           int64
+	depth                int
+	noColor              bool
+	skipPreflight        bool
+	cryptoConfig         string
+	endpoints            string
+	mspIDs               string
+	channelIDs           string
+	stateStore           string
+	output               string
+	rotateInterval       int64
+	compress             bool
+	fsync                string
 	args                 string
 	chaincodeEvent       string
 	seekType             string
@@ -225,6 +482,12 @@ type options struct {
 	chaincodePolicy      string
 	collectionConfigFile string
 	timeout              int64
+	connectionTimeout    int64
+	endorsementTimeout   int64
+	commitTimeout        int64
+	ordererTimeout       int64
+	discoveryTimeout     int64
+	queryTimeout         int64
 	printPayloadOnly     bool
 	validate             bool
 	concurrency          int
@@ -235,61 +498,151 @@ type options struct {
 	verbose              bool
 	selectionProvider    string
 	goPath               string
-}
-
-func init() {
-	opts = &options{
-		user:             defaultUser,
-		password:         defaultPassword,
-		loggingLevel:     defaultLoggingLevel,
-		channelID:        defaultChannelID,
-		orgIDsStr:        defaultOrgIDs,
-		chaincodeVersion: defaultChaincodeVersion,
-		iterations:       1,
-		concurrency:      1,
-		args:             getEmptyArgs(),
-	}
-}
-
-// CLIConfig overrides certain configuration values with those supplied on the command-line
+	signingCert          string
+	signingKey           string
+	pkcs12               string
+	mspDir               string
+	enrollmentID         string
+	secret               string
+	identityType         string
+	affiliation          string
+	attributes           string
+	maxEnrollments       int
+	revocationReason     string
+	serial               string
+	aki                  string
+	count                int
+	prefix               string
+	identityManifest     string
+	healthInterval       int64
+	targetStrategy       string
+	selectionRules       string
+	invocationChain      string
+	watchInterval        int64
+	fromBlock            uint64
+	toBlock              uint64
+	blockFormat          string
+	input                string
+	function             string
+	creatorMSP           string
+	validationCode       string
+	writeKey             string
+	writeKeyPrefix       string
+	since                string
+	until                string
+	full                 bool
+	stateKey             string
+}
+
+// CLIConfig overrides certain configuration values with those supplied on the command-line.
+// The flags are bound to the config with the Init functions and the values are available once
+// InitConfig has been called. Each command (or embedding program) uses its own CLIConfig so
+// that several commands may be run in the same process.
 type CLIConfig struct {
 	core.ConfigProvider
-	logger   *logging.Logger
-	setFlags map[string]string
+	logger          *logging.Logger
+	setFlags        map[string]string
+	settingsFlags   map[string]string
+	opts            *options
+	ignoreSettings  bool
+	settingsApplied bool
+	ctx             context.Context
+}
+
+// New returns a new CLI configuration with default values
+func New() *CLIConfig {
+	return &CLIConfig{
+		logger:        logging.NewLogger(loggerName),
+		setFlags:      make(map[string]string),
+		settingsFlags: make(map[string]string),
+		opts: &options{
+			user:             defaultUser,
+			password:         defaultPassword,
+			loggingLevel:     defaultLoggingLevel,
+			channelID:        defaultChannelID,
+			orgIDsStr:        defaultOrgIDs,
+			chaincodeVersion: defaultChaincodeVersion,
+			iterations:       1,
+			concurrency:      1,
+			args:             getEmptyArgs(),
+		},
+	}
 }
 
-// InitConfig initializes the configuration
-func InitConfig(flags *pflag.FlagSet) error {
-
-	instance = &CLIConfig{
-		logger:   logging.NewLogger(loggerName),
-		setFlags: make(map[string]string),
+// InitConfig initializes the configuration from the given flags
+func (c *CLIConfig) InitConfig(flags *pflag.FlagSet) error {
+	if err := c.ApplySettings(flags); err != nil {
+		return err
 	}
+
+	c.setFlags = make(map[string]string)
 	flags.Visit(func(flag *pflag.Flag) {
-		instance.setFlags[flag.Name] = flag.Value.String()
+		c.setFlags[flag.Name] = flag.Value.String()
 	})
 
-	cnfg := config.FromFile(opts.configFile)
+	c.ConfigProvider = c.withTimeouts(config.FromFile(c.opts.configFile))
+
+	return nil
+}
+
+// ApplySettings sets the flags that weren't specified on the command line from environment variables
+// or the active profile. It's called before the commands validate their flags so that required flags
+// (e.g. --cid) may be taken from the settings. The settings are only applied once.
+func (c *CLIConfig) ApplySettings(flags *pflag.FlagSet) error {
+	if c.ignoreSettings || c.settingsApplied {
+		return nil
+	}
 
-	instance.ConfigProvider = cnfg
+	applied, err := applySettings(flags)
+	if err != nil {
+		return err
+	}
+	c.settingsFlags = applied
+	c.settingsApplied = true
 
 	return nil
 }
 
-// IsFlagSet indicates whether or not the given flag is set
-func IsFlagSet(name string) bool {
-	_, ok := instance.setFlags[name]
+// IgnoreSettings prevents the settings file and FABRIC_CLI_* environment variables from being
+// applied to flags that weren't set. This is used by programs that embed the CLI so that only
+// the values that they provide are used.
+func (c *CLIConfig) IgnoreSettings() *CLIConfig {
+	c.ignoreSettings = true
+	return c
+}
+
+// IsFlagSet indicates whether or not the given flag is set on the command line
+func (c *CLIConfig) IsFlagSet(name string) bool {
+	_, ok := c.setFlags[name]
+	return ok
+}
+
+// IsFlagSpecified indicates whether or not the given flag is set on the command line,
+// by an environment variable or in the active profile
+func (c *CLIConfig) IsFlagSpecified(name string) bool {
+	if c.IsFlagSet(name) {
+		return true
+	}
+	_, ok := c.settingsFlags[name]
 	return ok
 }
 
 // Provider returns the config provider
-func Provider() core.ConfigProvider {
-	return instance.ConfigProvider
+func (c *CLIConfig) Provider() core.ConfigProvider {
+	return c.ConfigProvider
+}
+
+// Context returns the context of the command. It's cancelled when the command is interrupted.
+func (c *CLIConfig) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
-// Config returns the CLI configuration
-func Config() *CLIConfig {
-	return instance
+// SetContext sets the context of the command
+func (c *CLIConfig) SetContext(ctx context.Context) {
+	c.ctx = ctx
 }
 
 // Logger returns the Logger for the CLI tool
@@ -299,19 +652,35 @@ func (c *CLIConfig) Logger() *logging.Logger {
 
 // LoggingLevel specifies the logging level (DEBUG, INFO, WARNING, ERROR, or CRITICAL)
 func (c *CLIConfig) LoggingLevel() string {
-	return opts.loggingLevel
+	return c.opts.loggingLevel
 }
 
 // InitLoggingLevel initializes the logging level from the provided arguments
-func InitLoggingLevel(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitLoggingLevel(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultLoggingLevel, loggingLevelDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.loggingLevel, LoggingLevelFlag, defaultValue, description)
+	flags.StringVar(&c.opts.loggingLevel, LoggingLevelFlag, defaultValue, description)
+}
+
+// Profile returns the name of the profile specified with the --profile flag
+func (c *CLIConfig) Profile() string {
+	return c.opts.profile
+}
+
+// InitProfile initializes the profile name from the provided arguments
+func (c *CLIConfig) InitProfile(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultProfile, profileDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.profile, ProfileFlag, defaultValue, description)
+}
+
+// ConfigFile returns the path of the connection profile (config.yaml)
+func (c *CLIConfig) ConfigFile() string {
+	return c.opts.configFile
 }
 
 // InitConfigFile initializes the config file path from the provided arguments
-func InitConfigFile(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitConfigFile(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultConfigFile, configFileDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.configFile, ConfigFileFlag, defaultValue, description)
+	flags.StringVar(&c.opts.configFile, ConfigFileFlag, defaultValue, description)
 }
 
 // OrgID specifies the ID of the current organization. If multiple org IDs are specified then the first one is returned.
@@ -325,8 +694,8 @@ func (c *CLIConfig) OrgID() string {
 // OrgIDs returns a comma-separated list of organization IDs
 func (c *CLIConfig) OrgIDs() []string {
 	var orgIDs []string
-	if len(strings.TrimSpace(opts.orgIDsStr)) > 0 {
-		s := strings.Split(opts.orgIDsStr, ",")
+	if len(strings.TrimSpace(c.opts.orgIDsStr)) > 0 {
+		s := strings.Split(c.opts.orgIDsStr, ",")
 		for _, orgID := range s {
 			orgIDs = append(orgIDs, orgID)
 		}
@@ -335,64 +704,64 @@ func (c *CLIConfig) OrgIDs() []string {
 }
 
 // InitOrgIDs initializes the org IDs from the provided arguments
-func InitOrgIDs(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitOrgIDs(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultOrgIDs, orgIDsDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.orgIDsStr, OrgIDsFlag, defaultValue, description)
+	flags.StringVar(&c.opts.orgIDsStr, OrgIDsFlag, defaultValue, description)
 }
 
 // ChannelID returns the channel ID
 func (c *CLIConfig) ChannelID() string {
-	return opts.channelID
+	return c.opts.channelID
 }
 
 // InitChannelID initializes the channel ID from the provided arguments
-func InitChannelID(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitChannelID(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultChannelID, channelIDDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.channelID, ChannelIDFlag, defaultValue, description)
+	flags.StringVar(&c.opts.channelID, ChannelIDFlag, defaultValue, description)
 }
 
 // UserName returns the name of the enrolled user
 func (c *CLIConfig) UserName() string {
-	return opts.user
+	return c.opts.user
 }
 
 // InitUserName initializes the user name from the provided arguments
-func InitUserName(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitUserName(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultUser, userDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.user, UserFlag, defaultValue, description)
+	flags.StringVar(&c.opts.user, UserFlag, defaultValue, description)
 }
 
 // UserPassword is the password to use when enrolling a user
 func (c *CLIConfig) UserPassword() string {
-	return opts.password
+	return c.opts.password
 }
 
 // InitUserPassword initializes the user password from the provided arguments
-func InitUserPassword(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitUserPassword(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultPassword, passwordDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.password, PasswordFlag, defaultValue, description)
+	flags.StringVar(&c.opts.password, PasswordFlag, defaultValue, description)
 }
 
 // ChaincodeID returns the chaicode ID
 func (c *CLIConfig) ChaincodeID() string {
-	return opts.chaincodeID
+	return c.opts.chaincodeID
 }
 
 // InitChaincodeID initializes the chaincode ID from the provided arguments
-func InitChaincodeID(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitChaincodeID(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultChaincodeID, chaincodeIDDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.chaincodeID, ChaincodeIDFlag, defaultValue, description)
+	flags.StringVar(&c.opts.chaincodeID, ChaincodeIDFlag, defaultValue, description)
 }
 
 // ChaincodeEvent the name of the chaincode event to listen for
 func (c *CLIConfig) ChaincodeEvent() string {
-	return opts.chaincodeEvent
+	return c.opts.chaincodeEvent
 }
 
 // InitChaincodeEvent initializes the chaincode event name from the provided arguments
-func InitChaincodeEvent(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitChaincodeEvent(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultChaincodeEvent, chaincodeEventDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.chaincodeEvent, ChaincodeEventFlag, defaultValue, description)
+	flags.StringVar(&c.opts.chaincodeEvent, ChaincodeEventFlag, defaultValue, description)
 }
 
 // SeekType the seek type for Deliver Events. Possible values:
@@ -400,47 +769,47 @@ func InitChaincodeEvent(flags *pflag.FlagSet, defaultValueAndDescription ...stri
 // - Newest - will deliver the newest block and will continue listening for new blocks
 // - FromBlock - Delivers from the specific block, as specified by the "--num" flag
 func (c *CLIConfig) SeekType() seek.Type {
-	return seek.Type(opts.seekType)
+	return seek.Type(c.opts.seekType)
 }
 
 // InitSeekType initializes the seek type from the provided arguments
-func InitSeekType(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitSeekType(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultSeekType, seekTypeDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.seekType, SeekTypeFlag, defaultValue, description)
+	flags.StringVar(&c.opts.seekType, SeekTypeFlag, defaultValue, description)
 }
 
 // ChaincodePath returns the source path of the chaincode to install/instantiate
 func (c *CLIConfig) ChaincodePath() string {
-	return opts.chaincodePath
+	return c.opts.chaincodePath
 }
 
 // InitChaincodePath initializes the chaincode install source path from the provided arguments
-func InitChaincodePath(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitChaincodePath(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultChaincodePath, chaincodePathDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.chaincodePath, ChaincodePathFlag, defaultValue, description)
+	flags.StringVar(&c.opts.chaincodePath, ChaincodePathFlag, defaultValue, description)
 }
 
 // ChaincodeVersion returns the version of the chaincode
 func (c *CLIConfig) ChaincodeVersion() string {
-	return opts.chaincodeVersion
+	return c.opts.chaincodeVersion
 }
 
 // InitChaincodeVersion initializes the chaincode version from the provided arguments
-func InitChaincodeVersion(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitChaincodeVersion(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultChaincodeVersion, chaincodeVersionDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.chaincodeVersion, ChaincodeVersionFlag, defaultValue, description)
+	flags.StringVar(&c.opts.chaincodeVersion, ChaincodeVersionFlag, defaultValue, description)
 }
 
 // PeerURL returns a comma-separated list of peers in the format host1:port1,host2:port2,...
 func (c *CLIConfig) PeerURL() string {
-	return opts.peerURL
+	return c.opts.peerURL
 }
 
 // PeerURLs returns a list of peer URLs
 func (c *CLIConfig) PeerURLs() []string {
 	var urls []string
-	if len(strings.TrimSpace(opts.peerURL)) > 0 {
-		s := strings.Split(opts.peerURL, ",")
+	if len(strings.TrimSpace(c.opts.peerURL)) > 0 {
+		s := strings.Split(c.opts.peerURL, ",")
 		for _, orgID := range s {
 			urls = append(urls, orgID)
 		}
@@ -449,353 +818,560 @@ func (c *CLIConfig) PeerURLs() []string {
 }
 
 // InitPeerURL initializes the peer URL from the provided arguments
-func InitPeerURL(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitPeerURL(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultPeerURL, peerURLDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.peerURL, PeerURLFlag, defaultValue, description)
+	flags.StringVar(&c.opts.peerURL, PeerURLFlag, defaultValue, description)
 }
 
 // OrdererURL returns the URL of the orderer
 func (c *CLIConfig) OrdererURL() string {
-	return opts.ordererURL
+	return c.opts.ordererURL
 }
 
 // InitOrdererURL initializes the orderer URL from the provided arguments
-func InitOrdererURL(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitOrdererURL(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultOrdererURL, ordererURLDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.ordererURL, OrdererFlag, defaultValue, description)
+	flags.StringVar(&c.opts.ordererURL, OrdererFlag, defaultValue, description)
 }
 
 // Iterations returns the number of times that a chaincode should be invoked
 func (c *CLIConfig) Iterations() int {
-	return opts.iterations
+	return c.opts.iterations
 }
 
 // InitIterations initializes the number of query/invoke iterations from the provided arguments
-func InitIterations(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitIterations(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultIterations, iterationsDescription, defaultValueAndDescription...)
 	i, err := strconv.Atoi(defaultValue)
 	if err != nil {
 		fmt.Printf("Invalid number for %s: %s\n", IterationsFlag, defaultValue)
 		os.Exit(-1)
 	}
-	flags.IntVar(&opts.iterations, IterationsFlag, i, description)
+	flags.IntVar(&c.opts.iterations, IterationsFlag, i, description)
 }
 
 // SleepTime returns the number of milliseconds to sleep between invocations of a chaincode
 func (c *CLIConfig) SleepTime() int64 {
-	return opts.sleepTime
+	return c.opts.sleepTime
 }
 
 // InitSleepTime initializes the sleep time from the provided arguments
-func InitSleepTime(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitSleepTime(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultSleepTime, sleepTimeDescription, defaultValueAndDescription...)
 	i, err := strconv.Atoi(defaultValue)
 	if err != nil {
 		fmt.Printf("Invalid number for %s: %s\n", SleepFlag, defaultValue)
 		os.Exit(-1)
 	}
-	flags.Int64Var(&opts.sleepTime, SleepFlag, int64(i), description)
+	flags.Int64Var(&c.opts.sleepTime, SleepFlag, int64(i), description)
 }
 
 // BlockNum returns the block number (where 0 is the first block)
 func (c *CLIConfig) BlockNum() uint64 {
-	return opts.blockNum
+	return c.opts.blockNum
 }
 
 // InitBlockNum initializes the bluck number from the provided arguments
-func InitBlockNum(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitBlockNum(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultBlockNum, blockNumDescription, defaultValueAndDescription...)
 	i, err := strconv.ParseUint(defaultValue, 10, 64)
 	if err != nil {
 		fmt.Printf("Invalid number for %s: %s\n", BlockNumFlag, defaultValue)
 		os.Exit(-1)
 	}
-	flags.Uint64Var(&opts.blockNum, BlockNumFlag, i, description)
+	flags.Uint64Var(&c.opts.blockNum, BlockNumFlag, i, description)
 }
 
 // BlockHash specifies the hash of the block
 func (c *CLIConfig) BlockHash() string {
-	return opts.blockHash
+	return c.opts.blockHash
 }
 
 // InitBlockHash initializes the block hash from the provided arguments
-func InitBlockHash(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitBlockHash(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultBlockHash, blockHashDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.blockHash, BlockHashFlag, defaultValue, description)
+	flags.StringVar(&c.opts.blockHash, BlockHashFlag, defaultValue, description)
 }
 
 // Traverse returns the number of blocks to traverse backwards in the query block command
 func (c *CLIConfig) Traverse() int {
-	return opts.traverse
+	return c.opts.traverse
 }
 
 // InitTraverse initializes the 'traverse' flag from the provided arguments
-func InitTraverse(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitTraverse(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultTraverse, traverseDescription, defaultValueAndDescription...)
 	i, err := strconv.Atoi(defaultValue)
 	if err != nil {
 		fmt.Printf("Invalid number for %s: %s\n", TimeoutFlag, defaultValue)
 		i = 1
 	}
-	flags.IntVar(&opts.traverse, TraverseFlag, i, description)
+	flags.IntVar(&c.opts.traverse, TraverseFlag, i, description)
 }
 
 // PrintFormat returns the print (output) format for a block
 func (c *CLIConfig) PrintFormat() string {
-	return opts.printFormat
+	return c.opts.printFormat
 }
 
 // InitPrintFormat initializes the print format from the provided arguments
-func InitPrintFormat(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitPrintFormat(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription("display", printFormatDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.printFormat, PrintFormatFlag, defaultValue, description)
+	flags.StringVar(&c.opts.printFormat, PrintFormatFlag, defaultValue, description)
 }
 
 // Writer returns the writer for output
 func (c *CLIConfig) Writer() string {
-	return opts.writer
+	return c.opts.writer
 }
 
 // InitWriter initializes the print writer from the provided arguments
-func InitWriter(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitWriter(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription("stdout", writerDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.writer, WriterFlag, defaultValue, description)
+	flags.StringVar(&c.opts.writer, WriterFlag, defaultValue, description)
+}
+
+// RotateSize returns the size in bytes after which the output file is rotated (0 if there's no size-based rotation)
+func (c *CLIConfig) RotateSize() int64 {
+	return c.opts.rotateSize * 1024 * 1024
+}
+
+// InitRotateSize initializes the output file rotation size from the provided arguments
+func (c *CLIConfig) InitRotateSize(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultRotateSize, rotateSizeDescription, defaultValueAndDescription...)
+	i, err := strconv.Atoi(defaultValue)
+	if err != nil {
+		fmt.Printf("Invalid number for %s: %s\n", RotateSizeFlag, defaultValue)
+		i = 0
+	}
+	flags.Int64Var(&c.opts.rotateSize, RotateSizeFlag, int64(i), description)
+}
+
+// RotateInterval returns the interval after which the output file is rotated (0 if there's no time-based rotation)
+func (c *CLIConfig) RotateInterval() time.Duration {
+	return time.Duration(c.opts.rotateInterval) * time.Second
+}
+
+// InitRotateInterval initializes the output file rotation interval from the provided arguments
+func (c *CLIConfig) InitRotateInterval(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultRotateInterval, rotateIntervalDescription, defaultValueAndDescription...)
+	i, err := strconv.Atoi(defaultValue)
+	if err != nil {
+		fmt.Printf("Invalid number for %s: %s\n", RotateIntervalFlag, defaultValue)
+		i = 0
+	}
+	flags.Int64Var(&c.opts.rotateInterval, RotateIntervalFlag, int64(i), description)
+}
+
+// Compress indicates whether rotated output files are to be compressed
+func (c *CLIConfig) Compress() bool {
+	return c.opts.compress
+}
+
+// InitCompress initializes the compress flag from the provided arguments
+func (c *CLIConfig) InitCompress(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription("false", compressDescription, defaultValueAndDescription...)
+	flags.BoolVar(&c.opts.compress, CompressFlag, defaultValue == "true", description)
+}
+
+// Fsync returns the policy for syncing the output file to disk
+func (c *CLIConfig) Fsync() string {
+	return c.opts.fsync
+}
+
+// InitFsync initializes the fsync policy from the provided arguments
+func (c *CLIConfig) InitFsync(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultFsync, fsyncDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.fsync, FsyncFlag, defaultValue, description)
 }
 
 // Base64 indicates whether binary values are to be encoded in base64. (Only applies to 'display' format.)
 func (c *CLIConfig) Base64() bool {
-	return opts.base64
+	return c.opts.base64
 }
 
 // InitBase64 initializes the base64 flag from the provided arguments
-func InitBase64(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
-	defaultValue, description := getDefaultValueAndDescription("false", writerDescription, defaultValueAndDescription...)
-	flags.BoolVar(&opts.base64, Base64Flag, defaultValue == "true", description)
+func (c *CLIConfig) InitBase64(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription("false", base64Description, defaultValueAndDescription...)
+	flags.BoolVar(&c.opts.base64, Base64Flag, defaultValue == "true", description)
+}
+
+// Depth returns the maximum depth of nested elements that are displayed (0 means no limit). (Only applies to 'display' format.)
+func (c *CLIConfig) Depth() int {
+	return c.opts.depth
+}
+
+// InitDepth initializes the display depth from the provided arguments
+func (c *CLIConfig) InitDepth(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultDepth, depthDescription, defaultValueAndDescription...)
+	i, err := strconv.Atoi(defaultValue)
+	if err != nil {
+		fmt.Printf("Invalid number for %s: %s\n", DepthFlag, defaultValue)
+		i = 0
+	}
+	flags.IntVar(&c.opts.depth, DepthFlag, i, description)
+}
+
+// NoColor indicates whether colorized output is disabled. (Only applies to 'display' format.)
+func (c *CLIConfig) NoColor() bool {
+	return c.opts.noColor
+}
+
+// InitNoColor initializes the nocolor flag from the provided arguments
+func (c *CLIConfig) InitNoColor(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription("false", noColorDescription, defaultValueAndDescription...)
+	flags.BoolVar(&c.opts.noColor, NoColorFlag, defaultValue == "true", description)
+}
+
+// SkipPreflight indicates whether the validation of the connection profile before running a command is skipped
+func (c *CLIConfig) SkipPreflight() bool {
+	return c.opts.skipPreflight
+}
+
+// InitSkipPreflight initializes the skip-preflight flag from the provided arguments
+func (c *CLIConfig) InitSkipPreflight(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription("false", skipPreflightDescription, defaultValueAndDescription...)
+	flags.BoolVar(&c.opts.skipPreflight, SkipPreflightFlag, defaultValue == "true", description)
+}
+
+// DecodePayloads indicates whether the content of binary payloads is to be detected and decoded. (Only applies to 'display' format.)
+func (c *CLIConfig) DecodePayloads() bool {
+	return c.opts.decodePayloads
+}
+
+// InitDecodePayloads initializes the decode flag from the provided arguments
+func (c *CLIConfig) InitDecodePayloads(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription("false", decodePayloadsDescription, defaultValueAndDescription...)
+	flags.BoolVar(&c.opts.decodePayloads, DecodePayloadsFlag, defaultValue == "true", description)
+}
+
+// MessageTypes returns the protobuf message types to use when decoding payloads, keyed by chaincode ID
+func (c *CLIConfig) MessageTypes() map[string]string {
+	return c.parseMappings(c.opts.messageTypes, "ccid=type")
+}
+
+// Decoders returns the path of the payload decoders file
+func (c *CLIConfig) Decoders() string {
+	return c.opts.decoders
+}
+
+// InitDecoders initializes the path of the payload decoders file from the provided arguments
+func (c *CLIConfig) InitDecoders(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultDecoders, decodersDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.decoders, DecodersFlag, defaultValue, description)
+}
+
+// InitMessageTypes initializes the chaincode message type mappings from the provided arguments
+func (c *CLIConfig) InitMessageTypes(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultMessageTypes, messageTypesDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.messageTypes, MessageTypesFlag, defaultValue, description)
 }
 
 // OrdererTLSCertificate is the path of the orderer TLS certificate
 func (c *CLIConfig) OrdererTLSCertificate() string {
-	return opts.certificate
+	return c.opts.certificate
 }
 
 // InitOrdererTLSCertificate initializes the orderer TLS certificate from the provided arguments
-func InitOrdererTLSCertificate(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitOrdererTLSCertificate(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultCertificate, certificateDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.certificate, CertificateFileFlag, defaultValue, description)
+	flags.StringVar(&c.opts.certificate, CertificateFileFlag, defaultValue, description)
+}
+
+// SigningCert returns the path of the PEM enrollment certificate of the signing identity
+func (c *CLIConfig) SigningCert() string {
+	return c.opts.signingCert
+}
+
+// InitSigningCert initializes the signing certificate from the provided arguments
+func (c *CLIConfig) InitSigningCert(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultSigningCert, signingCertDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.signingCert, SigningCertFlag, defaultValue, description)
+}
+
+// SigningKey returns the path of the PEM private key of the signing identity
+func (c *CLIConfig) SigningKey() string {
+	return c.opts.signingKey
+}
+
+// InitSigningKey initializes the signing key from the provided arguments
+func (c *CLIConfig) InitSigningKey(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultSigningKey, signingKeyDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.signingKey, SigningKeyFlag, defaultValue, description)
+}
+
+// PKCS12 returns the path of the PKCS#12 bundle of the signing identity
+func (c *CLIConfig) PKCS12() string {
+	return c.opts.pkcs12
+}
+
+// InitPKCS12 initializes the PKCS#12 bundle from the provided arguments
+func (c *CLIConfig) InitPKCS12(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultPKCS12, pkcs12Description, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.pkcs12, PKCS12Flag, defaultValue, description)
+}
+
+// MSPDir returns the path of the MSP directory of the signing identity
+func (c *CLIConfig) MSPDir() string {
+	return c.opts.mspDir
+}
+
+// InitMSPDir initializes the MSP directory from the provided arguments
+func (c *CLIConfig) InitMSPDir(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultMSPDir, mspDirDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.mspDir, MSPDirFlag, defaultValue, description)
 }
 
 // Args returns the chaincode invocation arguments as a JSON string in the format, {"Func":"function","Args":["arg1","arg2",...]}
 func (c *CLIConfig) Args() string {
-	return opts.args
+	return c.opts.args
 }
 
 // InitArgs initializes the invoke/query args from the provided arguments
-func InitArgs(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitArgs(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(getEmptyArgs(), argsDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.args, ArgsFlag, defaultValue, description)
+	flags.StringVar(&c.opts.args, ArgsFlag, defaultValue, description)
 }
 
 // TxFile is the path of the .tx file used to create a channel
 func (c *CLIConfig) TxFile() string {
-	return opts.txFile
+	return c.opts.txFile
 }
 
 // InitTxFile initializes the path of the .tx file used to create/update a channel from the provided arguments
-func InitTxFile(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitTxFile(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultTxFile, txFileDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.txFile, TxFileFlag, defaultValue, description)
+	flags.StringVar(&c.opts.txFile, TxFileFlag, defaultValue, description)
 }
 
 // TxID returns the transaction ID
 func (c *CLIConfig) TxID() string {
-	return opts.txID
+	return c.opts.txID
 }
 
 // InitTxID initializes the transaction D from the provided arguments
-func InitTxID(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitTxID(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultTxID, txIDDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.txID, TxIDFlag, defaultValue, description)
+	flags.StringVar(&c.opts.txID, TxIDFlag, defaultValue, description)
 }
 
 // ChaincodePolicy returns the chaincode policy string, e.g Nof(1,(SignedBy(Org1Msp),SignedBy(Org2MSP)))
 func (c *CLIConfig) ChaincodePolicy() string {
-	return opts.chaincodePolicy
+	return c.opts.chaincodePolicy
 }
 
 // InitChaincodePolicy initializes the chaincode policy from the provided arguments
-func InitChaincodePolicy(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitChaincodePolicy(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultChaincodePolicy, chaincodePolicyDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.chaincodePolicy, ChaincodePolicyFlag, defaultValue, description)
+	flags.StringVar(&c.opts.chaincodePolicy, ChaincodePolicyFlag, defaultValue, description)
 }
 
 // CollectionConfigFile returns the path of the JSON file that contains the private data collection configuration for the chaincode to be instantiated/upgraded
 func (c *CLIConfig) CollectionConfigFile() string {
-	return opts.collectionConfigFile
+	return c.opts.collectionConfigFile
 }
 
 // InitCollectionConfigFile initializes the collection config file from the provided arguments
-func InitCollectionConfigFile(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitCollectionConfigFile(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultCollectionConfigFile, collectionConfigFileDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.collectionConfigFile, CollectionConfigFileFlag, defaultValue, description)
+	flags.StringVar(&c.opts.collectionConfigFile, CollectionConfigFileFlag, defaultValue, description)
 }
 
-// Timeout returns the timeout (in milliseconds) for various operations
+// Timeout returns the timeout for the given type of operation as configured by the CLI flags. The connection
+// profile's timeouts are applied by the config provider when neither the operation's flag nor --timeout is set.
 func (c *CLIConfig) Timeout(timeoutType fab.TimeoutType) time.Duration {
-	// TODO use provided timoutType
-	return time.Duration(opts.timeout) * time.Millisecond
+	flag, ok := timeoutFlagByType[timeoutType]
+	if !ok {
+		return time.Duration(c.opts.timeout) * time.Millisecond
+	}
+	return c.timeoutFor(flag)
 }
 
 // InitTimeout initializes the timeout from the provided arguments
-func InitTimeout(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
-	defaultValue, description := getDefaultValueAndDescription(defaultTimeout, timeoutDescription, defaultValueAndDescription...)
+func (c *CLIConfig) InitTimeout(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	c.initTimeout(flags, &c.opts.timeout, TimeoutFlag, defaultTimeout, timeoutDescription, defaultValueAndDescription...)
+}
+
+// InitConnectionTimeout initializes the connection timeout from the provided arguments
+func (c *CLIConfig) InitConnectionTimeout(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	c.initTimeout(flags, &c.opts.connectionTimeout, ConnectionTimeoutFlag, defaultConnectionTimeout, connectionTimeoutDescription, defaultValueAndDescription...)
+}
+
+// InitEndorsementTimeout initializes the endorsement timeout from the provided arguments
+func (c *CLIConfig) InitEndorsementTimeout(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	c.initTimeout(flags, &c.opts.endorsementTimeout, EndorsementTimeoutFlag, defaultEndorsementTimeout, endorsementTimeoutDescription, defaultValueAndDescription...)
+}
+
+// InitCommitTimeout initializes the commit timeout from the provided arguments
+func (c *CLIConfig) InitCommitTimeout(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	c.initTimeout(flags, &c.opts.commitTimeout, CommitTimeoutFlag, defaultCommitTimeout, commitTimeoutDescription, defaultValueAndDescription...)
+}
+
+// InitOrdererTimeout initializes the orderer timeout from the provided arguments
+func (c *CLIConfig) InitOrdererTimeout(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	c.initTimeout(flags, &c.opts.ordererTimeout, OrdererTimeoutFlag, defaultOrdererTimeout, ordererTimeoutDescription, defaultValueAndDescription...)
+}
+
+// InitDiscoveryTimeout initializes the discovery timeout from the provided arguments
+func (c *CLIConfig) InitDiscoveryTimeout(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	c.initTimeout(flags, &c.opts.discoveryTimeout, DiscoveryTimeoutFlag, defaultDiscoveryTimeout, discoveryTimeoutDescription, defaultValueAndDescription...)
+}
+
+// InitQueryTimeout initializes the query timeout from the provided arguments
+func (c *CLIConfig) InitQueryTimeout(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	c.initTimeout(flags, &c.opts.queryTimeout, QueryTimeoutFlag, defaultQueryTimeout, queryTimeoutDescription, defaultValueAndDescription...)
+}
+
+func (c *CLIConfig) initTimeout(flags *pflag.FlagSet, value *int64, name, defaultTimeout, description string, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultTimeout, description, defaultValueAndDescription...)
 	i, err := strconv.Atoi(defaultValue)
 	if err != nil {
-		fmt.Printf("Invalid number for %s: %s\n", TimeoutFlag, defaultValue)
+		fmt.Printf("Invalid number for %s: %s\n", name, defaultValue)
 		i = 1000
 	}
-	flags.Int64Var(&opts.timeout, TimeoutFlag, int64(i), description)
+	flags.Int64Var(value, name, int64(i), description)
 }
 
 // PrintPayloadOnly indicates whether only the payload or the entire
 // transaction proposal response should be printed
 func (c *CLIConfig) PrintPayloadOnly() bool {
-	return opts.printPayloadOnly
+	return c.opts.printPayloadOnly
 }
 
 // InitPrintPayloadOnly initializes the PrintPayloadOnly flag from the provided arguments
-func InitPrintPayloadOnly(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitPrintPayloadOnly(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultPrintPayloadOnly, printPayloadOnlyDescription, defaultValueAndDescription...)
-	flags.BoolVar(&opts.printPayloadOnly, PrintPayloadOnlyFlag, defaultValue == "true", description)
+	flags.BoolVar(&c.opts.printPayloadOnly, PrintPayloadOnlyFlag, defaultValue == "true", description)
 }
 
 // Validate indicates whether the endorsement responses from a query should be validated
 func (c *CLIConfig) Validate() bool {
-	return opts.validate
+	return c.opts.validate
 }
 
 // InitValidate initializes the Validate flag from the provided arguments
-func InitValidate(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitValidate(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultValidate, validateDescription, defaultValueAndDescription...)
-	flags.BoolVar(&opts.validate, ValidateFlag, defaultValue == "true", description)
+	flags.BoolVar(&c.opts.validate, ValidateFlag, defaultValue == "true", description)
 }
 
 // Concurrency returns the number of concurrent invocations/queries
 func (c *CLIConfig) Concurrency() uint16 {
-	return uint16(opts.concurrency)
+	return uint16(c.opts.concurrency)
 }
 
 // InitConcurrency initializes the 'concurrency' flag from the provided arguments
-func InitConcurrency(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitConcurrency(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultConcurrency, concurrencyDescription, defaultValueAndDescription...)
 	i, err := strconv.Atoi(defaultValue)
 	if err != nil {
 		fmt.Printf("Invalid number for %s: %s\n", TimeoutFlag, defaultValue)
 		i = 1
 	}
-	flags.IntVar(&opts.concurrency, ConcurrencyFlag, i, description)
+	flags.IntVar(&c.opts.concurrency, ConcurrencyFlag, i, description)
 }
 
 // MaxAttempts returns the maximum number of invocations attempts to be made
 // for a single chaincode invocation request. If >1 then a retry will be attempted
 // if a transient failure occurs.
 func (c *CLIConfig) MaxAttempts() int {
-	return opts.maxAttempts
+	return c.opts.maxAttempts
 }
 
 // InitMaxAttempts initializes the 'maxAttempts' flag from the provided arguments
-func InitMaxAttempts(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitMaxAttempts(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultMaxAttempts, maxAttemptsDescription, defaultValueAndDescription...)
 	i, err := strconv.Atoi(defaultValue)
 	if err != nil {
 		fmt.Printf("Invalid number for %s: %s\n", TimeoutFlag, defaultValue)
 		i = 1
 	}
-	flags.IntVar(&opts.maxAttempts, MaxAttemptsFlag, i, description)
+	flags.IntVar(&c.opts.maxAttempts, MaxAttemptsFlag, i, description)
 }
 
 // InitialBackoff returns the time (in milliseconds) to wait
 // before resubmitting an invocation after a transient error
 func (c *CLIConfig) InitialBackoff() time.Duration {
-	return time.Duration(opts.initialBackoff) * time.Millisecond
+	return time.Duration(c.opts.initialBackoff) * time.Millisecond
 }
 
 // InitInitialBackoff initializes the initial backoff from the provided arguments
-func InitInitialBackoff(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitInitialBackoff(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultInitialBackoff, initialBackoffDescription, defaultValueAndDescription...)
 	i, err := strconv.Atoi(defaultValue)
 	if err != nil {
 		fmt.Printf("Invalid number for %s: %s\n", TimeoutFlag, defaultValue)
 		i = 1000
 	}
-	flags.Int64Var(&opts.initialBackoff, InitialBackoffFlag, int64(i), description)
+	flags.Int64Var(&c.opts.initialBackoff, InitialBackoffFlag, int64(i), description)
 }
 
 // MaxBackoff returns the number
 func (c *CLIConfig) MaxBackoff() time.Duration {
-	return time.Duration(opts.maxBackoff) * time.Millisecond
+	return time.Duration(c.opts.maxBackoff) * time.Millisecond
 }
 
 // InitMaxBackoff initializes the maximum backoff from the provided arguments
-func InitMaxBackoff(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitMaxBackoff(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultMaxBackoff, maxBackoffDescription, defaultValueAndDescription...)
 	i, err := strconv.Atoi(defaultValue)
 	if err != nil {
 		fmt.Printf("Invalid number for %s: %s\n", TimeoutFlag, defaultValue)
 		i = 1000
 	}
-	flags.Int64Var(&opts.maxBackoff, MaxBackoffFlag, int64(i), description)
+	flags.Int64Var(&c.opts.maxBackoff, MaxBackoffFlag, int64(i), description)
 }
 
 // BackoffFactor returns the factor by which the backoff time is multiplied each time a retry fails. For example, if the initial
 // backoff is 1s and factor is 2 then the next retry will have a backoff of 2s and a subsequent backoff will be 4s up to the maximum backoff
 func (c *CLIConfig) BackoffFactor() float64 {
-	return opts.backoffFactor
+	return c.opts.backoffFactor
 }
 
 // InitBackoffFactor initializes the backoff factor from the provided arguments
-func InitBackoffFactor(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitBackoffFactor(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultBackoffFactor, backoffFactorDescription, defaultValueAndDescription...)
 	i, err := strconv.Atoi(defaultValue)
 	if err != nil {
 		fmt.Printf("Invalid number for %s: %s\n", TimeoutFlag, defaultValue)
 		i = 1000
 	}
-	flags.Float64Var(&opts.backoffFactor, BackoffFactorFlag, float64(i), description)
+	flags.Float64Var(&c.opts.backoffFactor, BackoffFactorFlag, float64(i), description)
 }
 
 // Verbose indicates whether or not to print the transaction proposal responses
 // when Iterations > 1
 func (c *CLIConfig) Verbose() bool {
-	return opts.verbose
+	return c.opts.verbose
 }
 
 // InitVerbosity initializes the Verbose flag from the provided arguments
-func InitVerbosity(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitVerbosity(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultVerbosity, verboseDescription, defaultValueAndDescription...)
-	flags.BoolVar(&opts.verbose, VerboseFlag, defaultValue == "true", description)
+	flags.BoolVar(&c.opts.verbose, VerboseFlag, defaultValue == "true", description)
 }
 
-// SelectionProvider returns the peer selection provider - either static or dynamic
+// SelectionProvider returns the peer selection provider - either static, dynamic, fabric, rules or auto
 func (c *CLIConfig) SelectionProvider() string {
-	return opts.selectionProvider
+	return c.opts.selectionProvider
 }
 
 // InitSelectionProvider initializes the peer selection provider from the provided arguments
-func InitSelectionProvider(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitSelectionProvider(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultSelectionProvider, selectionProviderDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.selectionProvider, SelectionProviderFlag, defaultValue, description)
+	flags.StringVar(&c.opts.selectionProvider, SelectionProviderFlag, defaultValue, description)
 }
 
 // InitGoPath initializes the gopath from the provided arguments
-func InitGoPath(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+func (c *CLIConfig) InitGoPath(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
 	defaultValue, description := getDefaultValueAndDescription(defaultGoPath, goPathDescription, defaultValueAndDescription...)
-	flags.StringVar(&opts.goPath, GoPathFlag, defaultValue, description)
+	flags.StringVar(&c.opts.goPath, GoPathFlag, defaultValue, description)
 }
 
 // GoPath returns the gopath
 func (c *CLIConfig) GoPath() string {
-	gopath := opts.goPath
-	if !IsFlagSet(GoPathFlag) {
+	gopath := c.opts.goPath
+	if !c.IsFlagSpecified(GoPathFlag) {
 		gopath = os.Getenv("GOPATH")
 	}
 	return gopath
@@ -812,6 +1388,455 @@ func getEmptyArgs() string {
 	return "{}"
 }
 
+// CryptoConfig returns the path of the crypto-config directory
+func (c *CLIConfig) CryptoConfig() string {
+	return c.opts.cryptoConfig
+}
+
+// InitCryptoConfig initializes the path of the crypto-config directory from the provided arguments
+func (c *CLIConfig) InitCryptoConfig(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultCryptoConfig, cryptoConfigDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.cryptoConfig, CryptoConfigFlag, defaultValue, description)
+}
+
+// Endpoints returns the URLs of peers, orderers and CAs keyed by host name
+func (c *CLIConfig) Endpoints() map[string]string {
+	return c.parseMappings(c.opts.endpoints, "host=url")
+}
+
+// InitEndpoints initializes the endpoints from the provided arguments
+func (c *CLIConfig) InitEndpoints(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultEndpoints, endpointsDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.endpoints, EndpointsFlag, defaultValue, description)
+}
+
+// MSPIDs returns the MSP IDs keyed by organization
+func (c *CLIConfig) MSPIDs() map[string]string {
+	return c.parseMappings(c.opts.mspIDs, "org=mspid")
+}
+
+// InitMSPIDs initializes the MSP ID mappings from the provided arguments
+func (c *CLIConfig) InitMSPIDs(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultMSPIDs, mspIDsDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.mspIDs, MSPIDsFlag, defaultValue, description)
+}
+
+// ChannelIDs returns the channel IDs
+func (c *CLIConfig) ChannelIDs() []string {
+	var channelIDs []string
+	for _, channelID := range strings.Split(c.opts.channelIDs, ",") {
+		if channelID = strings.TrimSpace(channelID); channelID != "" {
+			channelIDs = append(channelIDs, channelID)
+		}
+	}
+	return channelIDs
+}
+
+// InitChannelIDs initializes the channel IDs from the provided arguments
+func (c *CLIConfig) InitChannelIDs(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultChannelIDs, channelIDsDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.channelIDs, ChannelIDsFlag, defaultValue, description)
+}
+
+// StateStore returns the path of the SDK credential store of a generated connection profile
+func (c *CLIConfig) StateStore() string {
+	return c.opts.stateStore
+}
+
+// InitStateStore initializes the path of the credential store from the provided arguments
+func (c *CLIConfig) InitStateStore(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultStateStore, stateStoreDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.stateStore, StateStoreFlag, defaultValue, description)
+}
+
+// Output returns the path of the output file
+func (c *CLIConfig) Output() string {
+	return c.opts.output
+}
+
+// InitOutput initializes the path of the output file from the provided arguments
+func (c *CLIConfig) InitOutput(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultOutput, outputDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.output, OutputFlag, defaultValue, description)
+}
+
+// EnrollmentID returns the enrollment ID of the identity
+func (c *CLIConfig) EnrollmentID() string {
+	return c.opts.enrollmentID
+}
+
+// InitEnrollmentID initializes the enrollment ID from the provided arguments
+func (c *CLIConfig) InitEnrollmentID(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultEnrollmentID, enrollmentIDDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.enrollmentID, EnrollmentIDFlag, defaultValue, description)
+}
+
+// Secret returns the enrollment secret of the identity
+func (c *CLIConfig) Secret() string {
+	return c.opts.secret
+}
+
+// InitSecret initializes the enrollment secret from the provided arguments
+func (c *CLIConfig) InitSecret(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultSecret, secretDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.secret, SecretFlag, defaultValue, description)
+}
+
+// IdentityType returns the type of the identity
+func (c *CLIConfig) IdentityType() string {
+	return c.opts.identityType
+}
+
+// InitIdentityType initializes the identity type from the provided arguments
+func (c *CLIConfig) InitIdentityType(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultIdentityType, identityTypeDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.identityType, IdentityTypeFlag, defaultValue, description)
+}
+
+// Affiliation returns the affiliation of the identity
+func (c *CLIConfig) Affiliation() string {
+	return c.opts.affiliation
+}
+
+// InitAffiliation initializes the affiliation from the provided arguments
+func (c *CLIConfig) InitAffiliation(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultAffiliation, affiliationDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.affiliation, AffiliationFlag, defaultValue, description)
+}
+
+// Attributes returns the attributes of the identity as a comma-separated list of name=value[:ecert]
+func (c *CLIConfig) Attributes() string {
+	return c.opts.attributes
+}
+
+// InitAttributes initializes the identity attributes from the provided arguments
+func (c *CLIConfig) InitAttributes(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultAttributes, attributesDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.attributes, AttributesFlag, defaultValue, description)
+}
+
+// MaxEnrollments returns the number of times the secret may be used to enroll
+func (c *CLIConfig) MaxEnrollments() int {
+	return c.opts.maxEnrollments
+}
+
+// InitMaxEnrollments initializes the 'maxenrollments' flag from the provided arguments
+func (c *CLIConfig) InitMaxEnrollments(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultMaxEnrollments, maxEnrollmentsDescription, defaultValueAndDescription...)
+	i, err := strconv.Atoi(defaultValue)
+	if err != nil {
+		fmt.Printf("Invalid number for %s: %s\n", MaxEnrollmentsFlag, defaultValue)
+		i = 0
+	}
+	flags.IntVar(&c.opts.maxEnrollments, MaxEnrollmentsFlag, i, description)
+}
+
+// RevocationReason returns the reason for revoking a certificate
+func (c *CLIConfig) RevocationReason() string {
+	return c.opts.revocationReason
+}
+
+// InitRevocationReason initializes the revocation reason from the provided arguments
+func (c *CLIConfig) InitRevocationReason(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultRevocationReason, revocationReasonDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.revocationReason, RevocationReasonFlag, defaultValue, description)
+}
+
+// Serial returns the serial number of the certificate to revoke
+func (c *CLIConfig) Serial() string {
+	return c.opts.serial
+}
+
+// InitSerial initializes the certificate serial number from the provided arguments
+func (c *CLIConfig) InitSerial(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultSerial, serialDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.serial, SerialFlag, defaultValue, description)
+}
+
+// AKI returns the authority key identifier of the certificate to revoke
+func (c *CLIConfig) AKI() string {
+	return c.opts.aki
+}
+
+// InitAKI initializes the authority key identifier from the provided arguments
+func (c *CLIConfig) InitAKI(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultAKI, akiDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.aki, AKIFlag, defaultValue, description)
+}
+
+// Count returns the number of identities to provision
+func (c *CLIConfig) Count() int {
+	return c.opts.count
+}
+
+// InitCount initializes the 'count' flag from the provided arguments
+func (c *CLIConfig) InitCount(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultCount, countDescription, defaultValueAndDescription...)
+	i, err := strconv.Atoi(defaultValue)
+	if err != nil {
+		fmt.Printf("Invalid number for %s: %s\n", CountFlag, defaultValue)
+		i = 1
+	}
+	flags.IntVar(&c.opts.count, CountFlag, i, description)
+}
+
+// Prefix returns the prefix of the enrollment IDs of provisioned identities
+func (c *CLIConfig) Prefix() string {
+	return c.opts.prefix
+}
+
+// InitPrefix initializes the enrollment ID prefix from the provided arguments
+func (c *CLIConfig) InitPrefix(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultPrefix, prefixDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.prefix, PrefixFlag, defaultValue, description)
+}
+
+// IdentityManifest returns the path of the identity manifest
+func (c *CLIConfig) IdentityManifest() string {
+	return c.opts.identityManifest
+}
+
+// InitIdentityManifest initializes the path of the identity manifest from the provided arguments
+func (c *CLIConfig) InitIdentityManifest(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultIdentityManifest, identityManifestDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.identityManifest, IdentityManifestFlag, defaultValue, description)
+}
+
+// HealthInterval returns the interval at which target peers are health checked (0 if they aren't checked)
+func (c *CLIConfig) HealthInterval() time.Duration {
+	return time.Duration(c.opts.healthInterval) * time.Millisecond
+}
+
+// InitHealthInterval initializes the health check interval from the provided arguments
+func (c *CLIConfig) InitHealthInterval(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultHealthInterval, healthIntervalDescription, defaultValueAndDescription...)
+	i, err := strconv.Atoi(defaultValue)
+	if err != nil {
+		fmt.Printf("Invalid number for %s: %s\n", HealthIntervalFlag, defaultValue)
+		os.Exit(-1)
+	}
+	flags.Int64Var(&c.opts.healthInterval, HealthIntervalFlag, int64(i), description)
+}
+
+// TargetStrategy returns the strategy used to choose the targets of each request
+func (c *CLIConfig) TargetStrategy() string {
+	return c.opts.targetStrategy
+}
+
+// InitTargetStrategy initializes the target strategy from the provided arguments
+func (c *CLIConfig) InitTargetStrategy(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultTargetStrategy, targetStrategyDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.targetStrategy, TargetStrategyFlag, defaultValue, description)
+}
+
+// SelectionRules returns the path of the peer selection rules file
+func (c *CLIConfig) SelectionRules() string {
+	return c.opts.selectionRules
+}
+
+// InitSelectionRules initializes the path of the peer selection rules file from the provided arguments
+func (c *CLIConfig) InitSelectionRules(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultSelectionRules, selectionRulesDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.selectionRules, SelectionRulesFlag, defaultValue, description)
+}
+
+// InvocationChain returns the chaincode-to-chaincode invocation chain
+func (c *CLIConfig) InvocationChain() string {
+	return c.opts.invocationChain
+}
+
+// InitInvocationChain initializes the chaincode-to-chaincode invocation chain from the provided arguments
+func (c *CLIConfig) InitInvocationChain(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultInvocationChain, invocationChainDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.invocationChain, InvocationChainFlag, defaultValue, description)
+}
+
+// WatchInterval returns the interval at which a command is repeated (0 if the command isn't repeated)
+func (c *CLIConfig) WatchInterval() time.Duration {
+	return time.Duration(c.opts.watchInterval) * time.Millisecond
+}
+
+// InitWatchInterval initializes the watch interval from the provided arguments
+func (c *CLIConfig) InitWatchInterval(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultWatchInterval, watchIntervalDescription, defaultValueAndDescription...)
+	i, err := strconv.Atoi(defaultValue)
+	if err != nil {
+		fmt.Printf("Invalid number for %s: %s\n", WatchIntervalFlag, defaultValue)
+		os.Exit(-1)
+	}
+	flags.Int64Var(&c.opts.watchInterval, WatchIntervalFlag, int64(i), description)
+}
+
+// FromBlock returns the number of the first block in a range of blocks
+func (c *CLIConfig) FromBlock() uint64 {
+	return c.opts.fromBlock
+}
+
+// InitFromBlock initializes the number of the first block in a range of blocks from the provided arguments
+func (c *CLIConfig) InitFromBlock(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultFromBlock, fromBlockDescription, defaultValueAndDescription...)
+	i, err := strconv.ParseUint(defaultValue, 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid number for %s: %s\n", FromBlockFlag, defaultValue)
+		os.Exit(-1)
+	}
+	flags.Uint64Var(&c.opts.fromBlock, FromBlockFlag, i, description)
+}
+
+// ToBlock returns the number of the last block in a range of blocks
+func (c *CLIConfig) ToBlock() uint64 {
+	return c.opts.toBlock
+}
+
+// InitToBlock initializes the number of the last block in a range of blocks from the provided arguments
+func (c *CLIConfig) InitToBlock(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultToBlock, toBlockDescription, defaultValueAndDescription...)
+	i, err := strconv.ParseUint(defaultValue, 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid number for %s: %s\n", ToBlockFlag, defaultValue)
+		os.Exit(-1)
+	}
+	flags.Uint64Var(&c.opts.toBlock, ToBlockFlag, i, description)
+}
+
+// BlockFormat returns the format in which blocks are written (proto, json or both)
+func (c *CLIConfig) BlockFormat() string {
+	return c.opts.blockFormat
+}
+
+// InitBlockFormat initializes the block format from the provided arguments
+func (c *CLIConfig) InitBlockFormat(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultBlockFormat, blockFormatDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.blockFormat, BlockFormatFlag, defaultValue, description)
+}
+
+// Input returns the path of the blocks on disk that are read instead of querying the channel
+func (c *CLIConfig) Input() string {
+	return c.opts.input
+}
+
+// InitInput initializes the path of the blocks on disk from the provided arguments
+func (c *CLIConfig) InitInput(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultInput, inputDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.input, InputFlag, defaultValue, description)
+}
+
+// Function returns the chaincode function by which transactions are filtered
+func (c *CLIConfig) Function() string {
+	return c.opts.function
+}
+
+// InitFunction initializes the chaincode function filter from the provided arguments
+func (c *CLIConfig) InitFunction(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultFunction, functionDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.function, FunctionFlag, defaultValue, description)
+}
+
+// CreatorMSP returns the MSP ID by which the creators of transactions are filtered
+func (c *CLIConfig) CreatorMSP() string {
+	return c.opts.creatorMSP
+}
+
+// InitCreatorMSP initializes the creator MSP filter from the provided arguments
+func (c *CLIConfig) InitCreatorMSP(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultCreatorMSP, creatorMSPDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.creatorMSP, CreatorMSPFlag, defaultValue, description)
+}
+
+// ValidationCode returns the validation code by which transactions are filtered
+func (c *CLIConfig) ValidationCode() string {
+	return c.opts.validationCode
+}
+
+// InitValidationCode initializes the validation code filter from the provided arguments
+func (c *CLIConfig) InitValidationCode(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultValidationCode, validationCodeDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.validationCode, ValidationCodeFlag, defaultValue, description)
+}
+
+// WriteKey returns the key by which the writes of transactions are filtered
+func (c *CLIConfig) WriteKey() string {
+	return c.opts.writeKey
+}
+
+// InitWriteKey initializes the write key filter from the provided arguments
+func (c *CLIConfig) InitWriteKey(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultWriteKey, writeKeyDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.writeKey, WriteKeyFlag, defaultValue, description)
+}
+
+// WriteKeyPrefix returns the key prefix by which the writes of transactions are filtered
+func (c *CLIConfig) WriteKeyPrefix() string {
+	return c.opts.writeKeyPrefix
+}
+
+// InitWriteKeyPrefix initializes the write key prefix filter from the provided arguments
+func (c *CLIConfig) InitWriteKeyPrefix(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultWriteKeyPrefix, writeKeyPrefixDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.writeKeyPrefix, WriteKeyPrefixFlag, defaultValue, description)
+}
+
+// Since returns the start of the time window of transactions (RFC3339)
+func (c *CLIConfig) Since() string {
+	return c.opts.since
+}
+
+// InitSince initializes the start of the time window from the provided arguments
+func (c *CLIConfig) InitSince(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultSince, sinceDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.since, SinceFlag, defaultValue, description)
+}
+
+// Until returns the end of the time window of transactions (RFC3339)
+func (c *CLIConfig) Until() string {
+	return c.opts.until
+}
+
+// InitUntil initializes the end of the time window from the provided arguments
+func (c *CLIConfig) InitUntil(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultUntil, untilDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.until, UntilFlag, defaultValue, description)
+}
+
+// Full returns true if full transactions are printed instead of summaries
+func (c *CLIConfig) Full() bool {
+	return c.opts.full
+}
+
+// InitFull initializes the Full flag from the provided arguments
+func (c *CLIConfig) InitFull(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultFull, fullDescription, defaultValueAndDescription...)
+	flags.BoolVar(&c.opts.full, FullFlag, defaultValue == "true", description)
+}
+
+// StateKey returns the key in the chaincode's state
+func (c *CLIConfig) StateKey() string {
+	return c.opts.stateKey
+}
+
+// InitStateKey initializes the state key from the provided arguments
+func (c *CLIConfig) InitStateKey(flags *pflag.FlagSet, defaultValueAndDescription ...string) {
+	defaultValue, description := getDefaultValueAndDescription(defaultStateKey, stateKeyDescription, defaultValueAndDescription...)
+	flags.StringVar(&c.opts.stateKey, StateKeyFlag, defaultValue, description)
+}
+
+// parseMappings parses a comma-separated list of key=value pairs
+func (c *CLIConfig) parseMappings(value, expecting string) map[string]string {
+	mappings := make(map[string]string)
+	if len(strings.TrimSpace(value)) == 0 {
+		return mappings
+	}
+	for _, mapping := range strings.Split(value, ",") {
+		s := strings.SplitN(mapping, "=", 2)
+		if len(s) != 2 {
+			c.logger.Warnf("Ignoring invalid mapping [%s]. Expecting %s", mapping, expecting)
+			continue
+		}
+		mappings[strings.TrimSpace(s[0])] = strings.TrimSpace(s[1])
+	}
+	return mappings
+}
+
 func getDefaultValueAndDescription(defaultValue string, defaultDescription string, overrides ...string) (value, description string) {
 	if len(overrides) > 0 {
 		value = overrides[0]
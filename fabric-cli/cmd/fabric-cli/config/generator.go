@@ -0,0 +1,455 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	peerOrganizationsDir    = "peerOrganizations"
+	ordererOrganizationsDir = "ordererOrganizations"
+
+	defaultPeerPort    = "7051"
+	defaultOrdererPort = "7050"
+	defaultCAPort      = "7054"
+
+	// the pre-enrolled user whose TLS certificate is used for mutual TLS
+	tlsClientUser = "User1"
+
+	// the enrollment secret of CA registrars, which must be replaced in the generated profile
+	registrarSecretPlaceholder = "REPLACE_WITH_REGISTRAR_SECRET"
+
+	defaultStateStorePath = "/tmp/state-store"
+)
+
+// GenerateOpts contains the options for generating a connection profile
+type GenerateOpts struct {
+	// CryptoConfigPath is the path of the crypto-config directory (generated by cryptogen or Fabric CA)
+	CryptoConfigPath string
+
+	// Endpoints contains the URLs of the peers, orderers and CAs keyed by host name. Peers and
+	// orderers that aren't specified use host:7051 and host:7050. CAs are only included if an
+	// endpoint is specified.
+	Endpoints map[string]string
+
+	// MSPIDs contains the MSP IDs keyed by organization name or domain. The MSP ID of other
+	// organizations is derived from the domain, e.g. Org1MSP for org1.example.com.
+	MSPIDs map[string]string
+
+	// Channels contains the IDs of the channels that all of the peers have joined
+	Channels []string
+
+	// ClientOrg is the name or domain of the client's organization (the first peer organization by default)
+	ClientOrg string
+
+	// StateStorePath is the path of the SDK's credential store (/tmp/state-store by default).
+	// The crypto store is the msp subdirectory of the credential store.
+	StateStorePath string
+}
+
+// cryptoOrg is an organization found in the crypto-config directory
+type cryptoOrg struct {
+	name      string
+	domain    string
+	mspID     string
+	dir       string
+	orderer   bool
+	nodes     []string
+	tlsCACert string
+	caCert    string
+}
+
+// GenerateConnectionProfile generates an SDK connection profile (in YAML) from the organizations, peers
+// and orderers in a crypto-config directory
+func GenerateConnectionProfile(opts *GenerateOpts) ([]byte, error) {
+	cryptoConfigPath, err := filepath.Abs(opts.CryptoConfigPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid crypto-config path [%s]", opts.CryptoConfigPath)
+	}
+
+	peerOrgs, err := scanOrgs(cryptoConfigPath, peerOrganizationsDir, "peers")
+	if err != nil {
+		return nil, err
+	}
+	if len(peerOrgs) == 0 {
+		return nil, errors.Errorf("no peer organizations found in [%s]", filepath.Join(cryptoConfigPath, peerOrganizationsDir))
+	}
+
+	ordererOrgs, err := scanOrgs(cryptoConfigPath, ordererOrganizationsDir, "orderers")
+	if err != nil {
+		return nil, err
+	}
+
+	nameOrgs(peerOrgs, ordererOrgs, opts.MSPIDs)
+
+	clientOrg := peerOrgs[0]
+	if opts.ClientOrg != "" {
+		clientOrg = findOrg(opts.ClientOrg, peerOrgs)
+		if clientOrg == nil {
+			return nil, errors.Errorf("organization [%s] not found in [%s]", opts.ClientOrg, filepath.Join(cryptoConfigPath, peerOrganizationsDir))
+		}
+	}
+
+	g := &generator{opts: opts, cryptoConfigPath: cryptoConfigPath}
+	allOrgs := append(append([]*cryptoOrg{}, peerOrgs...), ordererOrgs...)
+
+	profile := yaml.MapSlice{
+		{Key: "version", Value: "1.0.0"},
+		{Key: "client", Value: g.client(clientOrg)},
+		{Key: "channels", Value: g.channels(peerOrgs, ordererOrgs)},
+		{Key: "organizations", Value: g.organizations(allOrgs)},
+		{Key: "orderers", Value: g.endpoints(ordererOrgs, defaultOrdererPort)},
+		{Key: "peers", Value: g.endpoints(peerOrgs, defaultPeerPort)},
+	}
+	if cas := g.certificateAuthorities(peerOrgs); len(cas) > 0 {
+		profile = append(profile, yaml.MapItem{Key: "certificateAuthorities", Value: cas})
+	}
+	if matchers := g.entityMatchers(peerOrgs, ordererOrgs); len(matchers) > 0 {
+		profile = append(profile, yaml.MapItem{Key: "entityMatchers", Value: matchers})
+	}
+
+	return yaml.Marshal(profile)
+}
+
+type generator struct {
+	opts             *GenerateOpts
+	cryptoConfigPath string
+}
+
+func (g *generator) client(org *cryptoOrg) yaml.MapSlice {
+	stateStorePath := g.opts.StateStorePath
+	if stateStorePath == "" {
+		stateStorePath = defaultStateStorePath
+	}
+
+	tlsCerts := yaml.MapSlice{{Key: "systemCertPool", Value: false}}
+
+	// Use the TLS certificate of a pre-enrolled user for mutual TLS (if it exists)
+	tlsDir := filepath.Join(g.cryptoConfigPath, org.dir, "users", fmt.Sprintf("%s@%s", tlsClientUser, org.domain), "tls")
+	if fileExists(filepath.Join(tlsDir, "client.key")) && fileExists(filepath.Join(tlsDir, "client.crt")) {
+		tlsCerts = append(tlsCerts, yaml.MapItem{Key: "client", Value: yaml.MapSlice{
+			{Key: "key", Value: pathRef(filepath.Join(tlsDir, "client.key"))},
+			{Key: "cert", Value: pathRef(filepath.Join(tlsDir, "client.crt"))},
+		}})
+	}
+
+	return yaml.MapSlice{
+		{Key: "organization", Value: org.name},
+		{Key: "logging", Value: yaml.MapSlice{{Key: "level", Value: "info"}}},
+		{Key: "cryptoconfig", Value: pathRef(g.cryptoConfigPath)},
+		{Key: "credentialStore", Value: yaml.MapSlice{
+			{Key: "path", Value: stateStorePath},
+			{Key: "cryptoStore", Value: pathRef(filepath.Join(stateStorePath, "msp"))},
+		}},
+		{Key: "BCCSP", Value: yaml.MapSlice{
+			{Key: "security", Value: yaml.MapSlice{
+				{Key: "enabled", Value: true},
+				{Key: "default", Value: yaml.MapSlice{{Key: "provider", Value: "SW"}}},
+				{Key: "hashAlgorithm", Value: "SHA2"},
+				{Key: "softVerify", Value: true},
+				{Key: "level", Value: 256},
+			}},
+		}},
+		{Key: "tlsCerts", Value: tlsCerts},
+	}
+}
+
+func (g *generator) channels(peerOrgs, ordererOrgs []*cryptoOrg) yaml.MapSlice {
+	var orderers []string
+	for _, org := range ordererOrgs {
+		orderers = append(orderers, org.nodes...)
+	}
+
+	channels := yaml.MapSlice{}
+	for _, channelID := range g.opts.Channels {
+		peers := yaml.MapSlice{}
+		for _, org := range peerOrgs {
+			for _, peer := range org.nodes {
+				peers = append(peers, yaml.MapItem{Key: peer, Value: yaml.MapSlice{
+					{Key: "endorsingPeer", Value: true},
+					{Key: "chaincodeQuery", Value: true},
+					{Key: "ledgerQuery", Value: true},
+					{Key: "eventSource", Value: true},
+				}})
+			}
+		}
+
+		channel := yaml.MapSlice{{Key: "peers", Value: peers}}
+		if len(orderers) > 0 {
+			channel = append(channel, yaml.MapItem{Key: "orderers", Value: orderers})
+		}
+		channels = append(channels, yaml.MapItem{Key: channelID, Value: channel})
+	}
+	return channels
+}
+
+func (g *generator) organizations(orgs []*cryptoOrg) yaml.MapSlice {
+	organizations := yaml.MapSlice{}
+	for _, org := range orgs {
+		o := yaml.MapSlice{
+			{Key: "mspid", Value: org.mspID},
+			{Key: "cryptoPath", Value: filepath.ToSlash(filepath.Join(org.dir, "users", "{username}@"+org.domain, "msp"))},
+		}
+		if !org.orderer {
+			o = append(o, yaml.MapItem{Key: "peers", Value: org.nodes})
+			if _, ok := g.opts.Endpoints[caHost(org)]; ok && org.caCert != "" {
+				o = append(o, yaml.MapItem{Key: "certificateAuthorities", Value: []string{caHost(org)}})
+			}
+		}
+		organizations = append(organizations, yaml.MapItem{Key: org.name, Value: o})
+	}
+	return organizations
+}
+
+func (g *generator) endpoints(orgs []*cryptoOrg, defaultPort string) yaml.MapSlice {
+	endpoints := yaml.MapSlice{}
+	for _, org := range orgs {
+		for _, node := range org.nodes {
+			endpoint := yaml.MapSlice{
+				{Key: "url", Value: g.url(node, defaultPort)},
+				{Key: "grpcOptions", Value: yaml.MapSlice{
+					{Key: "ssl-target-name-override", Value: node},
+					{Key: "keep-alive-time", Value: "0s"},
+					{Key: "keep-alive-timeout", Value: "20s"},
+					{Key: "keep-alive-permit", Value: false},
+					{Key: "fail-fast", Value: false},
+					{Key: "allow-insecure", Value: false},
+				}},
+			}
+			if org.tlsCACert != "" {
+				endpoint = append(endpoint, yaml.MapItem{Key: "tlsCACerts", Value: pathRef(org.tlsCACert)})
+			}
+			endpoints = append(endpoints, yaml.MapItem{Key: node, Value: endpoint})
+		}
+	}
+	return endpoints
+}
+
+func (g *generator) certificateAuthorities(orgs []*cryptoOrg) yaml.MapSlice {
+	cas := yaml.MapSlice{}
+	for _, org := range orgs {
+		host := caHost(org)
+		url, ok := g.opts.Endpoints[host]
+		if !ok || org.caCert == "" {
+			continue
+		}
+		if !strings.Contains(url, "://") {
+			url = "https://" + url
+		}
+		cas = append(cas, yaml.MapItem{Key: host, Value: yaml.MapSlice{
+			{Key: "url", Value: url},
+			{Key: "tlsCACerts", Value: pathRef(org.caCert)},
+			{Key: "registrar", Value: yaml.MapSlice{
+				{Key: "enrollId", Value: "admin"},
+				{Key: "enrollSecret", Value: registrarSecretPlaceholder},
+			}},
+			{Key: "caName", Value: host},
+		}})
+	}
+	return cas
+}
+
+// entityMatchers maps the host names of the peers and orderers (e.g. as returned by the discovery
+// service) to the configured endpoints if the endpoint's host is different
+func (g *generator) entityMatchers(peerOrgs, ordererOrgs []*cryptoOrg) yaml.MapSlice {
+	matchers := yaml.MapSlice{}
+	for _, kind := range []struct {
+		name        string
+		orgs        []*cryptoOrg
+		defaultPort string
+	}{
+		{"peer", peerOrgs, defaultPeerPort},
+		{"orderer", ordererOrgs, defaultOrdererPort},
+	} {
+		var entries []yaml.MapSlice
+		for _, org := range kind.orgs {
+			for _, node := range org.nodes {
+				url := g.url(node, kind.defaultPort)
+				if urlHost(url) == node {
+					continue
+				}
+				entries = append(entries, yaml.MapSlice{
+					{Key: "pattern", Value: regexp.QuoteMeta(node)},
+					{Key: "urlSubstitutionExp", Value: url},
+					{Key: "sslTargetOverrideUrlSubstitutionExp", Value: node},
+					{Key: "mappedHost", Value: node},
+				})
+			}
+		}
+		if len(entries) > 0 {
+			matchers = append(matchers, yaml.MapItem{Key: kind.name, Value: entries})
+		}
+	}
+	return matchers
+}
+
+func (g *generator) url(host, defaultPort string) string {
+	if url, ok := g.opts.Endpoints[host]; ok {
+		return url
+	}
+	return net.JoinHostPort(host, defaultPort)
+}
+
+// scanOrgs returns the organizations in the given directory of the crypto-config (peerOrganizations
+// or ordererOrganizations) along with their nodes (peers or orderers)
+func scanOrgs(cryptoConfigPath, orgsDir, nodesDir string) ([]*cryptoOrg, error) {
+	domains, err := subDirs(filepath.Join(cryptoConfigPath, orgsDir))
+	if err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var orgs []*cryptoOrg
+	for _, domain := range domains {
+		dir := filepath.Join(orgsDir, domain)
+		absDir := filepath.Join(cryptoConfigPath, dir)
+
+		nodes, err := subDirs(filepath.Join(absDir, nodesDir))
+		if err != nil && !os.IsNotExist(errors.Cause(err)) {
+			return nil, err
+		}
+
+		orgs = append(orgs, &cryptoOrg{
+			domain:    domain,
+			dir:       dir,
+			orderer:   orgsDir == ordererOrganizationsDir,
+			nodes:     nodes,
+			tlsCACert: findCert(filepath.Join(absDir, "tlsca"), filepath.Join(absDir, "msp", "tlscacerts")),
+			caCert:    findCert(filepath.Join(absDir, "ca"), filepath.Join(absDir, "msp", "cacerts")),
+		})
+	}
+	return orgs, nil
+}
+
+// nameOrgs assigns the names and MSP IDs of the organizations. Peer organizations are named after the
+// first label of their domain (e.g. org1 for org1.example.com). An orderer organization is named
+// 'ordererorg' if there is only one.
+func nameOrgs(peerOrgs, ordererOrgs []*cryptoOrg, mspIDs map[string]string) {
+	used := make(map[string]bool)
+	assign := func(org *cryptoOrg, name, mspID string) {
+		if used[name] {
+			name = org.domain
+		}
+		used[name] = true
+		org.name = name
+
+		if id, ok := mspIDs[org.domain]; ok {
+			org.mspID = id
+		} else if id, ok := mspIDs[org.name]; ok {
+			org.mspID = id
+		} else {
+			org.mspID = mspID
+		}
+	}
+
+	for _, org := range peerOrgs {
+		label := strings.ToLower(firstLabel(org.domain))
+		assign(org, label, strings.Title(label)+"MSP")
+	}
+	for _, org := range ordererOrgs {
+		if len(ordererOrgs) == 1 {
+			assign(org, "ordererorg", "OrdererMSP")
+		} else {
+			label := strings.ToLower(firstLabel(org.domain))
+			assign(org, label+"-orderer", strings.Title(label)+"OrdererMSP")
+		}
+	}
+}
+
+func findOrg(nameOrDomain string, orgs []*cryptoOrg) *cryptoOrg {
+	for _, org := range orgs {
+		if strings.EqualFold(org.name, nameOrDomain) || strings.EqualFold(org.domain, nameOrDomain) {
+			return org
+		}
+	}
+	return nil
+}
+
+// findCert returns the path of the first PEM file in the given directories (files ending with
+// -cert.pem are preferred) or an empty string if none is found
+func findCert(dirs ...string) string {
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		var pems []string
+		for _, f := range files {
+			if f.IsDir() || !strings.HasSuffix(f.Name(), ".pem") {
+				continue
+			}
+			if strings.HasSuffix(f.Name(), "-cert.pem") {
+				return filepath.Join(dir, f.Name())
+			}
+			pems = append(pems, f.Name())
+		}
+		if len(pems) > 0 {
+			return filepath.Join(dir, pems[0])
+		}
+	}
+	return ""
+}
+
+// subDirs returns the sorted names of the sub-directories of the given directory
+func subDirs(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading directory [%s]", dir)
+	}
+
+	var dirs []string
+	for _, f := range files {
+		if f.IsDir() {
+			dirs = append(dirs, f.Name())
+		}
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+func caHost(org *cryptoOrg) string {
+	return "ca." + org.domain
+}
+
+func pathRef(path string) yaml.MapSlice {
+	return yaml.MapSlice{{Key: "path", Value: filepath.ToSlash(path)}}
+}
+
+func firstLabel(domain string) string {
+	if i := strings.Index(domain, "."); i > 0 {
+		return domain[:i]
+	}
+	return domain
+}
+
+func urlHost(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	}
+	if host, _, err := net.SplitHostPort(url); err == nil {
+		return host
+	}
+	return url
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestGenerateConnectionProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crypto-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, file := range []string{
+		"peerOrganizations/org1.example.com/tlsca/tlsca.org1.example.com-cert.pem",
+		"peerOrganizations/org1.example.com/ca/ca.org1.example.com-cert.pem",
+		"peerOrganizations/org1.example.com/peers/peer0.org1.example.com/tls/ca.crt",
+		"peerOrganizations/org1.example.com/peers/peer1.org1.example.com/tls/ca.crt",
+		"peerOrganizations/org1.example.com/users/User1@org1.example.com/tls/client.key",
+		"peerOrganizations/org1.example.com/users/User1@org1.example.com/tls/client.crt",
+		"peerOrganizations/org2.example.com/msp/tlscacerts/tlsca.pem",
+		"peerOrganizations/org2.example.com/peers/peer0.org2.example.com/tls/ca.crt",
+		"ordererOrganizations/example.com/tlsca/tlsca.example.com-cert.pem",
+		"ordererOrganizations/example.com/orderers/orderer.example.com/tls/ca.crt",
+	} {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte("cert"), 0644))
+	}
+
+	raw, err := GenerateConnectionProfile(&GenerateOpts{
+		CryptoConfigPath: dir,
+		Endpoints: map[string]string{
+			"peer0.org1.example.com": "localhost:7051",
+			"ca.org1.example.com":    "localhost:7054",
+		},
+		MSPIDs:         map[string]string{"org2.example.com": "SecondMSP"},
+		Channels:       []string{"mychannel"},
+		StateStorePath: "/var/fabric-cli/store",
+	})
+	require.NoError(t, err)
+
+	problems := validateConnectionProfile(raw)
+	assert.Empty(t, problems)
+
+	var profile struct {
+		Client struct {
+			Organization    string
+			CredentialStore struct {
+				Path        string
+				CryptoStore struct {
+					Path string
+				} `yaml:"cryptoStore"`
+			} `yaml:"credentialStore"`
+		}
+		CertificateAuthorities map[string]struct {
+			Registrar struct {
+				EnrollSecret string `yaml:"enrollSecret"`
+			}
+		} `yaml:"certificateAuthorities"`
+		Organizations map[string]struct {
+			MSPID      string `yaml:"mspid"`
+			CryptoPath string `yaml:"cryptoPath"`
+			Peers      []string
+		}
+		Peers map[string]struct {
+			URL string
+		}
+		EntityMatchers map[string][]map[string]string `yaml:"entityMatchers"`
+	}
+	require.NoError(t, yaml.Unmarshal(raw, &profile))
+
+	assert.Equal(t, "org1", profile.Client.Organization)
+	assert.Equal(t, "/var/fabric-cli/store", profile.Client.CredentialStore.Path)
+	assert.Equal(t, "/var/fabric-cli/store/msp", profile.Client.CredentialStore.CryptoStore.Path)
+	assert.Equal(t, registrarSecretPlaceholder, profile.CertificateAuthorities["ca.org1.example.com"].Registrar.EnrollSecret)
+	assert.Equal(t, "Org1MSP", profile.Organizations["org1"].MSPID)
+	assert.Equal(t, "SecondMSP", profile.Organizations["org2"].MSPID)
+	assert.Equal(t, "OrdererMSP", profile.Organizations["ordererorg"].MSPID)
+	assert.Equal(t, "peerOrganizations/org1.example.com/users/{username}@org1.example.com/msp", profile.Organizations["org1"].CryptoPath)
+	assert.Equal(t, []string{"peer0.org1.example.com", "peer1.org1.example.com"}, profile.Organizations["org1"].Peers)
+	assert.Equal(t, "localhost:7051", profile.Peers["peer0.org1.example.com"].URL)
+	assert.Equal(t, "peer1.org1.example.com:7051", profile.Peers["peer1.org1.example.com"].URL)
+	if assert.Len(t, profile.EntityMatchers["peer"], 1) {
+		assert.Equal(t, "peer0.org1.example.com", profile.EntityMatchers["peer"][0]["mappedHost"])
+	}
+
+	// The SDK must be able to load the generated profile
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, raw, 0644))
+	backends, err := config.FromFile(path)()
+	require.NoError(t, err)
+	value, ok := backends[0].Lookup("client.organization")
+	assert.True(t, ok)
+	assert.Equal(t, "org1", value)
+}
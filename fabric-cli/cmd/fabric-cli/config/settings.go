@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// HomeEnvVar is the environment variable that overrides the directory of the settings file (default ~/.fabric-cli)
+	HomeEnvVar = "FABRIC_CLI_HOME"
+
+	// ProfileEnvVar is the environment variable that selects the profile (overrides the current profile in the settings file)
+	ProfileEnvVar = "FABRIC_CLI_PROFILE"
+
+	// EnvPrefix is the prefix of the environment variables that override flags, e.g. FABRIC_CLI_CID overrides --cid
+	EnvPrefix = "FABRIC_CLI_"
+
+	// DefaultProfile is the profile used by 'config set' if no profile is selected
+	DefaultProfile = "default"
+
+	settingsDir  = ".fabric-cli"
+	settingsFile = "config.yaml"
+)
+
+// Settings contains the persistent CLI settings, i.e. named profiles of flag values
+type Settings struct {
+	// CurrentProfile is the profile that's used if neither --profile nor FABRIC_CLI_PROFILE is set
+	CurrentProfile string `yaml:"currentProfile,omitempty"`
+
+	// Profiles contains the flag values of each profile, keyed by profile name and then by flag name
+	Profiles map[string]map[string]string `yaml:"profiles,omitempty"`
+
+	path string
+}
+
+// SettingsPath returns the path of the settings file, i.e. $FABRIC_CLI_HOME/config.yaml or ~/.fabric-cli/config.yaml
+func SettingsPath() (string, error) {
+	if home := os.Getenv(HomeEnvVar); home != "" {
+		return filepath.Join(home, settingsFile), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "unable to determine home directory")
+	}
+	return filepath.Join(home, settingsDir, settingsFile), nil
+}
+
+// LoadSettings loads the settings file. Empty settings are returned if the file doesn't exist.
+func LoadSettings() (*Settings, error) {
+	path, err := SettingsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &Settings{path: path}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return nil, errors.Wrapf(err, "error reading settings file [%s]", path)
+	}
+
+	if err := yaml.Unmarshal(raw, settings); err != nil {
+		return nil, errors.Wrapf(err, "error parsing settings file [%s]", path)
+	}
+
+	return settings, nil
+}
+
+// Save saves the settings file
+func (s *Settings) Save() error {
+	raw, err := yaml.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "error marshalling settings")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return errors.Wrapf(err, "error creating directory for settings file [%s]", s.path)
+	}
+
+	if err := ioutil.WriteFile(s.path, raw, 0600); err != nil {
+		return errors.Wrapf(err, "error writing settings file [%s]", s.path)
+	}
+
+	return nil
+}
+
+// Path returns the path of the settings file
+func (s *Settings) Path() string {
+	return s.path
+}
+
+// ProfileNames returns the sorted names of all profiles
+func (s *Settings) ProfileNames() []string {
+	var names []string
+	for name := range s.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the flag values of the given profile
+func (s *Settings) Profile(name string) (map[string]string, bool) {
+	profile, ok := s.Profiles[name]
+	return profile, ok
+}
+
+// Get returns the value of the given flag in the given profile
+func (s *Settings) Get(profile, key string) (string, bool) {
+	value, ok := s.Profiles[profile][key]
+	return value, ok
+}
+
+// Set sets the value of the given flag in the given profile. The profile is created if it doesn't exist.
+func (s *Settings) Set(profile, key, value string) {
+	if s.Profiles == nil {
+		s.Profiles = make(map[string]map[string]string)
+	}
+	if s.Profiles[profile] == nil {
+		s.Profiles[profile] = make(map[string]string)
+	}
+	s.Profiles[profile][key] = value
+}
+
+// Unset removes the given flag from the given profile
+func (s *Settings) Unset(profile, key string) {
+	delete(s.Profiles[profile], key)
+}
+
+// ActiveProfile returns the name of the profile selected by (in order of precedence) the
+// --profile flag, the FABRIC_CLI_PROFILE environment variable or the current profile in the
+// settings file. An empty string is returned if no profile is selected.
+func (s *Settings) ActiveProfile(flags *pflag.FlagSet) string {
+	if flag := flags.Lookup(ProfileFlag); flag != nil && flag.Changed {
+		return flag.Value.String()
+	}
+	if profile := os.Getenv(ProfileEnvVar); profile != "" {
+		return profile
+	}
+	return s.CurrentProfile
+}
+
+// EnvVarName returns the name of the environment variable that overrides the given flag,
+// e.g. FABRIC_CLI_CID for --cid and FABRIC_CLI_LOGGING_LEVEL for --logging-level
+func EnvVarName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.Replace(flagName, "-", "_", -1))
+}
+
+// applySettings sets the flags that weren't provided on the command line from environment
+// variables and then from the active profile, i.e. the order of precedence is
+// flag > environment variable > profile > default. The flags are not marked as changed so that
+// only the flags provided on the command line are reported as set. The names and values of the
+// flags that were applied are returned.
+func applySettings(flags *pflag.FlagSet) (map[string]string, error) {
+	settings, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	var profile map[string]string
+	if name := settings.ActiveProfile(flags); name != "" {
+		var ok bool
+		profile, ok = settings.Profile(name)
+		if !ok {
+			return nil, errors.Errorf("profile [%s] not found in settings file [%s]", name, settings.Path())
+		}
+	}
+
+	applied := make(map[string]string)
+	var setErr error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if setErr != nil || flag.Changed || flag.Name == ProfileFlag {
+			return
+		}
+
+		if value, ok := os.LookupEnv(EnvVarName(flag.Name)); ok {
+			if err := flag.Value.Set(value); err != nil {
+				setErr = errors.Wrapf(err, "invalid value for %s from environment variable %s", flag.Name, EnvVarName(flag.Name))
+			}
+			applied[flag.Name] = value
+			return
+		}
+
+		if value, ok := profile[flag.Name]; ok {
+			if err := flag.Value.Set(value); err != nil {
+				setErr = errors.Wrapf(err, "invalid value for %s from profile", flag.Name)
+			}
+			applied[flag.Name] = value
+		}
+	})
+	if setErr != nil {
+		return nil, setErr
+	}
+
+	return applied, nil
+}
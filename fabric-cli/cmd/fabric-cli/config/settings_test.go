@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvVarName(t *testing.T) {
+	assert.Equal(t, "FABRIC_CLI_CID", EnvVarName(ChannelIDFlag))
+	assert.Equal(t, "FABRIC_CLI_LOGGING_LEVEL", EnvVarName(LoggingLevelFlag))
+}
+
+func TestApplySettings(t *testing.T) {
+	home, err := ioutil.TempDir("", "fabric-cli")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	os.Setenv(HomeEnvVar, home)
+	defer os.Unsetenv(HomeEnvVar)
+
+	settings, err := LoadSettings()
+	require.NoError(t, err)
+	settings.Set("staging", ChannelIDFlag, "stagingchannel")
+	settings.Set("staging", OrgIDsFlag, "org2")
+	settings.Set("staging", IterationsFlag, "10")
+	settings.Set("prod", ChannelIDFlag, "prodchannel")
+	settings.CurrentProfile = "staging"
+	require.NoError(t, settings.Save())
+
+	var c *CLIConfig
+	newFlags := func() *pflag.FlagSet {
+		c = New()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		c.InitProfile(flags)
+		c.InitChannelID(flags)
+		c.InitOrgIDs(flags)
+		c.InitIterations(flags)
+		c.InitUserName(flags)
+		return flags
+	}
+
+	// Profile > default
+	flags := newFlags()
+	require.NoError(t, flags.Parse(nil))
+	applied, err := applySettings(flags)
+	require.NoError(t, err)
+	assert.Equal(t, "stagingchannel", c.opts.channelID)
+	assert.Equal(t, "stagingchannel", applied[ChannelIDFlag])
+	assert.False(t, flags.Changed(ChannelIDFlag))
+	_, ok := applied[UserFlag]
+	assert.False(t, ok)
+	assert.Equal(t, "org2", c.opts.orgIDsStr)
+	assert.Equal(t, 10, c.opts.iterations)
+	assert.Equal(t, defaultUser, c.opts.user)
+
+	// Env > profile
+	os.Setenv(EnvVarName(ChannelIDFlag), "envchannel")
+	flags = newFlags()
+	require.NoError(t, flags.Parse(nil))
+	_, err = applySettings(flags)
+	require.NoError(t, err)
+	assert.Equal(t, "envchannel", c.opts.channelID)
+	assert.Equal(t, "org2", c.opts.orgIDsStr)
+
+	// Flag > env
+	flags = newFlags()
+	require.NoError(t, flags.Parse([]string{"--cid", "flagchannel"}))
+	_, err = applySettings(flags)
+	require.NoError(t, err)
+	assert.Equal(t, "flagchannel", c.opts.channelID)
+	os.Unsetenv(EnvVarName(ChannelIDFlag))
+
+	// Profile selected by flag and env
+	flags = newFlags()
+	require.NoError(t, flags.Parse([]string{"--profile", "prod"}))
+	_, err = applySettings(flags)
+	require.NoError(t, err)
+	assert.Equal(t, "prodchannel", c.opts.channelID)
+
+	os.Setenv(ProfileEnvVar, "prod")
+	defer os.Unsetenv(ProfileEnvVar)
+	flags = newFlags()
+	require.NoError(t, flags.Parse(nil))
+	_, err = applySettings(flags)
+	require.NoError(t, err)
+	assert.Equal(t, "prodchannel", c.opts.channelID)
+
+	// Unknown profile
+	flags = newFlags()
+	require.NoError(t, flags.Parse([]string{"--profile", "unknown"}))
+	_, err = applySettings(flags)
+	assert.Error(t, err)
+
+	// Invalid value
+	os.Setenv(EnvVarName(IterationsFlag), "many")
+	defer os.Unsetenv(EnvVarName(IterationsFlag))
+	flags = newFlags()
+	require.NoError(t, flags.Parse(nil))
+	_, err = applySettings(flags)
+	assert.Error(t, err)
+}
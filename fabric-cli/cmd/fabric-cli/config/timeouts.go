@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// timeoutKeys maps each timeout flag to the connection profile keys that it configures
+var timeoutKeys = map[string][]string{
+	ConnectionTimeoutFlag: {
+		"client.peer.timeout.connection",
+		"client.orderer.timeout.connection",
+		"client.discovery.timeout.connection",
+		"client.eventService.timeout.registrationResponse",
+	},
+	EndorsementTimeoutFlag: {"client.peer.timeout.response"},
+	CommitTimeoutFlag: {
+		"client.global.timeout.execute",
+		"client.global.timeout.resmgmt",
+	},
+	OrdererTimeoutFlag:   {"client.orderer.timeout.response"},
+	DiscoveryTimeoutFlag: {"client.discovery.timeout.response"},
+	QueryTimeoutFlag:     {"client.global.timeout.query"},
+}
+
+// timeoutFlagByType maps the SDK's timeout types to the flag that configures them
+var timeoutFlagByType = map[fab.TimeoutType]string{
+	fab.PeerConnection:      ConnectionTimeoutFlag,
+	fab.OrdererConnection:   ConnectionTimeoutFlag,
+	fab.DiscoveryConnection: ConnectionTimeoutFlag,
+	fab.EventReg:            ConnectionTimeoutFlag,
+	fab.PeerResponse:        EndorsementTimeoutFlag,
+	fab.Execute:             CommitTimeoutFlag,
+	fab.ResMgmt:             CommitTimeoutFlag,
+	fab.OrdererResponse:     OrdererTimeoutFlag,
+	fab.DiscoveryResponse:   DiscoveryTimeoutFlag,
+	fab.Query:               QueryTimeoutFlag,
+}
+
+// timeoutFor returns the value of the given timeout flag or the value of --timeout
+// if the flag isn't set and --timeout is
+func (c *CLIConfig) timeoutFor(flag string) time.Duration {
+	if !c.IsFlagSpecified(flag) && c.IsFlagSpecified(TimeoutFlag) {
+		return time.Duration(c.opts.timeout) * time.Millisecond
+	}
+
+	var value int64
+	switch flag {
+	case ConnectionTimeoutFlag:
+		value = c.opts.connectionTimeout
+	case EndorsementTimeoutFlag:
+		value = c.opts.endorsementTimeout
+	case CommitTimeoutFlag:
+		value = c.opts.commitTimeout
+	case OrdererTimeoutFlag:
+		value = c.opts.ordererTimeout
+	case DiscoveryTimeoutFlag:
+		value = c.opts.discoveryTimeout
+	case QueryTimeoutFlag:
+		value = c.opts.queryTimeout
+	}
+	return time.Duration(value) * time.Millisecond
+}
+
+// timeoutBackend is a config backend that holds timeout values
+type timeoutBackend map[string]interface{}
+
+// Lookup returns the value for the given key
+func (b timeoutBackend) Lookup(key string) (interface{}, bool) {
+	value, ok := b[key]
+	return value, ok
+}
+
+// withTimeouts wraps the given config provider so that the timeouts specified on the command-line (or in
+// the environment or profile) override those of the connection profile. The defaults of the timeout flags
+// are used for the timeouts that are in neither.
+func (c *CLIConfig) withTimeouts(provider core.ConfigProvider) core.ConfigProvider {
+	return func() ([]core.ConfigBackend, error) {
+		backends, err := provider()
+		if err != nil {
+			return nil, err
+		}
+
+		overrides := timeoutBackend{}
+		defaults := timeoutBackend{}
+		for flag, keys := range timeoutKeys {
+			timeout := c.timeoutFor(flag)
+			if timeout <= 0 {
+				continue
+			}
+			for _, key := range keys {
+				if c.IsFlagSpecified(flag) || c.IsFlagSpecified(TimeoutFlag) {
+					overrides[key] = timeout
+				} else {
+					defaults[key] = timeout
+				}
+			}
+		}
+
+		// Backends are consulted in order so the overrides come first and the defaults last
+		var all []core.ConfigBackend
+		all = append(all, overrides)
+		all = append(all, backends...)
+		all = append(all, defaults)
+		return all, nil
+	}
+}
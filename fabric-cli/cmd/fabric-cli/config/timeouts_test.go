@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	sdklookup "github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimeouts(t *testing.T) {
+	profile := timeoutBackend{
+		"client.peer.timeout.response":    "45s",
+		"client.orderer.timeout.response": "50s",
+	}
+	provider := func() ([]core.ConfigBackend, error) {
+		return []core.ConfigBackend{profile}, nil
+	}
+
+	newConfig := func(args ...string) *CLIConfig {
+		c := New().IgnoreSettings()
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		c.InitTimeout(flags)
+		c.InitConnectionTimeout(flags)
+		c.InitEndorsementTimeout(flags)
+		c.InitCommitTimeout(flags)
+		c.InitOrdererTimeout(flags)
+		c.InitDiscoveryTimeout(flags)
+		c.InitQueryTimeout(flags)
+		require.NoError(t, flags.Parse(args))
+		require.NoError(t, c.InitConfig(flags))
+		return c
+	}
+
+	lookupTimeouts := func(c *CLIConfig) *sdklookup.ConfigLookup {
+		backends, err := c.withTimeouts(provider)()
+		require.NoError(t, err)
+		return sdklookup.New(backends...)
+	}
+
+	// Connection profile > flag defaults
+	c := newConfig()
+	l := lookupTimeouts(c)
+	assert.Equal(t, 45*time.Second, l.GetDuration("client.peer.timeout.response"))
+	assert.Equal(t, 50*time.Second, l.GetDuration("client.orderer.timeout.response"))
+	assert.Equal(t, 10*time.Second, l.GetDuration("client.peer.timeout.connection"))
+	assert.Equal(t, 60*time.Second, l.GetDuration("client.global.timeout.execute"))
+	assert.Equal(t, 30*time.Second, l.GetDuration("client.global.timeout.query"))
+
+	// Flag > --timeout > connection profile
+	c = newConfig("--timeout", "20000", "--endorsementtimeout", "5000")
+	l = lookupTimeouts(c)
+	assert.Equal(t, 5*time.Second, l.GetDuration("client.peer.timeout.response"))
+	assert.Equal(t, 20*time.Second, l.GetDuration("client.orderer.timeout.response"))
+	assert.Equal(t, 20*time.Second, l.GetDuration("client.discovery.timeout.response"))
+	assert.Equal(t, 5*time.Second, c.Timeout(fab.PeerResponse))
+	assert.Equal(t, 20*time.Second, c.Timeout(fab.ResMgmt))
+}
@@ -0,0 +1,718 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Severity is the severity of a problem found in the connection profile
+type Severity string
+
+const (
+	// SeverityError indicates that the SDK can't be initialized (or commands will fail) with the connection profile
+	SeverityError Severity = "ERROR"
+
+	// SeverityWarning indicates a problem that may cause some commands to fail
+	SeverityWarning Severity = "WARNING"
+)
+
+// Problem is a problem found in the connection profile
+type Problem struct {
+	// Severity is the severity of the problem
+	Severity Severity
+
+	// Path is the location of the problem in the connection profile, e.g. peers.peer0.org1.example.com.url
+	Path string
+
+	// Line is the line number in the connection profile (0 if unknown)
+	Line int
+
+	// Message describes the problem
+	Message string
+
+	path []string
+}
+
+// String returns the problem formatted as "SEVERITY: path: message"
+func (p Problem) String() string {
+	if p.Path == "" {
+		return fmt.Sprintf("%s: %s", p.Severity, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Severity, p.Path, p.Message)
+}
+
+// Location returns the location of the problem in the given file, e.g. config.yaml:12
+func (p Problem) Location(file string) string {
+	if p.Line == 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d", file, p.Line)
+}
+
+// Fatal returns true if the problem prevents a command on the given channel from running. Only errors
+// are fatal, and errors in the certificate authorities (which are only used by the identity commands)
+// or in the definitions of other channels are not.
+func (p Problem) Fatal(channelID string) bool {
+	if p.Severity != SeverityError {
+		return false
+	}
+	if len(p.path) < 2 {
+		return true
+	}
+	switch strings.ToLower(p.path[0]) {
+	case "certificateauthorities":
+		return false
+	case "channels":
+		return strings.EqualFold(p.path[1], channelID)
+	default:
+		return true
+	}
+}
+
+// Problems is a list of problems found in the connection profile
+type Problems []Problem
+
+// HasErrors returns true if any of the problems is an error
+func (p Problems) HasErrors() bool {
+	for _, problem := range p {
+		if problem.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of problems with the given severity
+func (p Problems) Count(severity Severity) int {
+	n := 0
+	for _, problem := range p {
+		if problem.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// entity matcher types and the sections of the connection profile that their mapped hosts refer to
+var matcherSections = map[string]string{
+	"peer":                 "peers",
+	"orderer":              "orderers",
+	"certificateauthority": "certificateAuthorities",
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// ValidateConnectionProfile checks the organizations, peers, orderers, channels, certificate authorities,
+// entity matchers and file references of the given connection profile (as used by the SDK). All of the
+// problems that are found are returned, sorted by line number. An error is returned only if the file
+// can't be read.
+func ValidateConnectionProfile(path string) (Problems, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading connection profile [%s]", path)
+	}
+	return validateConnectionProfile(raw), nil
+}
+
+func validateConnectionProfile(raw []byte) Problems {
+	v := &validator{lines: locateKeys(raw)}
+
+	var doc interface{}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		line := 0
+		if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		v.problems = append(v.problems, Problem{Severity: SeverityError, Line: line, Message: err.Error()})
+		return v.problems
+	}
+
+	root, ok := normalize(doc).(map[string]interface{})
+	if !ok {
+		v.errorf(nil, "the connection profile must be a YAML map")
+		return v.problems
+	}
+	v.root = root
+
+	v.validate()
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		return v.problems[i].Line < v.problems[j].Line
+	})
+	return v.problems
+}
+
+type validator struct {
+	root     map[string]interface{}
+	lines    map[string]int
+	problems Problems
+}
+
+func (v *validator) validate() {
+	v.validateClient()
+	v.validateOrganizations()
+	v.validateEndpoints("orderers")
+	v.validateEndpoints("peers")
+	v.validatePeerReferences()
+	v.validateChannels()
+	v.validateCertificateAuthorities()
+	v.validateEntityMatchers()
+}
+
+func (v *validator) validateClient() {
+	client, ok := v.mapAt("client")
+	if !ok {
+		v.errorf([]string{"client"}, "the client section is missing")
+		return
+	}
+
+	org := stringValue(lookup(client, "organization"))
+	if org == "" {
+		v.errorf([]string{"client", "organization"}, "the client organization is not specified")
+	} else if !v.exists("organizations", org) {
+		v.errorf([]string{"client", "organization"}, "organization [%s] is not defined in the organizations section", org)
+	}
+
+	if cryptoPath := stringValue(lookupPath(client, "cryptoconfig", "path")); cryptoPath != "" {
+		v.checkPath([]string{"client", "cryptoconfig", "path"}, cryptoPath, true, SeverityWarning)
+	}
+
+	// The client TLS key and certificate are only needed for mutual TLS
+	for _, kind := range []string{"key", "cert"} {
+		v.checkFileRef([]string{"client", "tlsCerts", "client", kind}, lookupPath(client, "tlsCerts", "client", kind), SeverityWarning)
+	}
+}
+
+func (v *validator) validateOrganizations() {
+	orgs, ok := v.mapAt("organizations")
+	if !ok || len(orgs) == 0 {
+		v.errorf([]string{"organizations"}, "no organizations are defined")
+		return
+	}
+
+	for _, name := range sortedKeys(orgs) {
+		org, _ := orgs[name].(map[string]interface{})
+		path := []string{"organizations", name}
+
+		if stringValue(lookup(org, "mspid")) == "" {
+			v.errorf(append(path, "mspid"), "the MSP ID is not specified")
+		}
+
+		peers := listValue(lookup(org, "peers"))
+		for i, peer := range peers {
+			if !v.resolves("peer", stringValue(peer)) {
+				v.errorf(append(path, "peers", index(i)), "peer [%s] is not defined in the peers section", stringValue(peer))
+			}
+		}
+		if len(peers) == 0 && !isOrdererOrg(name, org) {
+			v.warnf(append(path, "peers"), "organization [%s] has no peers", name)
+		}
+
+		for i, ca := range listValue(lookup(org, "certificateAuthorities")) {
+			if !v.resolves("certificateauthority", stringValue(ca)) {
+				v.errorf(append(path, "certificateAuthorities", index(i)), "certificate authority [%s] is not defined in the certificateAuthorities section", stringValue(ca))
+			}
+		}
+	}
+}
+
+// validateEndpoints validates the URLs, TLS certificates and gRPC options of the peers or orderers
+func (v *validator) validateEndpoints(section string) {
+	endpoints, ok := v.mapAt(section)
+	if !ok || len(endpoints) == 0 {
+		if section == "orderers" {
+			v.warnf([]string{section}, "no orderers are defined (transactions can't be submitted)")
+		} else {
+			v.errorf([]string{section}, "no peers are defined")
+		}
+		return
+	}
+
+	for _, name := range sortedKeys(endpoints) {
+		endpoint, _ := endpoints[name].(map[string]interface{})
+		path := []string{section, name}
+
+		rawURL := stringValue(lookup(endpoint, "url"))
+		if rawURL == "" {
+			v.errorf(append(path, "url"), "the URL is not specified")
+			continue
+		}
+
+		host, secure, err := parseURL(rawURL, "grpc", "grpcs")
+		if err != nil {
+			v.errorf(append(path, "url"), "%s", err)
+			continue
+		}
+
+		if eventURL := stringValue(lookup(endpoint, "eventUrl")); eventURL != "" {
+			if _, _, err := parseURL(eventURL, "grpc", "grpcs"); err != nil {
+				v.errorf(append(path, "eventUrl"), "%s", err)
+			}
+		}
+
+		allowInsecure := boolValue(lookupPath(endpoint, "grpcOptions", "allow-insecure"))
+		tlsEnabled := secure || (!strings.Contains(rawURL, "://") && !allowInsecure)
+
+		tlsCACerts := lookup(endpoint, "tlsCACerts")
+		if tlsCACerts == nil {
+			if tlsEnabled {
+				v.errorf(append(path, "tlsCACerts"), "TLS is enabled for [%s] but no TLS CA certificate is specified", rawURL)
+			}
+		} else {
+			v.checkFileRef(append(path, "tlsCACerts"), tlsCACerts, SeverityError)
+		}
+
+		override := stringValue(lookupPath(endpoint, "grpcOptions", "ssl-target-name-override"))
+		if tlsEnabled && override == "" && isLocalHost(host) && !strings.EqualFold(host, name) {
+			v.warnf(append(path, "grpcOptions"), "ssl-target-name-override is not set and the host [%s] probably doesn't match the TLS certificate of [%s]", host, name)
+		}
+	}
+}
+
+// validatePeerReferences warns about peers that don't belong to any organization since the CLI only
+// uses the peers of the organizations
+func (v *validator) validatePeerReferences() {
+	peers, _ := v.mapAt("peers")
+	orgs, _ := v.mapAt("organizations")
+
+	referenced := make(map[string]bool)
+	for _, org := range orgs {
+		orgMap, _ := org.(map[string]interface{})
+		for _, peer := range listValue(lookup(orgMap, "peers")) {
+			referenced[strings.ToLower(stringValue(peer))] = true
+		}
+	}
+
+	for _, name := range sortedKeys(peers) {
+		if !referenced[strings.ToLower(name)] {
+			v.warnf([]string{"peers", name}, "peer [%s] is not referenced by any organization and won't be used", name)
+		}
+	}
+}
+
+func (v *validator) validateChannels() {
+	channels, ok := v.mapAt("channels")
+	if !ok {
+		return
+	}
+
+	for _, name := range sortedKeys(channels) {
+		channel, _ := channels[name].(map[string]interface{})
+		path := []string{"channels", name}
+
+		peers, _ := lookup(channel, "peers").(map[string]interface{})
+		if len(peers) == 0 {
+			v.warnf(append(path, "peers"), "channel [%s] has no peers", name)
+		}
+		for _, peer := range sortedKeys(peers) {
+			if !v.resolves("peer", peer) {
+				v.errorf(append(path, "peers", peer), "peer [%s] is not defined in the peers section or matched by an entity matcher", peer)
+			}
+		}
+
+		for i, orderer := range listValue(lookup(channel, "orderers")) {
+			if !v.resolves("orderer", stringValue(orderer)) {
+				v.errorf(append(path, "orderers", index(i)), "orderer [%s] is not defined in the orderers section or matched by an entity matcher", stringValue(orderer))
+			}
+		}
+	}
+}
+
+func (v *validator) validateCertificateAuthorities() {
+	cas, ok := v.mapAt("certificateAuthorities")
+	if !ok {
+		return
+	}
+
+	for _, name := range sortedKeys(cas) {
+		ca, _ := cas[name].(map[string]interface{})
+		path := []string{"certificateAuthorities", name}
+
+		rawURL := stringValue(lookup(ca, "url"))
+		if rawURL == "" {
+			v.errorf(append(path, "url"), "the URL is not specified")
+		} else if _, secure, err := parseURL(rawURL, "http", "https"); err != nil {
+			v.errorf(append(path, "url"), "%s", err)
+		} else if secure && lookup(ca, "tlsCACerts") == nil {
+			v.errorf(append(path, "tlsCACerts"), "TLS is enabled for [%s] but no TLS CA certificate is specified", rawURL)
+		}
+
+		tlsCACerts, _ := lookup(ca, "tlsCACerts").(map[string]interface{})
+		if tlsCACerts == nil {
+			continue
+		}
+		// The certificate authorities are only used by the identity commands
+		v.checkFileRef(append(path, "tlsCACerts"), tlsCACerts, SeverityWarning)
+		for _, kind := range []string{"key", "cert"} {
+			v.checkFileRef(append(path, "tlsCACerts", "client", kind), lookupPath(tlsCACerts, "client", kind), SeverityWarning)
+		}
+	}
+}
+
+func (v *validator) validateEntityMatchers() {
+	matchers, ok := v.mapAt("entityMatchers")
+	if !ok {
+		return
+	}
+
+	for _, kind := range sortedKeys(matchers) {
+		section, ok := matcherSections[strings.ToLower(kind)]
+		if !ok {
+			v.warnf([]string{"entityMatchers", kind}, "unknown entity matcher type [%s] (expecting peer, orderer or certificateAuthority)", kind)
+			continue
+		}
+
+		for i, m := range listValue(matchers[kind]) {
+			matcher, _ := m.(map[string]interface{})
+			path := []string{"entityMatchers", kind, index(i)}
+
+			pattern := stringValue(lookup(matcher, "pattern"))
+			if pattern == "" {
+				v.errorf(append(path, "pattern"), "the pattern is not specified")
+			} else if _, err := regexp.Compile(pattern); err != nil {
+				v.errorf(append(path, "pattern"), "invalid pattern: %s", err)
+			}
+
+			if mappedHost := stringValue(lookup(matcher, "mappedHost")); mappedHost != "" && !v.exists(section, mappedHost) {
+				v.errorf(append(path, "mappedHost"), "mapped host [%s] is not defined in the %s section", mappedHost, section)
+			}
+		}
+	}
+}
+
+// checkFileRef checks a file reference of the form {path: ..., pem: ...}. The reference is valid
+// if it contains an embedded PEM or a path to a file that exists.
+func (v *validator) checkFileRef(path []string, ref interface{}, severity Severity) {
+	refMap, ok := ref.(map[string]interface{})
+	if !ok || stringValue(lookup(refMap, "pem")) != "" {
+		return
+	}
+	if file := stringValue(lookup(refMap, "path")); file != "" {
+		v.checkPath(append(path, "path"), file, false, severity)
+	}
+}
+
+// checkPath checks that the given path (which may contain variables such as ${GOPATH}) exists
+func (v *validator) checkPath(path []string, file string, dir bool, severity Severity) {
+	resolved := pathvar.Subst(file)
+	if strings.Contains(resolved, "${") {
+		v.add(severity, path, "unresolved variable in path [%s] (is the environment variable set?)", resolved)
+		return
+	}
+
+	info, err := os.Stat(resolved)
+	switch {
+	case os.IsNotExist(err):
+		v.add(severity, path, "file not found: %s", resolved)
+	case err != nil:
+		v.add(severity, path, "unable to access file [%s]: %s", resolved, err)
+	case dir && !info.IsDir():
+		v.add(severity, path, "not a directory: %s", resolved)
+	case !dir && info.IsDir():
+		v.add(severity, path, "expecting a file but found a directory: %s", resolved)
+	}
+}
+
+// exists returns true if the given section contains an entry with the given name (case-insensitive)
+func (v *validator) exists(section, name string) bool {
+	entries, _ := v.mapAt(section)
+	return name != "" && lookup(entries, name) != nil
+}
+
+// resolves returns true if the given name is defined in the corresponding section of the
+// connection profile or is matched by an entity matcher
+func (v *validator) resolves(kind, name string) bool {
+	if v.exists(matcherSections[kind], name) {
+		return true
+	}
+
+	matchers, _ := v.mapAt("entityMatchers")
+	for _, m := range listValue(lookup(matchers, kind)) {
+		matcher, _ := m.(map[string]interface{})
+		re, err := regexp.Compile(stringValue(lookup(matcher, "pattern")))
+		if err != nil || !re.MatchString(name) {
+			continue
+		}
+		mappedHost := stringValue(lookup(matcher, "mappedHost"))
+		if mappedHost == "" || v.exists(matcherSections[kind], mappedHost) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) mapAt(section string) (map[string]interface{}, bool) {
+	m, ok := lookup(v.root, section).(map[string]interface{})
+	return m, ok
+}
+
+func (v *validator) errorf(path []string, format string, args ...interface{}) {
+	v.add(SeverityError, path, format, args...)
+}
+
+func (v *validator) warnf(path []string, format string, args ...interface{}) {
+	v.add(SeverityWarning, path, format, args...)
+}
+
+func (v *validator) add(severity Severity, path []string, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		Severity: severity,
+		Path:     displayPath(path),
+		Line:     v.line(path),
+		Message:  fmt.Sprintf(format, args...),
+		path:     append([]string(nil), path...),
+	})
+}
+
+// line returns the line of the given path or, if the path isn't in the file (e.g. a missing
+// field), the line of its closest ancestor
+func (v *validator) line(path []string) int {
+	for n := len(path); n > 0; n-- {
+		if line, ok := v.lines[lineKey(path[:n])]; ok {
+			return line
+		}
+	}
+	return 0
+}
+
+// locateKeys returns the line numbers of the keys and list items in a block-style YAML document.
+// The keys of the returned map are the lower-case paths of the keys (see lineKey).
+func locateKeys(raw []byte) map[string]int {
+	type entry struct {
+		indent int
+		name   string
+		item   bool
+	}
+
+	lines := make(map[string]int)
+	nextIndex := make(map[string]int)
+	var stack []entry
+	blockIndent := -1
+
+	path := func() []string {
+		var p []string
+		for _, e := range stack {
+			p = append(p, e.name)
+		}
+		return p
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 64*1024), len(raw)+1)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		content := strings.TrimLeft(text, " ")
+		indent := len(text) - len(content)
+
+		// Skip the lines of block scalars (e.g. an embedded PEM)
+		if blockIndent >= 0 {
+			if content == "" || indent > blockIndent {
+				continue
+			}
+			blockIndent = -1
+		}
+		if content == "" || strings.HasPrefix(content, "#") || content == "---" {
+			continue
+		}
+
+		// List items (a list may have the same indentation as its parent key)
+		for strings.HasPrefix(content, "- ") || content == "-" {
+			for len(stack) > 0 && (stack[len(stack)-1].indent > indent || (stack[len(stack)-1].indent == indent && stack[len(stack)-1].item)) {
+				stack = stack[:len(stack)-1]
+			}
+			parent := lineKey(path())
+			stack = append(stack, entry{indent: indent, name: index(nextIndex[parent]), item: true})
+			nextIndex[parent]++
+			lines[lineKey(path())] = lineNum
+
+			rest := strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+			indent += len(content) - len(rest)
+			content = rest
+		}
+
+		key, value, ok := splitKey(content)
+		if !ok {
+			continue
+		}
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, entry{indent: indent, name: key})
+		p := lineKey(path())
+		lines[p] = lineNum
+		delete(nextIndex, p)
+
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = indent
+		}
+	}
+
+	return lines
+}
+
+// splitKey splits a "key: value" line into the (unquoted) key and the value
+func splitKey(content string) (key, value string, ok bool) {
+	var i int
+	if strings.HasSuffix(content, ":") && !strings.Contains(content, ": ") {
+		i = len(content) - 1
+	} else if i = strings.Index(content, ": "); i < 0 {
+		return "", "", false
+	}
+
+	key = strings.TrimSpace(content[:i])
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		key = key[1 : len(key)-1]
+	}
+	return key, strings.TrimSpace(content[i+1:]), key != ""
+}
+
+func lineKey(path []string) string {
+	return strings.ToLower(strings.Join(path, "\x00"))
+}
+
+func displayPath(path []string) string {
+	var s string
+	for _, p := range path {
+		if strings.HasPrefix(p, "[") || s == "" {
+			s += p
+		} else {
+			s += "." + p
+		}
+	}
+	return s
+}
+
+func index(i int) string {
+	return fmt.Sprintf("[%d]", i)
+}
+
+// normalize converts the maps in the unmarshalled YAML to map[string]interface{}
+func normalize(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			m[fmt.Sprint(k)] = normalize(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range value {
+			value[i] = normalize(e)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// lookup returns the value of the given key. Keys are case-insensitive (as they are for the SDK).
+func lookup(m map[string]interface{}, key string) interface{} {
+	if value, ok := m[key]; ok {
+		return value
+	}
+	for k, value := range m {
+		if strings.EqualFold(k, key) {
+			return value
+		}
+	}
+	return nil
+}
+
+func lookupPath(m map[string]interface{}, keys ...string) interface{} {
+	var value interface{} = m
+	for _, key := range keys {
+		mv, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = lookup(mv, key)
+	}
+	return value
+}
+
+func stringValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func boolValue(v interface{}) bool {
+	switch value := v.(type) {
+	case bool:
+		return value
+	case string:
+		b, _ := strconv.ParseBool(value)
+		return b
+	default:
+		return false
+	}
+}
+
+func listValue(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isOrdererOrg returns true if the organization appears to be an orderer organization (which has no peers)
+func isOrdererOrg(name string, org map[string]interface{}) bool {
+	return strings.Contains(strings.ToLower(name), "orderer") ||
+		strings.Contains(strings.ToLower(stringValue(lookup(org, "mspid"))), "orderer")
+}
+
+// parseURL validates a URL of the form [scheme://]host:port and returns the host and whether the
+// secure scheme is used
+func parseURL(rawURL, insecureScheme, secureScheme string) (host string, secure bool, err error) {
+	address := rawURL
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		scheme := strings.ToLower(rawURL[:i])
+		if scheme != insecureScheme && scheme != secureScheme {
+			return "", false, errors.Errorf("invalid scheme [%s] in URL [%s] (expecting %s or %s)", scheme, rawURL, insecureScheme, secureScheme)
+		}
+		secure = scheme == secureScheme
+		address = rawURL[i+3:]
+	}
+
+	host, port, err := net.SplitHostPort(strings.TrimSuffix(address, "/"))
+	if err != nil {
+		return "", false, errors.Errorf("invalid URL [%s]: expecting [%s://]host:port", rawURL, secureScheme)
+	}
+	if host == "" {
+		return "", false, errors.Errorf("invalid URL [%s]: the host is missing", rawURL)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+		return "", false, errors.Errorf("invalid port [%s] in URL [%s]", port, rawURL)
+	}
+	return host, secure, nil
+}
+
+func isLocalHost(host string) bool {
+	return strings.EqualFold(host, "localhost") || net.ParseIP(host) != nil
+}
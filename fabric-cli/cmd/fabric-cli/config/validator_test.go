@@ -0,0 +1,126 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const invalidProfile = `
+client:
+  organization: org3
+organizations:
+  org1:
+    mspid: Org1MSP
+    peers:
+    - peer0.org1.example.com
+    - peer9.org1.example.com
+  org2:
+    mspid: Org2MSP
+orderers:
+  orderer.example.com:
+    url: grpcs://orderer.example.com
+peers:
+  peer0.org1.example.com:
+    url: localhost:7051
+    tlsCACerts:
+      pem: |
+        -----BEGIN CERTIFICATE-----
+        url: not-a-key
+        -----END CERTIFICATE-----
+  peer1.org1.example.com:
+    url: grpc://localhost:7151
+channels:
+  mychannel:
+    peers:
+      peer0.org1.example.com:
+        endorsingPeer: true
+      peer0.org2.example.com:
+        endorsingPeer: true
+entityMatchers:
+  peer:
+    - pattern: (peer0.org2
+      mappedHost: peer0.org2.example.com
+`
+
+func TestValidateConnectionProfile(t *testing.T) {
+	problems := validateConnectionProfile([]byte(invalidProfile))
+
+	var actual []string
+	for _, p := range problems {
+		actual = append(actual, p.Location("config.yaml")+": "+p.String())
+	}
+
+	assert.Equal(t, []string{
+		"config.yaml:3: ERROR: client.organization: organization [org3] is not defined in the organizations section",
+		"config.yaml:9: ERROR: organizations.org1.peers[1]: peer [peer9.org1.example.com] is not defined in the peers section",
+		"config.yaml:10: WARNING: organizations.org2.peers: organization [org2] has no peers",
+		"config.yaml:14: ERROR: orderers.orderer.example.com.url: invalid URL [grpcs://orderer.example.com]: expecting [grpcs://]host:port",
+		"config.yaml:16: WARNING: peers.peer0.org1.example.com.grpcOptions: ssl-target-name-override is not set and the host [localhost] probably doesn't match the TLS certificate of [peer0.org1.example.com]",
+		"config.yaml:23: WARNING: peers.peer1.org1.example.com: peer [peer1.org1.example.com] is not referenced by any organization and won't be used",
+		"config.yaml:30: ERROR: channels.mychannel.peers.peer0.org2.example.com: peer [peer0.org2.example.com] is not defined in the peers section or matched by an entity matcher",
+		"config.yaml:34: ERROR: entityMatchers.peer[0].pattern: invalid pattern: error parsing regexp: missing closing ): `(peer0.org2`",
+		"config.yaml:35: ERROR: entityMatchers.peer[0].mappedHost: mapped host [peer0.org2.example.com] is not defined in the peers section",
+	}, actual)
+	assert.True(t, problems.HasErrors())
+	assert.Equal(t, 3, problems.Count(SeverityWarning))
+}
+
+func TestValidateConnectionProfileSyntaxError(t *testing.T) {
+	problems := validateConnectionProfile([]byte("client:\n  organization: org1\n bad: indent\n"))
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, SeverityError, problems[0].Severity)
+		assert.NotZero(t, problems[0].Line)
+	}
+}
+
+const profileWithMissingFiles = `
+client:
+  organization: org1
+  tlsCerts:
+    client:
+      key:
+        path: /nonexistent/client.key
+organizations:
+  org1:
+    mspid: Org1MSP
+    peers:
+    - peer0.org1.example.com
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com:7051
+channels:
+  mychannel:
+    peers:
+      peer8.org1.example.com:
+  otherchannel:
+    peers:
+      peer9.org1.example.com:
+certificateAuthorities:
+  ca.org1.example.com:
+    url: https://ca.org1.example.com:7054
+`
+
+func TestProblemFatal(t *testing.T) {
+	problems := validateConnectionProfile([]byte(profileWithMissingFiles))
+
+	var actual []string
+	for _, p := range problems {
+		actual = append(actual, fmt.Sprintf("%t %s", p.Fatal("mychannel"), p))
+	}
+
+	assert.Equal(t, []string{
+		"false WARNING: orderers: no orderers are defined (transactions can't be submitted)",
+		"false WARNING: client.tlsCerts.client.key.path: file not found: /nonexistent/client.key",
+		"true ERROR: channels.mychannel.peers.peer8.org1.example.com: peer [peer8.org1.example.com] is not defined in the peers section or matched by an entity matcher",
+		"false ERROR: channels.otherchannel.peers.peer9.org1.example.com: peer [peer9.org1.example.com] is not defined in the peers section or matched by an entity matcher",
+		"false ERROR: certificateAuthorities.ca.org1.example.com.tlsCACerts: TLS is enabled for [https://ca.org1.example.com:7054] but no TLS CA certificate is specified",
+	}, actual)
+}
@@ -0,0 +1,419 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package credential loads signing identities (an enrollment certificate and private key) from sources
+// other than the SDK's credential store: PEM files, PKCS#12 bundles and directories in MSP layout.
+// Encrypted private keys are unlocked with a passphrase taken from the environment or a prompt.
+package credential
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pkcs12"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// PassphraseEnvVar is the environment variable that holds the passphrase of an encrypted private key or PKCS#12 bundle
+const PassphraseEnvVar = "FABRIC_CLI_KEY_PASSPHRASE"
+
+// encryptedPKCS8Type is the PEM type of an encrypted PKCS#8 private key
+const encryptedPKCS8Type = "ENCRYPTED PRIVATE KEY"
+
+// Credential contains the PEM-encoded enrollment certificate and (unencrypted) private key of a signing identity
+type Credential struct {
+	Cert []byte
+	Key  []byte
+}
+
+// Source loads a credential
+type Source interface {
+	// Load loads the credential
+	Load() (*Credential, error)
+
+	// String returns a description of the source
+	String() string
+}
+
+// PassphraseFunc returns the passphrase used to decrypt a private key or PKCS#12 bundle.
+// The description identifies what is being unlocked.
+type PassphraseFunc func(description string) ([]byte, error)
+
+// EnvOrPrompt returns the passphrase from the FABRIC_CLI_KEY_PASSPHRASE environment variable
+// or, if it isn't set, prompts for it on the terminal
+func EnvOrPrompt(description string) ([]byte, error) {
+	if passphrase, ok := os.LookupEnv(PassphraseEnvVar); ok {
+		return []byte(passphrase), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(fd) {
+		return nil, errors.Errorf("a passphrase is required for %s: set %s or run from a terminal", description, PassphraseEnvVar)
+	}
+
+	fmt.Fprintf(os.Stderr, "Enter the passphrase for %s: ", description)
+	passphrase, err := terminal.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading passphrase")
+	}
+	return passphrase, nil
+}
+
+// FromPEM returns a source that loads the certificate and private key from the given PEM files.
+// If the private key is encrypted then it's decrypted with the passphrase.
+func FromPEM(certPath, keyPath string, passphrase PassphraseFunc) Source {
+	return &pemSource{certPath: certPath, keyPath: keyPath, passphrase: passphrase}
+}
+
+// FromPKCS12 returns a source that loads the certificate and private key from the given PKCS#12 bundle
+func FromPKCS12(path string, passphrase PassphraseFunc) Source {
+	return &pkcs12Source{path: path, passphrase: passphrase}
+}
+
+// FromMSPDir returns a source that loads the certificate from signcerts and the matching private
+// key from keystore in the given directory in MSP layout
+func FromMSPDir(dir string, passphrase PassphraseFunc) Source {
+	return &mspDirSource{dir: dir, passphrase: passphrase}
+}
+
+type pemSource struct {
+	certPath   string
+	keyPath    string
+	passphrase PassphraseFunc
+}
+
+func (s *pemSource) String() string {
+	return fmt.Sprintf("certificate [%s] and key [%s]", s.certPath, s.keyPath)
+}
+
+func (s *pemSource) Load() (*Credential, error) {
+	cert, err := readCert(s.certPath)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := ioutil.ReadFile(s.keyPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading private key [%s]", s.keyPath)
+	}
+
+	key, err := decodeKey(keyPEM, s.keyPath, s.passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCredential(cert, key)
+}
+
+type pkcs12Source struct {
+	path       string
+	passphrase PassphraseFunc
+}
+
+func (s *pkcs12Source) String() string {
+	return fmt.Sprintf("PKCS#12 bundle [%s]", s.path)
+}
+
+func (s *pkcs12Source) Load() (*Credential, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading PKCS#12 bundle [%s]", s.path)
+	}
+
+	// Try without a password first so that unprotected bundles don't require a prompt
+	key, cert, err := pkcs12.Decode(data, "")
+	if err == pkcs12.ErrIncorrectPassword {
+		if s.passphrase == nil {
+			return nil, errors.Errorf("PKCS#12 bundle [%s] is protected by a password", s.path)
+		}
+		passphrase, e := s.passphrase(s.String())
+		if e != nil {
+			return nil, e
+		}
+		key, cert, err = pkcs12.Decode(data, string(passphrase))
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding PKCS#12 bundle [%s]", s.path)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("unsupported private key type in PKCS#12 bundle [%s]: %T", s.path, key)
+	}
+
+	return newCredential(cert, signer)
+}
+
+type mspDirSource struct {
+	dir        string
+	passphrase PassphraseFunc
+}
+
+func (s *mspDirSource) String() string {
+	return fmt.Sprintf("MSP directory [%s]", s.dir)
+}
+
+func (s *mspDirSource) Load() (*Credential, error) {
+	certPaths, err := listFiles(filepath.Join(s.dir, "signcerts"))
+	if err != nil {
+		return nil, err
+	}
+	if len(certPaths) == 0 {
+		return nil, errors.Errorf("no certificate found in [%s]", filepath.Join(s.dir, "signcerts"))
+	}
+
+	cert, err := readCert(certPaths[0])
+	if err != nil {
+		return nil, err
+	}
+
+	keyPaths, err := listFiles(filepath.Join(s.dir, "keystore"))
+	if err != nil {
+		return nil, err
+	}
+
+	// The keystore may contain several keys so choose the one that matches the certificate. Unencrypted keys
+	// are matched first so that the passphrase is only prompted for if the matching key is encrypted.
+	var encryptedPaths []string
+	for _, keyPath := range keyPaths {
+		keyPEM, err := ioutil.ReadFile(keyPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading private key [%s]", keyPath)
+		}
+		if block, _ := pem.Decode(keyPEM); block != nil && encrypted(block) {
+			encryptedPaths = append(encryptedPaths, keyPath)
+			continue
+		}
+		key, err := decodeKey(keyPEM, keyPath, nil)
+		if err != nil {
+			return nil, err
+		}
+		if matches(cert, key) {
+			return newCredential(cert, key)
+		}
+	}
+
+	if keyPath, ok := encryptedKeyFor(cert, encryptedPaths); ok {
+		keyPEM, err := ioutil.ReadFile(keyPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading private key [%s]", keyPath)
+		}
+		key, err := decodeKey(keyPEM, keyPath, s.passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return newCredential(cert, key)
+	}
+
+	return nil, errors.Errorf("no private key matching the certificate [%s] found in [%s]", certPaths[0], filepath.Join(s.dir, "keystore"))
+}
+
+// encryptedKeyFor returns the encrypted key that should match the certificate without decrypting the keys.
+// Fabric names the files in the keystore after the subject key identifier (the SHA-256 hash of the public key)
+// so the key is chosen by its file name or, if the keystore contains a single encrypted key, that key is chosen.
+func encryptedKeyFor(cert *x509.Certificate, paths []string) (string, bool) {
+	if ski := subjectKeyID(cert); ski != "" {
+		for _, path := range paths {
+			if strings.HasPrefix(strings.ToLower(filepath.Base(path)), ski) {
+				return path, true
+			}
+		}
+	}
+	if len(paths) == 1 {
+		return paths[0], true
+	}
+	return "", false
+}
+
+// subjectKeyID returns the hex-encoded SHA-256 hash of the certificate's public key, as used by Fabric's key store
+func subjectKeyID(cert *x509.Certificate) string {
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return ""
+	}
+	hash := sha256.Sum256(elliptic.Marshal(pub.Curve, pub.X, pub.Y))
+	return hex.EncodeToString(hash[:])
+}
+
+// CheckIssuer returns an error if the certificate wasn't issued by one of the given CA certificates
+// (the root and intermediate certificates of an MSP)
+func (c *Credential) CheckIssuer(caCerts []*x509.Certificate) error {
+	block, _ := pem.Decode(c.Cert)
+	if block == nil {
+		return errors.New("invalid certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "error parsing certificate")
+	}
+
+	for _, caCert := range caCerts {
+		if bytes.Equal(cert.RawIssuer, caCert.RawSubject) && cert.CheckSignatureFrom(caCert) == nil {
+			return nil
+		}
+	}
+	return errors.Errorf("the certificate [%s] was not issued by any of the CAs of the MSP", cert.Subject)
+}
+
+// MSPCACerts returns the root and intermediate CA certificates (cacerts and intermediatecerts) of the
+// directories in MSP layout that match the given pattern (see filepath.Glob)
+func MSPCACerts(pattern string) ([]*x509.Certificate, error) {
+	dirs, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid MSP path [%s]", pattern)
+	}
+
+	var certs []*x509.Certificate
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		for _, sub := range []string{"cacerts", "intermediatecerts"} {
+			paths, err := listFiles(filepath.Join(dir, sub))
+			if err != nil {
+				// The directories are optional
+				continue
+			}
+			for _, path := range paths {
+				cert, err := readCert(path)
+				if err != nil {
+					return nil, err
+				}
+				if !seen[string(cert.Raw)] {
+					seen[string(cert.Raw)] = true
+					certs = append(certs, cert)
+				}
+			}
+		}
+	}
+	return certs, nil
+}
+
+func newCredential(cert *x509.Certificate, key crypto.Signer) (*Credential, error) {
+	if !matches(cert, key) {
+		return nil, errors.New("the private key does not match the certificate")
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding private key")
+	}
+
+	return &Credential{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+	}, nil
+}
+
+func readCert(path string) (*x509.Certificate, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading certificate [%s]", path)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.Errorf("no PEM certificate found in [%s]", path)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing certificate [%s]", path)
+	}
+	return cert, nil
+}
+
+// decodeKey decodes a PEM private key (PKCS#8, SEC 1 or PKCS#1). Encrypted PKCS#8 keys (e.g. written
+// by 'openssl pkcs8 -topk8') and keys encrypted according to RFC 1423 (e.g. by 'openssl ec -aes256')
+// are decrypted with the passphrase.
+func decodeKey(data []byte, path string, passphrase PassphraseFunc) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("no PEM private key found in [%s]", path)
+	}
+
+	der := block.Bytes
+	if encrypted(block) {
+		if passphrase == nil {
+			return nil, errors.Errorf("private key [%s] is encrypted", path)
+		}
+		pwd, err := passphrase(fmt.Sprintf("private key [%s]", path))
+		if err != nil {
+			return nil, err
+		}
+		if block.Type == encryptedPKCS8Type {
+			der, err = decryptPKCS8(block.Bytes, pwd)
+		} else {
+			der, err = x509.DecryptPEMBlock(block, pwd)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error decrypting private key [%s]", path)
+		}
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, errors.Errorf("unsupported private key type in [%s]: %T", path, key)
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, errors.Errorf("unable to parse private key [%s]", path)
+}
+
+func encrypted(block *pem.Block) bool {
+	return block.Type == encryptedPKCS8Type || x509.IsEncryptedPEMBlock(block)
+}
+
+func matches(cert *x509.Certificate, key crypto.Signer) bool {
+	switch pub := key.Public().(type) {
+	case *ecdsa.PublicKey:
+		certPub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+		return ok && certPub.X.Cmp(pub.X) == 0 && certPub.Y.Cmp(pub.Y) == 0
+	case *rsa.PublicKey:
+		certPub, ok := cert.PublicKey.(*rsa.PublicKey)
+		return ok && certPub.N.Cmp(pub.N) == 0 && certPub.E == pub.E
+	default:
+		certDER, err1 := x509.MarshalPKIXPublicKey(cert.PublicKey)
+		keyDER, err2 := x509.MarshalPKIXPublicKey(pub)
+		return err1 == nil && err2 == nil && bytes.Equal(certDER, keyDER)
+	}
+}
+
+func listFiles(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading directory [%s]", dir)
+	}
+
+	var paths []string
+	for _, info := range infos {
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, info.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
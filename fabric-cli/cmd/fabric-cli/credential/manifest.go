@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package credential
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// Manifest lists identities of an organization that are enrolled in the SDK's credential store,
+// e.g. the identities provisioned for a load test
+type Manifest struct {
+	OrgID      string             `json:"orgId"`
+	MSPID      string             `json:"mspId"`
+	Identities []ManifestIdentity `json:"identities"`
+}
+
+// ManifestIdentity is an identity in a manifest
+type ManifestIdentity struct {
+	Name string `json:"name"`
+
+	// Secret is the enrollment secret, if known
+	Secret string `json:"secret,omitempty"`
+}
+
+// Names returns the enrollment IDs of the identities in the manifest
+func (m *Manifest) Names() []string {
+	names := make([]string, len(m.Identities))
+	for i, identity := range m.Identities {
+		names[i] = identity.Name
+	}
+	return names
+}
+
+// Save writes the manifest to the given file. The file is only readable by its owner
+// since the manifest may contain enrollment secrets.
+func (m *Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error marshalling manifest")
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.Wrapf(err, "error writing manifest [%s]", path)
+	}
+	return nil
+}
+
+// LoadManifest reads a manifest from the given file
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading manifest [%s]", path)
+	}
+
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshalling manifest [%s]", path)
+	}
+	if m.OrgID == "" {
+		return nil, errors.Errorf("manifest [%s] doesn't specify an organization", path)
+	}
+	if len(m.Identities) == 0 {
+		return nil, errors.Errorf("manifest [%s] contains no identities", path)
+	}
+	return m, nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package credential
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "identities.json")
+
+	m := &Manifest{
+		OrgID: "org1",
+		MSPID: "Org1MSP",
+		Identities: []ManifestIdentity{
+			{Name: "loaduser1", Secret: "secret1"},
+			{Name: "loaduser2"},
+		},
+	}
+	require.NoError(t, m.Save(path))
+
+	loaded, err := LoadManifest(path)
+	require.NoError(t, err)
+	assert.Equal(t, m, loaded)
+	assert.Equal(t, []string{"loaduser1", "loaduser2"}, loaded.Names())
+
+	require.NoError(t, (&Manifest{OrgID: "org1"}).Save(path))
+	_, err = LoadManifest(path)
+	assert.Error(t, err)
+}
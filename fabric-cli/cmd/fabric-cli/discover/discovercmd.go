@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discover
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/pkg/errors"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the discover command
+func Cmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	discoverCmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Discovery service commands",
+		Long:  "Queries Fabric's discovery service on a peer (the first peer specified by --peer or --orgid) for channel information",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	discoverCmd.AddCommand(getDiscoverConfigCmd(cfg))
+	discoverCmd.AddCommand(getDiscoverPeersCmd(cfg))
+	discoverCmd.AddCommand(getDiscoverEndorsersCmd(cfg))
+
+	return discoverCmd
+}
+
+// parseInvocationChain parses a comma-separated chaincode-to-chaincode invocation chain in which
+// each chaincode may be followed by the collections that it accesses, e.g. cc1:coll1:coll2,cc2
+func parseInvocationChain(s string) ([]*discovery.ChaincodeCall, error) {
+	var chain []*discovery.ChaincodeCall
+	if strings.TrimSpace(s) == "" {
+		return chain, nil
+	}
+
+	for _, c := range strings.Split(s, ",") {
+		parts := strings.Split(c, ":")
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			return nil, errors.Errorf("invalid chaincode call [%s]: expecting ccid[:collection...]", c)
+		}
+
+		call := &discovery.ChaincodeCall{Name: name}
+		for _, coll := range parts[1:] {
+			coll = strings.TrimSpace(coll)
+			if coll == "" {
+				return nil, errors.Errorf("invalid chaincode call [%s]: empty collection name", c)
+			}
+			call.CollectionNames = append(call.CollectionNames, coll)
+		}
+		chain = append(chain, call)
+	}
+	return chain, nil
+}
@@ -0,0 +1,34 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discover
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInvocationChain(t *testing.T) {
+	chain, err := parseInvocationChain("")
+	require.NoError(t, err)
+	assert.Empty(t, chain)
+
+	chain, err = parseInvocationChain("examplecc:coll1:coll2, othercc")
+	require.NoError(t, err)
+	assert.Equal(t, []*discovery.ChaincodeCall{
+		{Name: "examplecc", CollectionNames: []string{"coll1", "coll2"}},
+		{Name: "othercc"},
+	}, chain)
+
+	_, err = parseInvocationChain(":coll1")
+	assert.Error(t, err)
+
+	_, err = parseInvocationChain("examplecc::coll2")
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discover
+
+import (
+	"fmt"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getDiscoverConfigCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	discoverConfigCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Discover channel config",
+		Long:  "Discovers the MSPs and orderers of the channel",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChannelID() == "" {
+				fmt.Printf("\nMust specify channel ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newDiscoverConfigAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing discoverConfigAction: %v", err)
+				return
+			}
+			defer action.Close()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running discoverConfigAction: %v", err)
+			}
+		},
+	}
+
+	flags := discoverConfigCmd.Flags()
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitOrgIDs(flags)
+	return discoverConfigCmd
+}
+
+type discoverConfigAction struct {
+	*fabriccli.Client
+}
+
+func newDiscoverConfigAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*discoverConfigAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &discoverConfigAction{Client: client}, nil
+}
+
+func (a *discoverConfigAction) run() error {
+	config, err := a.DiscoverConfig(a.Config().Context())
+	if err != nil {
+		return err
+	}
+
+	a.Printer().PrintDiscoveredConfig(config)
+
+	return nil
+}
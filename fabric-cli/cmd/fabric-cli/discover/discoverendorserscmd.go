@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discover
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getDiscoverEndorsersCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	discoverEndorsersCmd := &cobra.Command{
+		Use:   "endorsers",
+		Short: "Discover endorsers",
+		Long:  "Discovers the endorsement descriptor of a chaincode (--ccid) or of a chaincode-to-chaincode invocation chain with collections (--invocationchain), i.e. the groups of endorsers and the number of endorsements required from each group",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChannelID() == "" {
+				fmt.Printf("\nMust specify channel ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			if cfg.ChaincodeID() == "" && cfg.InvocationChain() == "" {
+				fmt.Printf("\nMust specify the chaincode ID or the invocation chain\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newDiscoverEndorsersAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing discoverEndorsersAction: %v", err)
+				return
+			}
+			defer action.Close()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running discoverEndorsersAction: %v", err)
+			}
+		},
+	}
+
+	flags := discoverEndorsersCmd.Flags()
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitOrgIDs(flags)
+	cfg.InitChaincodeID(flags)
+	cfg.InitInvocationChain(flags)
+	return discoverEndorsersCmd
+}
+
+type discoverEndorsersAction struct {
+	*fabriccli.Client
+}
+
+func newDiscoverEndorsersAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*discoverEndorsersAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &discoverEndorsersAction{Client: client}, nil
+}
+
+func (a *discoverEndorsersAction) run() error {
+	chain, err := parseInvocationChain(a.Config().InvocationChain())
+	if err != nil {
+		return err
+	}
+	if len(chain) == 0 {
+		chain = []*discovery.ChaincodeCall{{Name: a.Config().ChaincodeID()}}
+	}
+
+	descriptors, err := a.DiscoverEndorsers(a.Config().Context(), chain...)
+	if err != nil {
+		return err
+	}
+
+	a.Printer().PrintEndorsementDescriptors(descriptors)
+
+	return nil
+}
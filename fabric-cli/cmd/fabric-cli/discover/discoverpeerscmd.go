@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discover
+
+import (
+	"fmt"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getDiscoverPeersCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	discoverPeersCmd := &cobra.Command{
+		Use:   "peers",
+		Short: "Discover channel peers",
+		Long:  "Discovers the peers of the channel along with their ledger heights and installed chaincodes. If --invocationchain is specified then only the peers that have all of its chaincodes installed are returned.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChannelID() == "" {
+				fmt.Printf("\nMust specify channel ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newDiscoverPeersAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing discoverPeersAction: %v", err)
+				return
+			}
+			defer action.Close()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running discoverPeersAction: %v", err)
+			}
+		},
+	}
+
+	flags := discoverPeersCmd.Flags()
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitOrgIDs(flags)
+	cfg.InitInvocationChain(flags)
+	return discoverPeersCmd
+}
+
+type discoverPeersAction struct {
+	*fabriccli.Client
+}
+
+func newDiscoverPeersAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*discoverPeersAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &discoverPeersAction{Client: client}, nil
+}
+
+func (a *discoverPeersAction) run() error {
+	chain, err := parseInvocationChain(a.Config().InvocationChain())
+	if err != nil {
+		return err
+	}
+
+	members, err := a.DiscoverPeers(a.Config().Context(), chain...)
+	if err != nil {
+		return err
+	}
+
+	a.Printer().PrintDiscoveredPeers(members)
+
+	return nil
+}
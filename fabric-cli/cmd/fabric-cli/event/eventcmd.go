@@ -7,24 +7,25 @@ SPDX-License-Identifier: Apache-2.0
 package event
 
 import (
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
 	"github.com/spf13/cobra"
 )
 
-var eventCmd = &cobra.Command{
-	Use:   "event",
-	Short: "Event commands",
-	Long:  "Event commands",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.HelpFunc()(cmd, args)
-	},
-}
-
 // Cmd returns the events command
-func Cmd() *cobra.Command {
-	eventCmd.AddCommand(getListenCCCmd())
-	eventCmd.AddCommand(getListenTXCmd())
-	eventCmd.AddCommand(getListenBlockCmd())
-	eventCmd.AddCommand(getListenFilteredBlockCmd())
+func Cmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	eventCmd := &cobra.Command{
+		Use:   "event",
+		Short: "Event commands",
+		Long:  "Event commands",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	eventCmd.AddCommand(getListenCCCmd(cfg))
+	eventCmd.AddCommand(getListenTXCmd(cfg))
+	eventCmd.AddCommand(getListenBlockCmd(cfg))
+	eventCmd.AddCommand(getListenFilteredBlockCmd(cfg))
 
 	return eventCmd
 }
@@ -17,32 +17,32 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var listenBlockCmd = &cobra.Command{
-	Use:   "listenblock",
-	Short: "Listen to block events.",
-	Long:  "Listen to block events",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newlistenBlockAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing listenBlockAction: %v", err)
-			return
-		}
+func getListenBlockCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	listenBlockCmd := &cobra.Command{
+		Use:   "listenblock",
+		Short: "Listen to block events.",
+		Long:  "Listen to block events",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newlistenBlockAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing listenBlockAction: %v", err)
+				return
+			}
 
-		defer action.Terminate()
+			defer action.Terminate()
 
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running listenBlockAction: %v", err)
-		}
-	},
-}
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running listenBlockAction: %v", err)
+			}
+		},
+	}
 
-func getListenBlockCmd() *cobra.Command {
 	flags := listenBlockCmd.Flags()
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitPeerURL(flags, "", "The URL of the peer on which to listen for events, e.g. localhost:7051")
-	cliconfig.InitSeekType(flags)
-	cliconfig.InitBlockNum(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags, "", "The URL of the peer on which to listen for events, e.g. localhost:7051")
+	cfg.InitSeekType(flags)
+	cfg.InitBlockNum(flags)
 	return listenBlockCmd
 }
 
@@ -51,14 +51,14 @@ type listenBlockAction struct {
 	inputEvent
 }
 
-func newlistenBlockAction(flags *pflag.FlagSet) (*listenBlockAction, error) {
+func newlistenBlockAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*listenBlockAction, error) {
 	action := &listenBlockAction{inputEvent: inputEvent{done: make(chan bool)}}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
 func (a *listenBlockAction) invoke() error {
-	eventClient, err := a.EventClient(event.WithBlockEvents(), event.WithSeekType(cliconfig.Config().SeekType()), event.WithBlockNum(cliconfig.Config().BlockNum()))
+	eventClient, err := a.EventClient(event.WithBlockEvents(), event.WithSeekType(a.Config().SeekType()), event.WithBlockNum(a.Config().BlockNum()))
 	if err != nil {
 		return err
 	}
@@ -76,6 +76,8 @@ func (a *listenBlockAction) invoke() error {
 		select {
 		case _, _ = <-enterch:
 			return nil
+		case <-a.Config().Context().Done():
+			return nil
 		case event, ok := <-beventch:
 			if !ok {
 				return errors.WithMessage(err, "unexpected closed channel while waiting for block event")
@@ -16,43 +16,43 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var listenccCmd = &cobra.Command{
-	Use:   "listencc",
-	Short: "Listen to chaincode events.",
-	Long:  "Listen to chaincode events",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cliconfig.Config().ChaincodeID() == "" {
-			fmt.Printf("\nMust specify the chaincode ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		if cliconfig.Config().ChaincodeEvent() == "" {
-			fmt.Printf("\nMust specify the event name\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
+func getListenCCCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	listenccCmd := &cobra.Command{
+		Use:   "listencc",
+		Short: "Listen to chaincode events.",
+		Long:  "Listen to chaincode events",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChaincodeID() == "" {
+				fmt.Printf("\nMust specify the chaincode ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			if cfg.ChaincodeEvent() == "" {
+				fmt.Printf("\nMust specify the event name\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
 
-		action, err := newListenCCAction(cmd.Flags())
-		if err != nil {
-			fmt.Printf("\nError while initializing listenCCAction: %v\n", err)
-			return
-		}
+			action, err := newListenCCAction(cfg, cmd.Flags())
+			if err != nil {
+				fmt.Printf("\nError while initializing listenCCAction: %v\n", err)
+				return
+			}
 
-		defer action.Terminate()
+			defer action.Terminate()
 
-		err = action.invoke()
-		if err != nil {
-			fmt.Printf("\nError while running listenCCAction: %v\n", err)
-		}
-	},
-}
+			err = action.invoke()
+			if err != nil {
+				fmt.Printf("\nError while running listenCCAction: %v\n", err)
+			}
+		},
+	}
 
-func getListenCCCmd() *cobra.Command {
 	flags := listenccCmd.Flags()
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitPeerURL(flags, "", "The URL of the peer on which to listen for events, e.g. grpcs://localhost:7051")
-	cliconfig.InitChaincodeID(flags)
-	cliconfig.InitChaincodeEvent(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags, "", "The URL of the peer on which to listen for events, e.g. grpcs://localhost:7051")
+	cfg.InitChaincodeID(flags)
+	cfg.InitChaincodeEvent(flags)
 	return listenccCmd
 }
 
@@ -61,22 +61,22 @@ type listenccAction struct {
 	inputEvent
 }
 
-func newListenCCAction(flags *pflag.FlagSet) (*listenccAction, error) {
+func newListenCCAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*listenccAction, error) {
 	action := &listenccAction{inputEvent: inputEvent{done: make(chan bool)}}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
 func (a *listenccAction) invoke() error {
 
-	fmt.Printf("Registering CC event on chaincode [%s] and event [%s]\n", cliconfig.Config().ChaincodeID(), cliconfig.Config().ChaincodeEvent())
+	fmt.Printf("Registering CC event on chaincode [%s] and event [%s]\n", a.Config().ChaincodeID(), a.Config().ChaincodeEvent())
 
 	eventHub, err := a.EventClient()
 	if err != nil {
 		return err
 	}
 
-	breg, beventch, err := eventHub.RegisterChaincodeEvent(cliconfig.Config().ChaincodeID(), cliconfig.Config().ChaincodeEvent())
+	breg, beventch, err := eventHub.RegisterChaincodeEvent(a.Config().ChaincodeID(), a.Config().ChaincodeEvent())
 	if err != nil {
 		return errors.WithMessage(err, "Error registering for block events")
 	}
@@ -87,6 +87,8 @@ func (a *listenccAction) invoke() error {
 		select {
 		case _, _ = <-enterch:
 			return nil
+		case <-a.Config().Context().Done():
+			return nil
 		case event, ok := <-beventch:
 			if !ok {
 				return errors.WithMessage(err, "unexpected closed channel while waiting for block event")
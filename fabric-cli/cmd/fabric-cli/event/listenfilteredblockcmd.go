@@ -17,32 +17,32 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var listenFilteredBlockCmd = &cobra.Command{
-	Use:   "listenfilteredblock",
-	Short: "Listen to filtered block events.",
-	Long:  "Listen to filtered block events",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newlistenFilteredBlockAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing listenFilteredBlockAction: %v", err)
-			return
-		}
+func getListenFilteredBlockCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	listenFilteredBlockCmd := &cobra.Command{
+		Use:   "listenfilteredblock",
+		Short: "Listen to filtered block events.",
+		Long:  "Listen to filtered block events",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newlistenFilteredBlockAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing listenFilteredBlockAction: %v", err)
+				return
+			}
 
-		defer action.Terminate()
+			defer action.Terminate()
 
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running listenFilteredBlockAction: %v", err)
-		}
-	},
-}
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running listenFilteredBlockAction: %v", err)
+			}
+		},
+	}
 
-func getListenFilteredBlockCmd() *cobra.Command {
 	flags := listenFilteredBlockCmd.Flags()
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitPeerURL(flags, "", "The URL of the peer on which to listen for events, e.g. localhost:7051")
-	cliconfig.InitSeekType(flags)
-	cliconfig.InitBlockNum(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags, "", "The URL of the peer on which to listen for events, e.g. localhost:7051")
+	cfg.InitSeekType(flags)
+	cfg.InitBlockNum(flags)
 	return listenFilteredBlockCmd
 }
 
@@ -51,14 +51,14 @@ type listenFilteredBlockAction struct {
 	inputEvent
 }
 
-func newlistenFilteredBlockAction(flags *pflag.FlagSet) (*listenFilteredBlockAction, error) {
+func newlistenFilteredBlockAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*listenFilteredBlockAction, error) {
 	action := &listenFilteredBlockAction{inputEvent: inputEvent{done: make(chan bool)}}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
 func (a *listenFilteredBlockAction) invoke() error {
-	eventClient, err := a.EventClient(event.WithSeekType(cliconfig.Config().SeekType()), event.WithBlockNum(cliconfig.Config().BlockNum()))
+	eventClient, err := a.EventClient(event.WithSeekType(a.Config().SeekType()), event.WithBlockNum(a.Config().BlockNum()))
 	if err != nil {
 		return err
 	}
@@ -76,6 +76,8 @@ func (a *listenFilteredBlockAction) invoke() error {
 		select {
 		case _, _ = <-enterch:
 			return nil
+		case <-a.Config().Context().Done():
+			return nil
 		case event, ok := <-beventch:
 			if !ok {
 				return errors.WithMessage(err, "unexpected closed channel while waiting for filtered block event")
@@ -16,36 +16,36 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var listenTxCmd = &cobra.Command{
-	Use:   "listentx",
-	Short: "Listen to transaction events.",
-	Long:  "Listen to transaction events",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cliconfig.Config().TxID() == "" {
-			fmt.Printf("\nMust specify the transaction ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		action, err := newListenTXAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing listenTxAction: %v", err)
-			return
-		}
-
-		defer action.Terminate()
-
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running listenTxAction: %v", err)
-		}
-	},
-}
+func getListenTXCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	listenTxCmd := &cobra.Command{
+		Use:   "listentx",
+		Short: "Listen to transaction events.",
+		Long:  "Listen to transaction events",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.TxID() == "" {
+				fmt.Printf("\nMust specify the transaction ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			action, err := newListenTXAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing listenTxAction: %v", err)
+				return
+			}
+
+			defer action.Terminate()
+
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running listenTxAction: %v", err)
+			}
+		},
+	}
 
-func getListenTXCmd() *cobra.Command {
 	flags := listenTxCmd.Flags()
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitTxID(flags)
-	cliconfig.InitPeerURL(flags, "", "The URL of the peer on which to listen for events, e.g. grpcs://localhost:7051")
+	cfg.InitChannelID(flags)
+	cfg.InitTxID(flags)
+	cfg.InitPeerURL(flags, "", "The URL of the peer on which to listen for events, e.g. grpcs://localhost:7051")
 	return listenTxCmd
 }
 
@@ -54,9 +54,9 @@ type listentxAction struct {
 	inputEvent
 }
 
-func newListenTXAction(flags *pflag.FlagSet) (*listentxAction, error) {
+func newListenTXAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*listentxAction, error) {
 	action := &listentxAction{inputEvent: inputEvent{done: make(chan bool)}}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
@@ -67,9 +67,9 @@ func (a *listentxAction) invoke() error {
 		return err
 	}
 
-	fmt.Printf("Registering TX event for TxID [%s]\n", cliconfig.Config().TxID())
+	fmt.Printf("Registering TX event for TxID [%s]\n", a.Config().TxID())
 
-	reg, eventch, err := eventHub.RegisterTxStatusEvent(cliconfig.Config().TxID())
+	reg, eventch, err := eventHub.RegisterTxStatusEvent(a.Config().TxID())
 	if err != nil {
 		return errors.WithMessage(err, "Error registering for block events")
 	}
@@ -81,6 +81,8 @@ func (a *listentxAction) invoke() error {
 	select {
 	case _, _ = <-enterch:
 		return nil
+	case <-a.Config().Context().Done():
+		return nil
 	case event, ok := <-eventch:
 		if !ok {
 			return errors.WithMessage(err, "unexpected closed channel while waiting for tx status event")
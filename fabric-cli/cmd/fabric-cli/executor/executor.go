@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package executor
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sync"
@@ -35,21 +36,28 @@ const (
 // the task is queue until one becomes available. The Executor is useful for throttling requests in order
 // not to overload the application.
 type Executor struct {
+	config      *cliconfig.CLIConfig
 	name        string
 	state       State
-	tasks       chan worker.Task
+	tasks       chan request
 	terminating chan bool
 	pool        *worker.Pool
 	wg          sync.WaitGroup
 }
 
+type request struct {
+	ctx  context.Context
+	task worker.Task
+}
+
 // NewConcurrent creates a new, concurrent executor with the given concurrency.
 // As tasks are submitted they are queued while waiting for a worker for execution.
 //
+// - config: The CLI configuration
 // - name: The name of the executor (useful for debugging)
 // - concurrency: The concurrency, i.e. the number of workers executing concurrently
-func NewConcurrent(name string, concurrency uint16) *Executor {
-	return New(name, math.MaxInt16, worker.NewPool(name, concurrency))
+func NewConcurrent(config *cliconfig.CLIConfig, name string, concurrency uint16) *Executor {
+	return New(config, name, math.MaxInt16, worker.NewPool(config, name, concurrency))
 }
 
 // NewBoundedConcurrent creates a new, concurrent executor with the given concurrency
@@ -57,25 +65,28 @@ func NewConcurrent(name string, concurrency uint16) *Executor {
 // number of queued tasks reaches the given queue length, the Submit operation will block until a worker becomes
 // available.
 //
+// - config: The CLI configuration
 // - name: The name of the executor (useful for debugging)
 // - concurrency: The concurrency, i.e. the number of workers executing concurrently
 // - queueLength: The maximum number of tasks allowed to be queued while waiting for a worker
-func NewBoundedConcurrent(name string, concurrency uint16, queueLength uint16) *Executor {
-	return New(name, queueLength, worker.NewPool(name, concurrency))
+func NewBoundedConcurrent(config *cliconfig.CLIConfig, name string, concurrency uint16, queueLength uint16) *Executor {
+	return New(config, name, queueLength, worker.NewPool(config, name, concurrency))
 }
 
 // New creates a new, multi-threaded executor with the given options:
+// - config: The CLI configuration
 // - name: The name of the executor (useful for debugging)
 // - queueLength: The maximum number of tasks allowed to be queued while waiting for a worker
 // 		If queueSize == concurrency then the Submit() function will block until a worker becomes free,
 //		otherwise the task will be added to the queue and Submit() will not block.
 // pool - The worker pool
-func New(name string, queueLength uint16, pool *worker.Pool) *Executor {
+func New(config *cliconfig.CLIConfig, name string, queueLength uint16, pool *worker.Pool) *Executor {
 	return &Executor{
+		config:      config,
 		name:        name,
 		state:       NEW,
 		terminating: make(chan bool),
-		tasks:       make(chan worker.Task, queueLength),
+		tasks:       make(chan request, queueLength),
 		pool:        pool,
 	}
 }
@@ -97,35 +108,50 @@ func (e *Executor) Start() bool {
 	return true
 }
 
-// Submit submits a new task
-func (e *Executor) Submit(task worker.Task) error {
+// Submit submits a new task. The given context is passed to the task when it's invoked, so a task that
+// is still queued when the context is cancelled returns without doing any work. If the context is cancelled
+// while waiting for room in the queue then the task isn't submitted and the context's error is returned.
+func (e *Executor) Submit(ctx context.Context, task worker.Task) error {
 	if e.state != STARTED {
 		return fmt.Errorf("executor [%s] is not started", e.name)
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Submit the task to the dispatcher
-	cliconfig.Config().Logger().Debugf("Submit[%s] - submitting task...\n", e.name)
+	e.config.Logger().Debugf("Submit[%s] - submitting task...\n", e.name)
 	e.wg.Add(1)
-	e.tasks <- task
-	cliconfig.Config().Logger().Debugf("...Submit[%s] - submitted task\n", e.name)
+	select {
+	case e.tasks <- request{ctx: ctx, task: task}:
+	case <-ctx.Done():
+		e.wg.Done()
+		return ctx.Err()
+	}
+	e.config.Logger().Debugf("...Submit[%s] - submitted task\n", e.name)
 
 	return nil
 }
 
-// SubmitDelayed submits a new task in the future
-func (e *Executor) SubmitDelayed(task worker.Task, delay time.Duration) error {
+// SubmitDelayed submits a new task in the future. The task is not submitted if the context
+// is cancelled before the delay expires.
+func (e *Executor) SubmitDelayed(ctx context.Context, task worker.Task, delay time.Duration) error {
 	if e.state != STARTED {
 		return fmt.Errorf("executor [%s] is not started", e.name)
 	}
 
-	cliconfig.Config().Logger().Debugf("Submit[%s] - submitting task to execute in %s ...\n", e.name, delay)
+	e.config.Logger().Debugf("Submit[%s] - submitting task to execute in %s ...\n", e.name, delay)
 
 	e.wg.Add(1)
 
 	go func() {
-		<-time.After(delay)
-		e.Submit(task)
-		e.wg.Done()
+		defer e.wg.Done()
+		select {
+		case <-time.After(delay):
+			e.Submit(ctx, task)
+		case <-ctx.Done():
+			e.config.Logger().Debugf("Submit[%s] - delayed task cancelled: %s\n", e.name, ctx.Err())
+		}
 	}()
 
 	return nil
@@ -146,22 +172,22 @@ func (e *Executor) Stop(wait bool) bool {
 
 	e.state = TERMINATED
 
-	cliconfig.Config().Logger().Debugf("[%s] Stopping executor ...\n", e.name)
+	e.config.Logger().Debugf("[%s] Stopping executor ...\n", e.name)
 
 	// Wait for the dispatcher to purge its queue
 	e.wg.Wait()
 
-	cliconfig.Config().Logger().Debugf("[%s] Stopping the dispatcher ...\n", e.name)
+	e.config.Logger().Debugf("[%s] Stopping the dispatcher ...\n", e.name)
 
 	// Stop the dispatcher
 	e.terminating <- true
 
-	cliconfig.Config().Logger().Debugf("[%s] Stopping the worker pool ...\n", e.name)
+	e.config.Logger().Debugf("[%s] Stopping the worker pool ...\n", e.name)
 
 	// Stop the worker pool
 	e.pool.Stop(wait)
 
-	cliconfig.Config().Logger().Debugf("[%s] ... executor stopped.\n", e.name)
+	e.config.Logger().Debugf("[%s] ... executor stopped.\n", e.name)
 
 	return true
 }
@@ -170,12 +196,12 @@ func (e *Executor) dispatch() {
 	for {
 		select {
 		// Wait for a task
-		case task := <-e.tasks:
-			e.pool.Submit(task)
+		case req := <-e.tasks:
+			e.pool.Submit(req.ctx, req.task)
 			e.wg.Done()
 
 		case <-e.terminating:
-			cliconfig.Config().Logger().Debugf("[%s] ... executor dispatcher ended\n", e.name)
+			e.config.Logger().Debugf("[%s] ... executor dispatcher ended\n", e.name)
 			return
 		}
 	}
@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package executor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTask struct {
+	started *int32
+	running chan struct{}
+	block   chan struct{}
+	wg      *sync.WaitGroup
+}
+
+func (t *testTask) Invoke(ctx context.Context) {
+	defer t.wg.Done()
+	if ctx.Err() != nil {
+		return
+	}
+	atomic.AddInt32(t.started, 1)
+	if t.running != nil {
+		close(t.running)
+	}
+	if t.block != nil {
+		<-t.block
+	}
+}
+
+func TestExecutorCancel(t *testing.T) {
+	e := NewConcurrent(cliconfig.New(), "test", 1)
+	require.True(t, e.Start())
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var started int32
+	var wg sync.WaitGroup
+	running := make(chan struct{})
+	block := make(chan struct{})
+
+	// The first task occupies the only worker until it's unblocked
+	wg.Add(1)
+	require.NoError(t, e.Submit(ctx, &testTask{started: &started, running: running, block: block, wg: &wg}))
+	<-running
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		require.NoError(t, e.Submit(ctx, &testTask{started: &started, wg: &wg}))
+	}
+
+	cancel()
+	close(block)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&started), "queued tasks should not be started after the context is cancelled")
+	assert.Equal(t, context.Canceled, e.Submit(ctx, &testTask{started: &started, wg: &wg}))
+	assert.True(t, e.Stop(true))
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package worker
 
 import (
+	"context"
+
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
 )
 
@@ -21,9 +23,10 @@ const (
 	STOPPED
 )
 
-// Task is the task that the Worker invokes
+// Task is the task that the Worker invokes. The context is the one with which the task was
+// submitted. If it's done by the time the task is invoked then the task should return immediately.
 type Task interface {
-	Invoke()
+	Invoke(ctx context.Context)
 }
 
 // Events receives event notifications from the worker
@@ -40,17 +43,24 @@ type Events interface {
 
 // Worker invokes a Task
 type Worker struct {
+	config *cliconfig.CLIConfig
 	name   string
 	events Events
-	task   chan Task
+	task   chan request
 	done   chan bool
 }
 
-func newWorker(name string, events Events) *Worker {
+type request struct {
+	ctx  context.Context
+	task Task
+}
+
+func newWorker(config *cliconfig.CLIConfig, name string, events Events) *Worker {
 	return &Worker{
+		config: config,
 		name:   name,
 		events: events,
-		task:   make(chan Task),
+		task:   make(chan request),
 		done:   make(chan bool),
 	}
 }
@@ -61,36 +71,36 @@ func (w *Worker) Name() string {
 }
 
 // Submit submits a task
-func (w *Worker) Submit(task Task) {
-	w.task <- task
+func (w *Worker) Submit(ctx context.Context, task Task) {
+	w.task <- request{ctx: ctx, task: task}
 }
 
-func (w *Worker) invoke(task Task) {
-	cliconfig.Config().Logger().Debugf("Worker[%s].invoke ...\n", w.name)
+func (w *Worker) invoke(ctx context.Context, task Task) {
+	w.config.Logger().Debugf("Worker[%s].invoke ...\n", w.name)
 	defer w.events.TaskCompleted(w, task)
 
 	w.events.TaskStarted(w, task)
-	task.Invoke()
-	cliconfig.Config().Logger().Debugf("Worker[%s].invoke done.\n", w.name)
+	task.Invoke(ctx)
+	w.config.Logger().Debugf("Worker[%s].invoke done.\n", w.name)
 }
 
 // Start starts the worker
 func (w *Worker) Start() {
-	cliconfig.Config().Logger().Debugf("Worker[%s] starting...\n", w.name)
+	w.config.Logger().Debugf("Worker[%s] starting...\n", w.name)
 	go func() {
 		for {
-			cliconfig.Config().Logger().Debugf("Worker[%s] waiting for task...\n", w.name)
+			w.config.Logger().Debugf("Worker[%s] waiting for task...\n", w.name)
 
 			// Inform the events that I'm available
 			w.events.StateChange(w, READY)
 
 			select {
-			case task := <-w.task:
-				w.invoke(task)
+			case req := <-w.task:
+				w.invoke(req.ctx, req.task)
 
 			case <-w.done:
 				w.events.StateChange(w, STOPPED)
-				cliconfig.Config().Logger().Debugf("Worker[%s] stopped\n", w.name)
+				w.config.Logger().Debugf("Worker[%s] stopped\n", w.name)
 				return
 			}
 		}
@@ -99,6 +109,6 @@ func (w *Worker) Start() {
 
 // Stop stops the worker
 func (w *Worker) Stop() {
-	cliconfig.Config().Logger().Debugf("Worker[%s] stopping...\n", w.name)
+	w.config.Logger().Debugf("Worker[%s] stopping...\n", w.name)
 	w.done <- true
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package worker
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -15,6 +16,7 @@ import (
 
 // Pool contains a pool of workers that can each execute a Task
 type Pool struct {
+	config          *cliconfig.CLIConfig
 	name            string
 	workers         []*Worker
 	availableWorker chan *Worker
@@ -23,8 +25,9 @@ type Pool struct {
 }
 
 // NewPool creates a worker Pool with the given Factory
-func NewPool(name string, concurrency uint16) *Pool {
+func NewPool(config *cliconfig.CLIConfig, name string, concurrency uint16) *Pool {
 	pool := &Pool{
+		config:          config,
 		name:            name,
 		availableWorker: make(chan *Worker, concurrency),
 		workers:         make([]*Worker, concurrency),
@@ -32,7 +35,7 @@ func NewPool(name string, concurrency uint16) *Pool {
 
 	// Create the workers
 	for i := 0; i < int(concurrency); i++ {
-		pool.workers[i] = newWorker(fmt.Sprintf("%s-%d", name, i), pool)
+		pool.workers[i] = newWorker(config, fmt.Sprintf("%s-%d", name, i), pool)
 	}
 
 	return pool
@@ -55,18 +58,18 @@ func (p *Pool) Start() {
 
 // Stop stops the pool and optionally waits until all tasks have completed
 func (p *Pool) Stop(wait bool) {
-	cliconfig.Config().Logger().Debugf("[%s] Stopping worker pool ...\n", p.name)
+	p.config.Logger().Debugf("[%s] Stopping worker pool ...\n", p.name)
 
 	if wait {
 		// Wait for all the tasks to complete
-		cliconfig.Config().Logger().Debugf("[%s] ... waiting for tasks to complete ...\n", p.name)
+		p.config.Logger().Debugf("[%s] ... waiting for tasks to complete ...\n", p.name)
 		p.taskWg.Wait()
 	} else {
-		cliconfig.Config().Logger().Debugf("[%s] ... forcing all tasks to stop ...\n", p.name)
+		p.config.Logger().Debugf("[%s] ... forcing all tasks to stop ...\n", p.name)
 	}
 
 	// Shut down the workers
-	cliconfig.Config().Logger().Debugf("[%s] ... stopping workers ...\n", p.name)
+	p.config.Logger().Debugf("[%s] ... stopping workers ...\n", p.name)
 	for i := 0; i < len(p.workers); i++ {
 		w := <-p.availableWorker
 		w.Stop()
@@ -76,21 +79,21 @@ func (p *Pool) Stop(wait bool) {
 	p.wg.Wait()
 }
 
-// Submit submits a Task for execution
-func (p *Pool) Submit(task Task) {
-	cliconfig.Config().Logger().Debugf("worker pool.Submit[%s] - waiting for available worker\n", p.name)
+// Submit submits a Task for execution. The given context is passed to the task when it's invoked.
+func (p *Pool) Submit(ctx context.Context, task Task) {
+	p.config.Logger().Debugf("worker pool.Submit[%s] - waiting for available worker\n", p.name)
 
 	p.taskWg.Add(1)
 
 	// Wait for an available worker
 	w := <-p.availableWorker
 
-	cliconfig.Config().Logger().Debugf("worker pool.Submit[%s] - got worker [%s]. Submitting task...\n", p.name, w.Name())
+	p.config.Logger().Debugf("worker pool.Submit[%s] - got worker [%s]. Submitting task...\n", p.name, w.Name())
 
 	// Submit the task to the worker
-	w.Submit(task)
+	w.Submit(ctx, task)
 
-	cliconfig.Config().Logger().Debugf("worker pool.Submit[%s] - submitted task to worker[%s]\n", p.name, w.Name())
+	p.config.Logger().Debugf("worker pool.Submit[%s] - submitted task to worker[%s]\n", p.name, w.Name())
 }
 
 // StateChange is invoked when the state of the Worker changes
@@ -101,12 +104,12 @@ func (p *Pool) StateChange(w *Worker, state State) {
 		break
 
 	case STOPPED:
-		cliconfig.Config().Logger().Debugf("...Worker[%s] stopped\n", w.Name())
+		p.config.Logger().Debugf("...Worker[%s] stopped\n", w.Name())
 		p.wg.Done()
 		break
 
 	default:
-		cliconfig.Config().Logger().Warnf("Unsupported worker state: %d\n", state)
+		p.config.Logger().Warnf("Unsupported worker state: %d\n", state)
 		break
 	}
 }
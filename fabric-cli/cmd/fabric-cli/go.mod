@@ -6,7 +6,8 @@ module github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli
 
 require (
 	github.com/cloudflare/cfssl v0.0.0-20180323000720-5d63dbd981b5 // indirect
-	github.com/golang/protobuf v1.3.2
+	github.com/fxamacker/cbor/v2 v2.5.0
+	github.com/golang/protobuf v1.5.4
 	github.com/hyperledger/fabric-protos-go v0.0.0-20191121202242-f5500d5e3e85
 	github.com/hyperledger/fabric-sdk-go v1.0.0-beta1.0.20200106161850-8f3d32c9d1a6
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
@@ -19,7 +20,11 @@ require (
 	github.com/spf13/afero v1.1.1 // indirect
 	github.com/spf13/cobra v0.0.3
 	github.com/spf13/pflag v1.0.1
-	github.com/stretchr/testify v1.3.0
+	github.com/stretchr/testify v1.10.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+	golang.org/x/crypto v0.0.0-20190308221718-c2843e01d9a2
+	google.golang.org/protobuf v1.33.0
+	gopkg.in/yaml.v2 v2.2.1
 )
 
 go 1.13
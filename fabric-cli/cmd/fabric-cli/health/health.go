@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package health checks the health of peers so that peers which are down may be excluded from
+// the targets of requests. Peers that are marked down are checked periodically and are brought
+// back once they recover. The package also compares the ledger heights of peers to detect peers
+// that are behind or that have diverged.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+)
+
+// Checker checks the health of a peer. An error is returned if the peer is unhealthy.
+type Checker func(ctx context.Context, peer fab.Peer) error
+
+// Status is the result of the last health check of a peer
+type Status struct {
+	URL       string
+	MSPID     string
+	Healthy   bool
+	Latency   time.Duration
+	CheckedAt time.Time
+	Err       error
+}
+
+// Monitor maintains the health status of a set of peers
+type Monitor struct {
+	config   *cliconfig.CLIConfig
+	peers    []fab.Peer
+	checker  Checker
+	interval time.Duration
+	timeout  time.Duration
+	mutex    sync.RWMutex
+	status   map[string]*Status
+	stop     chan struct{}
+	stopped  sync.WaitGroup
+}
+
+// New returns a new health monitor for the given peers.
+//
+// - config: The CLI configuration
+// - peers: The peers to monitor
+// - checker: Checks the health of a single peer
+// - interval: The interval between checks when the monitor is started
+// - timeout: The maximum time allowed for a single check
+func New(config *cliconfig.CLIConfig, peers []fab.Peer, checker Checker, interval, timeout time.Duration) *Monitor {
+	return &Monitor{
+		config:   config,
+		peers:    peers,
+		checker:  checker,
+		interval: interval,
+		timeout:  timeout,
+		status:   make(map[string]*Status),
+	}
+}
+
+// Start checks all of the peers and then keeps checking them in the background at the monitor's
+// interval until Stop is called or the context of the configuration is done
+func (m *Monitor) Start() {
+	m.CheckAll(m.config.Context())
+
+	m.stop = make(chan struct{})
+	m.stopped.Add(1)
+	go func() {
+		defer m.stopped.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.CheckAll(m.config.Context())
+			case <-m.stop:
+				return
+			case <-m.config.Context().Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks
+func (m *Monitor) Stop() {
+	if m.stop == nil {
+		return
+	}
+	close(m.stop)
+	m.stopped.Wait()
+	m.stop = nil
+}
+
+// CheckAll checks all of the peers concurrently and returns their status
+func (m *Monitor) CheckAll(ctx context.Context) []*Status {
+	var wg sync.WaitGroup
+	for _, peer := range m.peers {
+		wg.Add(1)
+		go func(peer fab.Peer) {
+			defer wg.Done()
+			m.check(ctx, peer)
+		}(peer)
+	}
+	wg.Wait()
+
+	return m.Status()
+}
+
+// Status returns the status of each peer in the order in which the peers were given.
+// Peers that haven't been checked yet are not included.
+func (m *Monitor) Status() []*Status {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var statuses []*Status
+	for _, peer := range m.peers {
+		if s, ok := m.status[peer.URL()]; ok {
+			status := *s
+			statuses = append(statuses, &status)
+		}
+	}
+	return statuses
+}
+
+// Healthy returns the given peers excluding those that are marked down. Peers that haven't been
+// checked are assumed to be healthy. If all of the peers are down then all of them are returned
+// since excluding every target would change the semantics of the request.
+func (m *Monitor) Healthy(peers []fab.Peer) []fab.Peer {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var healthy []fab.Peer
+	for _, peer := range peers {
+		if s, ok := m.status[peer.URL()]; !ok || s.Healthy {
+			healthy = append(healthy, peer)
+		}
+	}
+
+	if len(healthy) == 0 {
+		m.config.Logger().Warnf("All target peers are down. Sending requests to all of them.\n")
+		return peers
+	}
+	return healthy
+}
+
+func (m *Monitor) check(ctx context.Context, peer fab.Peer) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := m.checker(reqCtx, peer)
+	status := &Status{
+		URL:       peer.URL(),
+		MSPID:     peer.MSPID(),
+		Healthy:   err == nil,
+		Latency:   time.Since(start),
+		CheckedAt: time.Now(),
+		Err:       err,
+	}
+
+	if err != nil && ctx.Err() != nil {
+		// The check was interrupted so it says nothing about the peer
+		return
+	}
+
+	m.mutex.Lock()
+	previous, checked := m.status[peer.URL()]
+	m.status[peer.URL()] = status
+	m.mutex.Unlock()
+
+	switch {
+	case !status.Healthy && (!checked || previous.Healthy):
+		m.config.Logger().Warnf("Peer [%s] is down and is excluded from the targets: %s\n", peer.URL(), err)
+	case status.Healthy && checked && !previous.Healthy:
+		m.config.Logger().Infof("Peer [%s] has recovered\n", peer.URL())
+	}
+}
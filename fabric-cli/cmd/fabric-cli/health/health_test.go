@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor(t *testing.T) {
+	peer1 := mocks.NewMockPeer("peer1", "peer1:7051")
+	peer2 := mocks.NewMockPeer("peer2", "peer2:7051")
+	peers := []fab.Peer{peer1, peer2}
+
+	var mutex sync.Mutex
+	down := map[string]bool{"peer2:7051": true}
+	checker := func(ctx context.Context, peer fab.Peer) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if down[peer.URL()] {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	m := New(cliconfig.New(), peers, checker, 10*time.Millisecond, time.Second)
+
+	// Peers that haven't been checked are assumed to be healthy
+	assert.Equal(t, peers, m.Healthy(peers))
+
+	statuses := m.CheckAll(context.Background())
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Healthy)
+	assert.False(t, statuses[1].Healthy)
+	assert.Error(t, statuses[1].Err)
+	assert.Equal(t, []fab.Peer{peer1}, m.Healthy(peers))
+
+	// All peers are returned if all of them are down
+	assert.Equal(t, []fab.Peer{peer2}, m.Healthy([]fab.Peer{peer2}))
+
+	m.Start()
+	defer m.Stop()
+
+	mutex.Lock()
+	down["peer2:7051"] = false
+	mutex.Unlock()
+
+	deadline := time.Now().Add(time.Second)
+	for len(m.Healthy(peers)) != 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, peers, m.Healthy(peers))
+}
@@ -0,0 +1,75 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package health
+
+import (
+	"bytes"
+	"sort"
+)
+
+// Height is the blockchain info of a peer on a channel
+type Height struct {
+	URL               string
+	MSPID             string
+	Height            uint64
+	CurrentBlockHash  []byte
+	PreviousBlockHash []byte
+	Err               error
+
+	// Lag is the number of blocks by which the peer is behind the highest peer
+	Lag uint64
+
+	// HashMismatch is true if another peer at the same height has a different current block hash
+	HashMismatch bool
+}
+
+// InSync returns true if the peer responded, isn't behind and agrees with the other peers at its height
+func (h *Height) InSync() bool {
+	return h.Err == nil && h.Lag == 0 && !h.HashMismatch
+}
+
+// CompareHeights sets the lag and hash mismatch of each of the given heights and sorts the
+// heights by MSP ID and URL. Heights with an error aren't compared.
+func CompareHeights(heights []*Height) {
+	var max uint64
+	hashesAtHeight := make(map[uint64][][]byte)
+	for _, h := range heights {
+		if h.Err != nil {
+			continue
+		}
+		if h.Height > max {
+			max = h.Height
+		}
+		if !containsHash(hashesAtHeight[h.Height], h.CurrentBlockHash) {
+			hashesAtHeight[h.Height] = append(hashesAtHeight[h.Height], h.CurrentBlockHash)
+		}
+	}
+
+	for _, h := range heights {
+		if h.Err != nil {
+			continue
+		}
+		h.Lag = max - h.Height
+		h.HashMismatch = len(hashesAtHeight[h.Height]) > 1
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		if heights[i].MSPID != heights[j].MSPID {
+			return heights[i].MSPID < heights[j].MSPID
+		}
+		return heights[i].URL < heights[j].URL
+	})
+}
+
+func containsHash(hashes [][]byte, hash []byte) bool {
+	for _, h := range hashes {
+		if bytes.Equal(h, hash) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package health
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareHeights(t *testing.T) {
+	p1 := &Height{URL: "peer1.org1:7051", MSPID: "Org1MSP", Height: 10, CurrentBlockHash: []byte("a")}
+	p0 := &Height{URL: "peer0.org1:7051", MSPID: "Org1MSP", Height: 8, CurrentBlockHash: []byte("b")}
+	p2 := &Height{URL: "peer0.org2:7051", MSPID: "Org2MSP", Height: 10, CurrentBlockHash: []byte("c")}
+	p3 := &Height{URL: "peer1.org2:7051", MSPID: "Org2MSP", Err: errors.New("unavailable")}
+	p4 := &Height{URL: "peer0.org3:7051", MSPID: "Org3MSP", Height: 8, CurrentBlockHash: []byte("b")}
+
+	heights := []*Height{p1, p0, p2, p3, p4}
+	CompareHeights(heights)
+
+	assert.Equal(t, []*Height{p0, p1, p2, p3, p4}, heights)
+
+	assert.Equal(t, uint64(2), p0.Lag)
+	assert.False(t, p0.HashMismatch)
+	assert.False(t, p0.InSync())
+
+	assert.Equal(t, uint64(0), p1.Lag)
+	assert.True(t, p1.HashMismatch)
+	assert.False(t, p1.InSync())
+	assert.True(t, p2.HashMismatch)
+
+	assert.Equal(t, uint64(0), p3.Lag)
+	assert.False(t, p3.InSync())
+
+	assert.Equal(t, uint64(2), p4.Lag)
+	assert.False(t, p4.HashMismatch)
+
+	inSync := &Height{URL: "peer0.org1:7051", Height: 5, CurrentBlockHash: []byte("a")}
+	CompareHeights([]*Height{inSync})
+	assert.True(t, inSync.InSync())
+}
@@ -0,0 +1,140 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/pkg/errors"
+)
+
+const genCRLPath = "/api/v1/gencrl"
+
+type genCRLRequest struct {
+	CAName string `json:"caname,omitempty"`
+}
+
+type genCRLResult struct {
+	CRL []byte `json:"CRL"`
+}
+
+type caResponse struct {
+	Success bool            `json:"success"`
+	Result  json.RawMessage `json:"result"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// genCRL requests a CRL containing all unexpired revoked certificates from the given CA. The SDK's
+// MSP client doesn't support this request so it's sent directly to the CA's REST API, authorized
+// with a token signed by the registrar.
+func genCRL(ctx context.Context, caConfig *mspapi.CAConfig, registrar mspapi.SigningIdentity, suite core.CryptoSuite) ([]byte, error) {
+	body, err := json.Marshal(&genCRLRequest{CAName: caConfig.CAName})
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling gencrl request")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(caConfig.URL, "/")+genCRLPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating gencrl request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	token, err := authToken(http.MethodPost, genCRLPath, body, registrar, suite)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("authorization", token)
+
+	httpClient, err := newHTTPClient(caConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error sending gencrl request to [%s]", caConfig.URL)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading gencrl response")
+	}
+
+	caResp := &caResponse{}
+	if err := json.Unmarshal(respBody, caResp); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshalling gencrl response (HTTP status %d)", resp.StatusCode)
+	}
+	if !caResp.Success {
+		var msgs []string
+		for _, e := range caResp.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return nil, errors.Errorf("gencrl request failed (HTTP status %d): %s", resp.StatusCode, strings.Join(msgs, "; "))
+	}
+
+	result := &genCRLResult{}
+	if err := json.Unmarshal(caResp.Result, result); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling CRL")
+	}
+	return result.CRL, nil
+}
+
+// authToken returns a Fabric CA authorization token, which consists of the signer's certificate and
+// its signature over the request method, URI, body and certificate
+func authToken(method, uri string, body []byte, signer mspapi.SigningIdentity, suite core.CryptoSuite) (string, error) {
+	b64Cert := base64.StdEncoding.EncodeToString(signer.EnrollmentCertificate())
+	payload := method + "." + base64.StdEncoding.EncodeToString([]byte(uri)) + "." + base64.StdEncoding.EncodeToString(body) + "." + b64Cert
+
+	digest, err := suite.Hash([]byte(payload), cryptosuite.GetSHAOpts())
+	if err != nil {
+		return "", errors.Wrap(err, "error hashing authorization token")
+	}
+
+	sig, err := suite.Sign(signer.PrivateKey(), digest, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "error signing authorization token")
+	}
+
+	return b64Cert + "." + base64.StdEncoding.EncodeToString(sig), nil
+}
+
+func newHTTPClient(caConfig *mspapi.CAConfig) (*http.Client, error) {
+	if !strings.HasPrefix(caConfig.URL, "https://") {
+		return &http.Client{}, nil
+	}
+
+	tlsConfig := &tls.Config{RootCAs: x509.NewCertPool()}
+	for _, cert := range caConfig.TLSCAServerCerts {
+		tlsConfig.RootCAs.AppendCertsFromPEM(cert)
+	}
+
+	if len(caConfig.TLSCAClientCert) > 0 && len(caConfig.TLSCAClientKey) > 0 {
+		clientCert, err := tls.X509KeyPair(caConfig.TLSCAClientCert, caConfig.TLSCAClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading CA client TLS certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthToken(t *testing.T) {
+	suite, signer, publicKey := newTestSigner(t)
+
+	token, err := authToken(http.MethodPost, genCRLPath, []byte(`{"caname":"ca.org1"}`), signer, suite)
+	require.NoError(t, err)
+
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 2)
+	assert.Equal(t, "Y2VydA==", parts[0])
+
+	sig, err := base64.StdEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+
+	// The payload is the method, URI, body and certificate as signed by the Fabric CA client
+	digest := sha256.Sum256([]byte("POST.L2FwaS92MS9nZW5jcmw=.eyJjYW5hbWUiOiJjYS5vcmcxIn0=.Y2VydA=="))
+	assert.True(t, ecdsa.VerifyASN1(publicKey, digest[:], sig))
+
+	digest = sha256.Sum256([]byte("POST.L2FwaS92MS9nZW5jcmw=.e30=.Y2VydA=="))
+	assert.False(t, ecdsa.VerifyASN1(publicKey, digest[:], sig))
+}
+
+func TestGenCRL(t *testing.T) {
+	suite, signer, _ := newTestSigner(t)
+
+	var body, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, genCRLPath, r.URL.Path)
+		raw, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		body = string(raw)
+		authorization = r.Header.Get("authorization")
+
+		if strings.Contains(body, "unknown") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"result":null,"errors":[{"code":19,"message":"CA 'unknown' does not exist"}]}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":{"CRL":"` + base64.StdEncoding.EncodeToString([]byte("crl")) + `"},"errors":[]}`))
+	}))
+	defer server.Close()
+
+	crl, err := genCRL(context.Background(), &mspapi.CAConfig{URL: server.URL + "/", CAName: "ca.org1"}, signer, suite)
+	require.NoError(t, err)
+	assert.Equal(t, "crl", string(crl))
+	assert.Equal(t, `{"caname":"ca.org1"}`, body)
+	assert.True(t, strings.HasPrefix(authorization, "Y2VydA=="))
+
+	_, err = genCRL(context.Background(), &mspapi.CAConfig{URL: server.URL, CAName: "unknown"}, signer, suite)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CA 'unknown' does not exist")
+}
+
+func newTestSigner(t *testing.T) (core.CryptoSuite, mspapi.SigningIdentity, *ecdsa.PublicKey) {
+	suite, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	key, err := suite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(true))
+	require.NoError(t, err)
+
+	pubKey, err := key.PublicKey()
+	require.NoError(t, err)
+	raw, err := pubKey.Bytes()
+	require.NoError(t, err)
+	publicKey, err := x509.ParsePKIXPublicKey(raw)
+	require.NoError(t, err)
+
+	signer := mockmsp.NewMockSigningIdentity("admin", "Org1MSP")
+	signer.SetEnrollmentCertificate([]byte("cert"))
+	signer.SetPrivateKey(key)
+
+	return suite, signer, publicKey.(*ecdsa.PublicKey)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/pkg/errors"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the identity command
+func Cmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	identityCmd := &cobra.Command{
+		Use:   "identity",
+		Short: "Identity commands",
+		Long:  "Manages identities with the Fabric CA of the organization (the first org specified by --orgid)",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	identityCmd.AddCommand(getIdentityRegisterCmd(cfg))
+	identityCmd.AddCommand(getIdentityEnrollCmd(cfg))
+	identityCmd.AddCommand(getIdentityReenrollCmd(cfg))
+	identityCmd.AddCommand(getIdentityRevokeCmd(cfg))
+	identityCmd.AddCommand(getIdentityListCmd(cfg))
+	identityCmd.AddCommand(getIdentityGetCertCmd(cfg))
+	identityCmd.AddCommand(getIdentityGenCRLCmd(cfg))
+	identityCmd.AddCommand(getIdentityProvisionCmd(cfg))
+
+	return identityCmd
+}
+
+// parseAttributes parses a comma-separated list of attributes in the form name=value[:ecert]
+func parseAttributes(s string) ([]msp.Attribute, error) {
+	var attrs []msp.Attribute
+	if strings.TrimSpace(s) == "" {
+		return attrs, nil
+	}
+
+	for _, a := range strings.Split(s, ",") {
+		nv := strings.SplitN(a, "=", 2)
+		if len(nv) != 2 || strings.TrimSpace(nv[0]) == "" {
+			return nil, errors.Errorf("invalid attribute [%s]: expecting name=value[:ecert]", a)
+		}
+
+		attr := msp.Attribute{Name: strings.TrimSpace(nv[0]), Value: nv[1]}
+		if strings.HasSuffix(attr.Value, ":ecert") {
+			attr.Value = strings.TrimSuffix(attr.Value, ":ecert")
+			attr.ECert = true
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs, nil
+}
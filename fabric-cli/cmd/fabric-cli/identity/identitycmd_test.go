@@ -0,0 +1,35 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAttributes(t *testing.T) {
+	attrs, err := parseAttributes("")
+	require.NoError(t, err)
+	assert.Empty(t, attrs)
+
+	attrs, err = parseAttributes("role=loadtester:ecert,level=2,url=http://host:80")
+	require.NoError(t, err)
+	assert.Equal(t, []msp.Attribute{
+		{Name: "role", Value: "loadtester", ECert: true},
+		{Name: "level", Value: "2"},
+		{Name: "url", Value: "http://host:80"},
+	}, attrs)
+
+	_, err = parseAttributes("role")
+	assert.Error(t, err)
+
+	_, err = parseAttributes("=value")
+	assert.Error(t, err)
+}
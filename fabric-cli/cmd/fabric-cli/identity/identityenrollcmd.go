@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getIdentityEnrollCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	enrollCmd := &cobra.Command{
+		Use:   "enroll",
+		Short: "Enroll an identity",
+		Long:  "Enrolls a registered identity with the CA and stores its certificate and private key in the credential store of the connection profile, after which it may be used with --user",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.EnrollmentID() == "" || cfg.Secret() == "" {
+				fmt.Printf("\nMust specify the enrollment ID and secret of the identity\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newEnrollAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing enrollAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running enrollAction: %v", err)
+			}
+		},
+	}
+
+	flags := enrollCmd.Flags()
+	cfg.InitEnrollmentID(flags)
+	cfg.InitSecret(flags)
+	return enrollCmd
+}
+
+type enrollAction struct {
+	action.Action
+}
+
+func newEnrollAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*enrollAction, error) {
+	action := &enrollAction{}
+	err := action.Initialize(cfg, flags)
+	return action, err
+}
+
+func (a *enrollAction) run() error {
+	mspClient, err := a.MSPClient(a.OrgID())
+	if err != nil {
+		return err
+	}
+
+	enrollmentID := a.Config().EnrollmentID()
+	if err := mspClient.Enroll(enrollmentID, msp.WithSecret(a.Config().Secret())); err != nil {
+		return err
+	}
+
+	user, err := mspClient.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return err
+	}
+
+	a.Printer().PrintEnrollment(enrollmentID, user.EnrollmentCertificate())
+
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getIdentityGenCRLCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	genCRLCmd := &cobra.Command{
+		Use:   "gencrl",
+		Short: "Generate a CRL",
+		Long:  "Generates a certificate revocation list (CRL) containing all of the unexpired revoked certificates of the CA. If --output is specified then the PEM CRL is written to the given file.",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newGenCRLAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing genCRLAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running genCRLAction: %v", err)
+			}
+		},
+	}
+
+	cfg.InitOutput(genCRLCmd.Flags())
+	return genCRLCmd
+}
+
+type genCRLAction struct {
+	action.Action
+}
+
+func newGenCRLAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*genCRLAction, error) {
+	action := &genCRLAction{}
+	err := action.Initialize(cfg, flags)
+	return action, err
+}
+
+func (a *genCRLAction) run() error {
+	ctx, err := a.ClientContext()
+	if err != nil {
+		return err
+	}
+
+	orgID := a.OrgID()
+	caConfig, err := a.caConfig(ctx.EndpointConfig().NetworkConfig(), ctx.IdentityConfig(), orgID)
+	if err != nil {
+		return err
+	}
+
+	registrar, err := a.registrar(orgID, caConfig)
+	if err != nil {
+		return err
+	}
+
+	// The CA's administrative requests are subject to the resource management timeout
+	reqCtx, cancel := context.WithTimeout(a.Config().Context(), ctx.EndpointConfig().Timeout(fab.ResMgmt))
+	defer cancel()
+
+	crl, err := genCRL(reqCtx, caConfig, registrar, ctx.CryptoSuite())
+	if err != nil {
+		return err
+	}
+
+	if output := a.Config().Output(); output != "" {
+		if err := ioutil.WriteFile(output, crl, 0644); err != nil {
+			return errors.Wrapf(err, "error writing CRL to [%s]", output)
+		}
+		a.Printer().Print("Wrote the CRL to [%s]\n", output)
+		return nil
+	}
+
+	a.Printer().Print("%s", crl)
+
+	return nil
+}
+
+// caConfig returns the configuration of the first CA of the given organization
+func (a *genCRLAction) caConfig(networkConfig *fab.NetworkConfig, identityConfig mspapi.IdentityConfig, orgID string) (*mspapi.CAConfig, error) {
+	orgConfig, ok := networkConfig.Organizations[strings.ToLower(orgID)]
+	if !ok || len(orgConfig.CertificateAuthorities) == 0 {
+		return nil, errors.Errorf("no CA is configured for organization [%s]", orgID)
+	}
+
+	caID := orgConfig.CertificateAuthorities[0]
+	caConfig, ok := identityConfig.CAConfig(caID)
+	if !ok {
+		return nil, errors.Errorf("CA [%s] of organization [%s] is not configured", caID, orgID)
+	}
+	return caConfig, nil
+}
+
+// registrar returns the CA's registrar, enrolling it if it isn't in the credential store
+func (a *genCRLAction) registrar(orgID string, caConfig *mspapi.CAConfig) (mspapi.SigningIdentity, error) {
+	enrollID := caConfig.Registrar.EnrollID
+	if enrollID == "" {
+		return nil, errors.Errorf("no registrar is configured for CA [%s]", caConfig.ID)
+	}
+
+	mspClient, err := a.MSPClient(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	registrar, err := mspClient.GetSigningIdentity(enrollID)
+	if err != msp.ErrUserNotFound {
+		return registrar, err
+	}
+
+	a.Config().Logger().Infof("Enrolling registrar %s...\n", enrollID)
+	if err := mspClient.Enroll(enrollID, msp.WithSecret(caConfig.Registrar.EnrollSecret)); err != nil {
+		return nil, errors.Wrapf(err, "error enrolling registrar [%s]", enrollID)
+	}
+	return mspClient.GetSigningIdentity(enrollID)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getIdentityGetCertCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	getCertCmd := &cobra.Command{
+		Use:   "get-cert",
+		Short: "Get the certificate of an identity",
+		Long:  "Outputs the enrollment certificate of an identity in the credential store. If --output is specified then the PEM certificate is written to the given file.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.EnrollmentID() == "" {
+				fmt.Printf("\nMust specify the enrollment ID of the identity\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newGetCertAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing getCertAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running getCertAction: %v", err)
+			}
+		},
+	}
+
+	flags := getCertCmd.Flags()
+	cfg.InitEnrollmentID(flags)
+	cfg.InitOutput(flags)
+	return getCertCmd
+}
+
+type getCertAction struct {
+	action.Action
+}
+
+func newGetCertAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*getCertAction, error) {
+	action := &getCertAction{}
+	err := action.Initialize(cfg, flags)
+	return action, err
+}
+
+func (a *getCertAction) run() error {
+	mspClient, err := a.MSPClient(a.OrgID())
+	if err != nil {
+		return err
+	}
+
+	enrollmentID := a.Config().EnrollmentID()
+	user, err := mspClient.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return errors.Wrapf(err, "error getting identity [%s] from the credential store", enrollmentID)
+	}
+
+	if output := a.Config().Output(); output != "" {
+		if err := ioutil.WriteFile(output, user.EnrollmentCertificate(), 0644); err != nil {
+			return errors.Wrapf(err, "error writing certificate to [%s]", output)
+		}
+		a.Printer().Print("Wrote the certificate of [%s] to [%s]\n", enrollmentID, output)
+		return nil
+	}
+
+	a.Printer().PrintEnrollment(enrollmentID, user.EnrollmentCertificate())
+
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getIdentityListCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List identities",
+		Long:  "Lists the identities registered with the CA that the registrar is allowed to see or, if --name is specified, the given identity",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newListAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing listAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running listAction: %v", err)
+			}
+		},
+	}
+
+	cfg.InitEnrollmentID(listCmd.Flags())
+	return listCmd
+}
+
+type listAction struct {
+	action.Action
+}
+
+func newListAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*listAction, error) {
+	action := &listAction{}
+	err := action.Initialize(cfg, flags)
+	return action, err
+}
+
+func (a *listAction) run() error {
+	mspClient, err := a.MSPClient(a.OrgID())
+	if err != nil {
+		return err
+	}
+
+	if enrollmentID := a.Config().EnrollmentID(); enrollmentID != "" {
+		identity, err := mspClient.GetIdentity(enrollmentID)
+		if err != nil {
+			return err
+		}
+		a.Printer().PrintIdentities([]*msp.IdentityResponse{identity})
+		return nil
+	}
+
+	identities, err := mspClient.GetAllIdentities()
+	if err != nil {
+		return err
+	}
+
+	a.Printer().PrintIdentities(identities)
+
+	return nil
+}
@@ -0,0 +1,257 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/credential"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/executor"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const defaultManifest = "identities.json"
+
+func getIdentityProvisionCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	provisionCmd := &cobra.Command{
+		Use:   "provision",
+		Short: "Provision identities for load testing",
+		Long:  "Registers and enrolls a number of identities concurrently, stores them in the credential store and writes a manifest of the identities. The manifest may be passed to chaincode invoke/query with --identities in order to spread requests across the identities.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.Count() < 1 || cfg.Prefix() == "" {
+				fmt.Printf("\nMust specify a count of at least 1 and a prefix\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newProvisionAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing provisionAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running provisionAction: %v", err)
+			}
+		},
+	}
+
+	flags := provisionCmd.Flags()
+	cfg.InitCount(flags)
+	cfg.InitPrefix(flags)
+	cfg.InitSecret(flags, "", "The enrollment secret of all of the identities. If not specified then the CA generates a secret for each identity.")
+	cfg.InitIdentityType(flags)
+	cfg.InitAffiliation(flags)
+	cfg.InitAttributes(flags)
+	cfg.InitMaxEnrollments(flags)
+	cfg.InitConcurrency(flags, "10", "The number of identities that are registered and enrolled concurrently")
+	cfg.InitOutput(flags, defaultManifest, "The path of the manifest file")
+	return provisionCmd
+}
+
+type provisionAction struct {
+	action.Action
+}
+
+func newProvisionAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*provisionAction, error) {
+	action := &provisionAction{}
+	err := action.Initialize(cfg, flags)
+	return action, err
+}
+
+func (a *provisionAction) run() error {
+	attrs, err := parseAttributes(a.Config().Attributes())
+	if err != nil {
+		return err
+	}
+
+	orgID := a.OrgID()
+	mspClient, err := a.MSPClient(orgID)
+	if err != nil {
+		return err
+	}
+
+	concurrency := a.Config().Concurrency()
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	executor := executor.NewConcurrent(a.Config(), "Provision Identities", concurrency)
+	executor.Start()
+	defer executor.Stop(true)
+
+	count := a.Config().Count()
+	identities := make([]*credential.ManifestIdentity, count)
+	var errs []error
+	var mutex sync.RWMutex
+	var wg sync.WaitGroup
+	var numStarted, numProvisioned int
+
+	done := make(chan struct{})
+	var stopOnce sync.Once
+	stopProgress := func() { stopOnce.Do(func() { close(done) }) }
+	defer stopProgress()
+
+	go func() {
+		ticker := time.NewTicker(3 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				mutex.RLock()
+				fmt.Printf("*** %d of %d identities provisioned, %d failed\n", numProvisioned, count, len(errs))
+				mutex.RUnlock()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	startTime := time.Now()
+	ctx := a.Config().Context()
+	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		index := i
+		t := &provisionTask{
+			mspClient: mspClient,
+			request: &msp.RegistrationRequest{
+				Name:           a.Config().Prefix() + strconv.Itoa(i+1),
+				Type:           a.Config().IdentityType(),
+				MaxEnrollments: a.Config().MaxEnrollments(),
+				Affiliation:    a.Config().Affiliation(),
+				Attributes:     attrs,
+				Secret:         a.Config().Secret(),
+			},
+			started: func() {
+				mutex.Lock()
+				numStarted++
+				mutex.Unlock()
+			},
+			completed: func(identity *credential.ManifestIdentity, err error) {
+				defer wg.Done()
+				mutex.Lock()
+				defer mutex.Unlock()
+				if err != nil {
+					errs = append(errs, err)
+					return
+				}
+				if identity == nil {
+					return
+				}
+				numProvisioned++
+				identities[index] = identity
+			},
+		}
+
+		wg.Add(1)
+		if err := executor.Submit(ctx, t); err != nil {
+			wg.Done()
+			if ctx.Err() != nil {
+				break
+			}
+			// Let the tasks that were already submitted complete before giving up
+			wg.Wait()
+			return errors.Errorf("error submitting task: %s", err)
+		}
+	}
+
+	// Wait for all tasks to complete. Tasks that were queued when the command
+	// was interrupted complete without being started.
+	wg.Wait()
+	stopProgress()
+
+	if ctx.Err() != nil {
+		fmt.Printf("\n*** Interrupted after %d of %d identities\n", numStarted, count)
+	}
+
+	manifest := &credential.Manifest{
+		OrgID: orgID,
+		MSPID: a.EndpointConfig().NetworkConfig().Organizations[strings.ToLower(orgID)].MSPID,
+	}
+	for _, identity := range identities {
+		if identity != nil {
+			manifest.Identities = append(manifest.Identities, *identity)
+		}
+	}
+
+	if len(errs) > 0 {
+		fmt.Printf("\n*** %d errors provisioning identities:\n", len(errs))
+		for _, err := range errs {
+			fmt.Printf("%s\n", err)
+		}
+	}
+
+	if len(manifest.Identities) == 0 {
+		return errors.New("no identities were provisioned")
+	}
+
+	if err := manifest.Save(a.Config().Output()); err != nil {
+		return err
+	}
+
+	a.Printer().Print("Provisioned %d of %d identities in %2.2fs. The manifest was written to [%s]\n",
+		len(manifest.Identities), count, time.Since(startTime).Seconds(), a.Config().Output())
+
+	return nil
+}
+
+// provisionTask registers and enrolls a single identity. If the identity is already
+// in the credential store then it's left as is. The completed callback is invoked
+// with a nil identity and error if the task was cancelled before it started.
+type provisionTask struct {
+	mspClient *msp.Client
+	request   *msp.RegistrationRequest
+	started   func()
+	completed func(identity *credential.ManifestIdentity, err error)
+}
+
+// Invoke registers and enrolls the identity
+func (t *provisionTask) Invoke(ctx context.Context) {
+	if ctx.Err() != nil {
+		// The command was interrupted so the identity isn't provisioned
+		t.completed(nil, nil)
+		return
+	}
+
+	t.started()
+	t.completed(t.provision())
+}
+
+func (t *provisionTask) provision() (*credential.ManifestIdentity, error) {
+	name := t.request.Name
+
+	if _, err := t.mspClient.GetSigningIdentity(name); err == nil {
+		return &credential.ManifestIdentity{Name: name}, nil
+	} else if err != msp.ErrUserNotFound {
+		return nil, errors.Wrapf(err, "error looking up identity [%s] in the credential store", name)
+	}
+
+	secret, err := t.mspClient.Register(t.request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error registering identity [%s]", name)
+	}
+
+	if err := t.mspClient.Enroll(name, msp.WithSecret(secret)); err != nil {
+		return nil, errors.Wrapf(err, "error enrolling identity [%s]", name)
+	}
+
+	return &credential.ManifestIdentity{Name: name, Secret: secret}, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"fmt"
+
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getIdentityReenrollCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	reenrollCmd := &cobra.Command{
+		Use:   "reenroll",
+		Short: "Reenroll an identity",
+		Long:  "Reenrolls an identity that's in the credential store, e.g. when its certificate is about to expire. The new certificate replaces the old one in the credential store.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.EnrollmentID() == "" {
+				fmt.Printf("\nMust specify the enrollment ID of the identity\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newReenrollAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing reenrollAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running reenrollAction: %v", err)
+			}
+		},
+	}
+
+	cfg.InitEnrollmentID(reenrollCmd.Flags())
+	return reenrollCmd
+}
+
+type reenrollAction struct {
+	action.Action
+}
+
+func newReenrollAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*reenrollAction, error) {
+	action := &reenrollAction{}
+	err := action.Initialize(cfg, flags)
+	return action, err
+}
+
+func (a *reenrollAction) run() error {
+	mspClient, err := a.MSPClient(a.OrgID())
+	if err != nil {
+		return err
+	}
+
+	enrollmentID := a.Config().EnrollmentID()
+	if err := mspClient.Reenroll(enrollmentID); err != nil {
+		return err
+	}
+
+	user, err := mspClient.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return err
+	}
+
+	a.Printer().PrintEnrollment(enrollmentID, user.EnrollmentCertificate())
+
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getIdentityRegisterCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	registerCmd := &cobra.Command{
+		Use:   "register",
+		Short: "Register an identity",
+		Long:  "Registers an identity with the CA using the CA's registrar. The enrollment secret is output.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.EnrollmentID() == "" {
+				fmt.Printf("\nMust specify the enrollment ID of the identity\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newRegisterAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing registerAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running registerAction: %v", err)
+			}
+		},
+	}
+
+	flags := registerCmd.Flags()
+	cfg.InitEnrollmentID(flags)
+	cfg.InitSecret(flags)
+	cfg.InitIdentityType(flags)
+	cfg.InitAffiliation(flags)
+	cfg.InitAttributes(flags)
+	cfg.InitMaxEnrollments(flags)
+	return registerCmd
+}
+
+type registerAction struct {
+	action.Action
+}
+
+func newRegisterAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*registerAction, error) {
+	action := &registerAction{}
+	err := action.Initialize(cfg, flags)
+	return action, err
+}
+
+func (a *registerAction) run() error {
+	attrs, err := parseAttributes(a.Config().Attributes())
+	if err != nil {
+		return err
+	}
+
+	mspClient, err := a.MSPClient(a.OrgID())
+	if err != nil {
+		return err
+	}
+
+	secret, err := mspClient.Register(&msp.RegistrationRequest{
+		Name:           a.Config().EnrollmentID(),
+		Type:           a.Config().IdentityType(),
+		MaxEnrollments: a.Config().MaxEnrollments(),
+		Affiliation:    a.Config().Affiliation(),
+		Attributes:     attrs,
+		Secret:         a.Config().Secret(),
+	})
+	if err != nil {
+		return err
+	}
+
+	a.Printer().Print("Registered identity [%s] with secret [%s]\n", a.Config().EnrollmentID(), secret)
+
+	return nil
+}
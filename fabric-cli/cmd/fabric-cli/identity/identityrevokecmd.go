@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package identity
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getIdentityRevokeCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	revokeCmd := &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke an identity or certificate",
+		Long:  "Revokes all of the certificates of the identity given by --name or the single certificate given by --serial and --aki",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.EnrollmentID() == "" && (cfg.Serial() == "" || cfg.AKI() == "") {
+				fmt.Printf("\nMust specify either the enrollment ID or the serial number and AKI of the certificate\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newRevokeAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing revokeAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running revokeAction: %v", err)
+			}
+		},
+	}
+
+	flags := revokeCmd.Flags()
+	cfg.InitEnrollmentID(flags)
+	cfg.InitSerial(flags)
+	cfg.InitAKI(flags)
+	cfg.InitRevocationReason(flags)
+	return revokeCmd
+}
+
+type revokeAction struct {
+	action.Action
+}
+
+func newRevokeAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*revokeAction, error) {
+	action := &revokeAction{}
+	err := action.Initialize(cfg, flags)
+	return action, err
+}
+
+func (a *revokeAction) run() error {
+	mspClient, err := a.MSPClient(a.OrgID())
+	if err != nil {
+		return err
+	}
+
+	response, err := mspClient.Revoke(&msp.RevocationRequest{
+		Name:   a.Config().EnrollmentID(),
+		Serial: a.Config().Serial(),
+		AKI:    a.Config().AKI(),
+		Reason: a.Config().RevocationReason(),
+	})
+	if err != nil {
+		return err
+	}
+
+	a.Printer().PrintRevocation(response)
+
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package inspect
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/archive"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Cmd returns the inspect command
+func Cmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	inspectCmd := &cobra.Command{
+		Use:   "inspect <path>...",
+		Short: "Inspect blocks on disk",
+		Long:  "Prints the blocks in the given files without connecting to the network. A path may be a block file from the ledger directory of a peer or orderer (blockfile_nnnnnn), a single block such as a genesis or config .block file, or a directory or .tar.gz/.tgz archive written by 'query blocks'.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				fmt.Printf("\nMust specify the path of at least one block file\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newInspectAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing inspectAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run(args)
+			if err != nil {
+				cfg.Logger().Errorf("Error while running inspectAction: %v", err)
+			}
+		},
+	}
+
+	flags := inspectCmd.Flags()
+	cfg.InitFromBlock(flags, "0", "Blocks with a lower number are skipped")
+	cfg.InitToBlock(flags, "0", "Blocks with a higher number are skipped (no limit if not specified)")
+	return inspectCmd
+}
+
+type inspectAction struct {
+	action.Action
+}
+
+func newInspectAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*inspectAction, error) {
+	action := &inspectAction{}
+	err := action.InitializeOffline(cfg, flags)
+	return action, err
+}
+
+func (a *inspectAction) run(paths []string) error {
+	ctx := a.Config().Context()
+	for _, path := range paths {
+		err := archive.ReadBlocks(path, func(block *common.Block) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if a.include(block.Header.Number) {
+				a.Printer().PrintBlock(block)
+			}
+			return nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// include returns true if the block with the given number is within the range given by --from and --to
+func (a *inspectAction) include(num uint64) bool {
+	if num < a.Config().FromBlock() {
+		return false
+	}
+	return !a.Config().IsFlagSpecified(cliconfig.ToBlockFlag) || num <= a.Config().ToBlock()
+}
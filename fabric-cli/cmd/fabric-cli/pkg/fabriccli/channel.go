@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabriccli
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/pkg/errors"
+)
+
+// JoinChannelResult contains the outcome of joining the peers of one organization
+type JoinChannelResult struct {
+	OrgID    string
+	PeerURLs []string
+	Err      error
+}
+
+// JoinChannelRequest contains the parameters of JoinChannel
+type JoinChannelRequest struct {
+	// OnJoin, if set, is called before the peers of each organization are joined
+	OnJoin func(orgID string, peerURLs []string)
+
+	// OnResult, if set, is called after the peers of each organization are joined
+	OnResult func(JoinChannelResult)
+}
+
+// JoinChannel joins all of the selected peers to the channel, one organization at a time.
+// A result is returned for each organization. The returned error is the last error
+// that occurred, if any.
+func (c *Client) JoinChannel(ctx context.Context, req JoinChannelRequest) ([]JoinChannelResult, error) {
+	peersByOrg := c.action.PeersByOrg()
+	if len(peersByOrg) == 0 {
+		return nil, errors.New("at least one peer is required for join")
+	}
+
+	var orgIDs []string
+	for orgID := range peersByOrg {
+		orgIDs = append(orgIDs, orgID)
+	}
+	sort.Strings(orgIDs)
+
+	var results []JoinChannelResult
+	var lastErr error
+	for _, orgID := range orgIDs {
+		result := JoinChannelResult{OrgID: orgID}
+		for _, peer := range peersByOrg[orgID] {
+			result.PeerURLs = append(result.PeerURLs, peer.URL())
+		}
+		if req.OnJoin != nil {
+			req.OnJoin(orgID, result.PeerURLs)
+		}
+		result.Err = c.joinChannel(ctx, orgID)
+		if result.Err != nil {
+			lastErr = result.Err
+		}
+		if req.OnResult != nil {
+			req.OnResult(result)
+		}
+		results = append(results, result)
+	}
+
+	return results, lastErr
+}
+
+func (c *Client) joinChannel(ctx context.Context, orgID string) error {
+	channelID := c.Config().ChannelID()
+	c.Config().Logger().Debugf("Joining channel [%s] for org [%s]...\n", channelID, orgID)
+
+	resMgmtClient, err := c.action.ResourceMgmtClientForOrg(orgID)
+	if err != nil {
+		return err
+	}
+
+	orderer, err := c.action.RandomOrderer()
+	if err != nil {
+		return err
+	}
+
+	err = resMgmtClient.JoinChannel(channelID,
+		resmgmt.WithTargets(c.action.PeersByOrg()[orgID]...),
+		resmgmt.WithOrderer(orderer),
+		resmgmt.WithParentContext(ctx))
+	if err != nil {
+		return errors.WithMessage(err, "Could not join channel")
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fabriccli exposes the fabric-cli actions as a Go API so that they may be called from other
+// programs and test harnesses without shelling out to the binary. Each operation takes a typed request
+// and returns its results as Go values. The fabric-cli commands are thin wrappers around this package.
+package fabriccli
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/printer"
+	"github.com/spf13/pflag"
+)
+
+// Options contains the settings used to connect to the network. Fields that are
+// not set take the same default values as the corresponding fabric-cli flags.
+type Options struct {
+	// ConfigFile is the path of the connection profile
+	ConfigFile string
+
+	// UserName is the enrolled user that performs the operations (default User1)
+	UserName string
+
+	// Password is the user's password
+	Password string
+
+	// CertFile and KeyFile are the PEM certificate and private key of the signing identity.
+	// They're used instead of a user from the credential store.
+	CertFile string
+	KeyFile  string
+
+	// PKCS12File is a PKCS#12 bundle containing the signing identity
+	PKCS12File string
+
+	// MSPDir is a directory in MSP layout containing the signing identity
+	MSPDir string
+
+	// IdentityManifest is the path of a manifest written by 'fabric-cli identity provision'. If set
+	// then chaincode invocations are spread across the identities in the manifest.
+	IdentityManifest string
+
+	// OrgIDs contains the organizations whose peers are targeted. The first one is the user's organization.
+	OrgIDs []string
+
+	// PeerURLs restricts the target peers to the given URLs
+	PeerURLs []string
+
+	// ChannelID is the channel on which the operations are performed
+	ChannelID string
+
+	// SelectionProvider is the endorser selection provider (auto, dynamic, fabric, rules or static)
+	SelectionProvider string
+
+	// SelectionRules is the path of the selection rules file used by the rules selection provider
+	SelectionRules string
+
+	// LoggingLevel is the logging level (DEBUG, INFO, WARNING, ERROR or CRITICAL)
+	LoggingLevel string
+
+	// SkipPreflight skips the validation of the connection profile
+	SkipPreflight bool
+
+	// Timeouts overrides the timeouts of the connection profile
+	Timeouts Timeouts
+
+	// TargetStrategy chooses the targets of each request from the peers given by PeerURLs or OrgIDs
+	// (all, round-robin, random, least-latency or least-inflight). The default is all.
+	TargetStrategy string
+
+	// HealthInterval is the interval at which the target peers are health checked during InvokeLoad.
+	// Peers that are down are excluded from the targets until they recover. If 0 then peers aren't checked.
+	HealthInterval time.Duration
+}
+
+// Timeouts contains the timeouts of network operations. Timeouts that are not set are taken
+// from the connection profile or else the defaults of the corresponding fabric-cli flags.
+type Timeouts struct {
+	Connection  time.Duration
+	Endorsement time.Duration
+	Commit      time.Duration
+	Orderer     time.Duration
+	Discovery   time.Duration
+	Query       time.Duration
+}
+
+// Client performs fabric-cli operations against a Fabric network. A Client
+// holds open connections and must be closed when it's no longer needed.
+type Client struct {
+	action action.Action
+}
+
+// New returns a new Client for the given options. The settings file and
+// FABRIC_CLI_* environment variables are not consulted.
+func New(opts Options) (*Client, error) {
+	cfg, flags, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromFlags(cfg, flags)
+}
+
+// newConfig returns a CLI configuration and the flags that are set from the given options
+func newConfig(opts Options) (*cliconfig.CLIConfig, *pflag.FlagSet, error) {
+	cfg := cliconfig.New().IgnoreSettings()
+
+	flags := pflag.NewFlagSet("fabriccli", pflag.ContinueOnError)
+	cfg.InitConfigFile(flags)
+	cfg.InitUserName(flags)
+	cfg.InitUserPassword(flags)
+	cfg.InitSigningCert(flags)
+	cfg.InitSigningKey(flags)
+	cfg.InitPKCS12(flags)
+	cfg.InitMSPDir(flags)
+	cfg.InitIdentityManifest(flags)
+	cfg.InitOrgIDs(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitSelectionProvider(flags)
+	cfg.InitSelectionRules(flags)
+	cfg.InitLoggingLevel(flags)
+	cfg.InitSkipPreflight(flags)
+	cfg.InitConnectionTimeout(flags)
+	cfg.InitEndorsementTimeout(flags)
+	cfg.InitCommitTimeout(flags)
+	cfg.InitOrdererTimeout(flags)
+	cfg.InitDiscoveryTimeout(flags)
+	cfg.InitQueryTimeout(flags)
+	cfg.InitHealthInterval(flags)
+	cfg.InitTargetStrategy(flags)
+
+	values := map[string]string{
+		cliconfig.ConfigFileFlag:        opts.ConfigFile,
+		cliconfig.UserFlag:              opts.UserName,
+		cliconfig.PasswordFlag:          opts.Password,
+		cliconfig.SigningCertFlag:       opts.CertFile,
+		cliconfig.SigningKeyFlag:        opts.KeyFile,
+		cliconfig.PKCS12Flag:            opts.PKCS12File,
+		cliconfig.MSPDirFlag:            opts.MSPDir,
+		cliconfig.IdentityManifestFlag:  opts.IdentityManifest,
+		cliconfig.OrgIDsFlag:            strings.Join(opts.OrgIDs, ","),
+		cliconfig.PeerURLFlag:           strings.Join(opts.PeerURLs, ","),
+		cliconfig.ChannelIDFlag:         opts.ChannelID,
+		cliconfig.SelectionProviderFlag: opts.SelectionProvider,
+		cliconfig.SelectionRulesFlag:    opts.SelectionRules,
+		cliconfig.TargetStrategyFlag:    opts.TargetStrategy,
+		cliconfig.LoggingLevelFlag:      opts.LoggingLevel,
+	}
+	if opts.SkipPreflight {
+		values[cliconfig.SkipPreflightFlag] = strconv.FormatBool(opts.SkipPreflight)
+	}
+	for name, timeout := range map[string]time.Duration{
+		cliconfig.ConnectionTimeoutFlag:  opts.Timeouts.Connection,
+		cliconfig.EndorsementTimeoutFlag: opts.Timeouts.Endorsement,
+		cliconfig.CommitTimeoutFlag:      opts.Timeouts.Commit,
+		cliconfig.OrdererTimeoutFlag:     opts.Timeouts.Orderer,
+		cliconfig.DiscoveryTimeoutFlag:   opts.Timeouts.Discovery,
+		cliconfig.QueryTimeoutFlag:       opts.Timeouts.Query,
+		cliconfig.HealthIntervalFlag:     opts.HealthInterval,
+	} {
+		if timeout > 0 {
+			values[name] = strconv.FormatInt(int64(timeout/time.Millisecond), 10)
+		}
+	}
+
+	for name, value := range values {
+		if value == "" {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid value for %s", name)
+		}
+	}
+
+	return cfg, flags, nil
+}
+
+// NewFromFlags returns a new Client for the given CLI configuration and flags.
+// This is used by the fabric-cli commands.
+func NewFromFlags(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*Client, error) {
+	c := &Client{}
+	if err := c.action.Initialize(cfg, flags); err != nil {
+		c.action.Terminate()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close releases the resources held by the client
+func (c *Client) Close() {
+	c.action.Terminate()
+}
+
+// Config returns the CLI configuration of the client
+func (c *Client) Config() *cliconfig.CLIConfig {
+	return c.action.Config()
+}
+
+// Printer returns the printer configured by the output flags. Results returned by
+// the client may be printed with it.
+func (c *Client) Printer() printer.Printer {
+	return c.action.Printer()
+}
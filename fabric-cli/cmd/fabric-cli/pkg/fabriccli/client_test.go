@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabriccli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	cfg, _, err := newConfig(Options{
+		ConfigFile:     "config.yaml",
+		UserName:       "User2",
+		OrgIDs:         []string{"org1", "org2"},
+		PeerURLs:       []string{"peer0.org1.example.com:7051"},
+		ChannelID:      "mychannel",
+		SkipPreflight:  true,
+		Timeouts:       Timeouts{Endorsement: 3 * time.Second},
+		TargetStrategy: "round-robin",
+		HealthInterval: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "config.yaml", cfg.ConfigFile())
+	assert.Equal(t, "User2", cfg.UserName())
+	assert.Equal(t, []string{"org1", "org2"}, cfg.OrgIDs())
+	assert.Equal(t, []string{"peer0.org1.example.com:7051"}, cfg.PeerURLs())
+	assert.Equal(t, "mychannel", cfg.ChannelID())
+	assert.True(t, cfg.SkipPreflight())
+	assert.Equal(t, 3*time.Second, cfg.Timeout(fab.PeerResponse))
+	assert.Equal(t, "round-robin", cfg.TargetStrategy())
+	assert.Equal(t, 5*time.Second, cfg.HealthInterval())
+
+	// Options that aren't set take the defaults of the flags
+	cfg, _, err = newConfig(Options{})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ChannelID())
+	assert.False(t, cfg.SkipPreflight())
+	assert.Equal(t, time.Duration(0), cfg.HealthInterval())
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Options{ConfigFile: "./nonexistent.yaml", SkipPreflight: true})
+	assert.Error(t, err)
+}
+
+func TestRequestValidation(t *testing.T) {
+	c := &Client{}
+	ctx := context.Background()
+
+	_, err := c.InvokeLoad(ctx, InvokeLoadRequest{Args: []Args{{Func: "move"}}})
+	assert.EqualError(t, err, "chaincode ID is required")
+
+	_, err = c.InvokeLoad(ctx, InvokeLoadRequest{ChaincodeID: "examplecc"})
+	assert.EqualError(t, err, "at least one set of args is required")
+
+	_, err = c.QueryBlocks(ctx, QueryBlocksRequest{From: 10, To: 9})
+	assert.EqualError(t, err, "invalid block range [10-9]")
+
+	called := false
+	_, err = c.JoinChannel(ctx, JoinChannelRequest{OnJoin: func(string, []string) { called = true }})
+	assert.EqualError(t, err, "at least one peer is required for join")
+	assert.False(t, called)
+}
+
+func TestReport(t *testing.T) {
+	r := &Report{
+		Invocations:      4,
+		Duration:         2 * time.Second,
+		SuccessDurations: []time.Duration{time.Second, 3 * time.Second, 2 * time.Second},
+		FailDurations:    []time.Duration{6 * time.Second},
+	}
+	assert.Equal(t, 2.0, r.Rate())
+	assert.Equal(t, 3*time.Second, r.Average())
+	assert.Equal(t, 2*time.Second, r.AverageSuccess())
+	assert.Equal(t, 6*time.Second, r.AverageFail())
+	assert.Equal(t, time.Second, r.MinSuccess())
+	assert.Equal(t, 3*time.Second, r.MaxSuccess())
+
+	empty := &Report{}
+	assert.Equal(t, 0.0, empty.Rate())
+	assert.Equal(t, time.Duration(0), empty.Average())
+	assert.Equal(t, time.Duration(0), empty.MinSuccess())
+}
+
+func TestParseArgs(t *testing.T) {
+	args, err := ParseArgs(`[{"Func":"move","Args":["A","B","1"]},{"Func":"query","Args":["A"]}]`)
+	require.NoError(t, err)
+	require.Len(t, args, 2)
+	assert.Equal(t, "move", args[0].Func)
+	assert.Equal(t, []string{"A", "B", "1"}, args[0].Args)
+	assert.Equal(t, "query", args[1].Func)
+
+	_, err = ParseArgs(`{"Func":`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabriccli
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/pkg/errors"
+)
+
+// DiscoverConfig returns the MSPs and orderers of the channel from the discovery service
+func (c *Client) DiscoverConfig(ctx context.Context) (*discovery.ConfigResult, error) {
+	result, err := c.discover(ctx, &discovery.Query{
+		Channel: c.Config().ChannelID(),
+		Query:   &discovery.Query_ConfigQuery{ConfigQuery: &discovery.ConfigQuery{}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.GetConfigResult(), nil
+}
+
+// DiscoverPeers returns the peers of the channel, including their ledger heights and installed chaincodes,
+// from the discovery service. If an invocation chain is given then only the peers that have all of its
+// chaincodes installed are returned.
+func (c *Client) DiscoverPeers(ctx context.Context, invocationChain ...*discovery.ChaincodeCall) (*discovery.PeerMembershipResult, error) {
+	peerQuery := &discovery.PeerMembershipQuery{}
+	if len(invocationChain) > 0 {
+		peerQuery.Filter = &discovery.ChaincodeInterest{Chaincodes: invocationChain}
+	}
+
+	result, err := c.discover(ctx, &discovery.Query{
+		Channel: c.Config().ChannelID(),
+		Query:   &discovery.Query_PeerQuery{PeerQuery: peerQuery},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.GetMembers(), nil
+}
+
+// DiscoverEndorsers returns the endorsement descriptor of the given chaincode-to-chaincode invocation chain
+// from the discovery service. The first chaincode in the chain is the chaincode that's invoked. Collections
+// may be specified for each chaincode so that the collection policies are taken into account.
+func (c *Client) DiscoverEndorsers(ctx context.Context, invocationChain ...*discovery.ChaincodeCall) ([]*discovery.EndorsementDescriptor, error) {
+	if len(invocationChain) == 0 {
+		return nil, errors.New("at least one chaincode must be specified")
+	}
+
+	result, err := c.discover(ctx, &discovery.Query{
+		Channel: c.Config().ChannelID(),
+		Query: &discovery.Query_CcQuery{
+			CcQuery: &discovery.ChaincodeQuery{
+				Interests: []*discovery.ChaincodeInterest{{Chaincodes: invocationChain}},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.GetCcQueryRes().GetContent(), nil
+}
+
+func (c *Client) discover(ctx context.Context, query *discovery.Query) (*discovery.QueryResult, error) {
+	results, err := c.action.Discover(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := results[0]
+	if result.GetError() != nil {
+		return nil, errors.Errorf("discovery service returned error: %s", result.GetError().Content)
+	}
+	return result, nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabriccli
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	fabricCommon "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/selection"
+)
+
+const (
+	lifecycleSCC  = "lscc"
+	getCCDataFunc = "getccdata"
+
+	newLifecycleSCC              = "_lifecycle"
+	queryChaincodeDefinitionFunc = "QueryChaincodeDefinition"
+)
+
+// Endorsers explains the endorser selection for a chaincode
+type Endorsers struct {
+	// Policy is the endorsement policy of the chaincode
+	Policy *fabricCommon.SignaturePolicyEnvelope
+
+	// Groups contains the groups of channel peers that satisfy the endorsement policy
+	Groups [][]fab.Peer
+
+	// Selected contains the endorsers chosen by the selection provider
+	Selected []fab.Peer
+}
+
+// QueryEndorsers returns the endorsement policy of the given chaincode, the groups of channel peers that
+// satisfy the policy and the endorsers chosen for the chaincode by the configured selection provider
+func (c *Client) QueryEndorsers(ctx context.Context, ccID string) (*Endorsers, error) {
+	chProvider, err := c.action.ChannelProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	chContext, err := chProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	discovery, err := chContext.ChannelService().Discovery()
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := discovery.GetPeers()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error retrieving channel peers")
+	}
+	if len(peers) == 0 {
+		return nil, errors.Errorf("no peers found for channel [%s]", c.Config().ChannelID())
+	}
+
+	policy, err := c.queryEndorsementPolicy(ctx, ccID, peers[0])
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := selection.PeerGroups(policy, peers)
+	if err != nil {
+		return nil, err
+	}
+
+	selectionService, err := chContext.ChannelService().Selection()
+	if err != nil {
+		return nil, err
+	}
+
+	// The selected endorsers are part of the explanation so the --verbose trace of the selection is skipped
+	if traced, ok := selectionService.(interface{ Untraced() fab.SelectionService }); ok {
+		selectionService = traced.Untraced()
+	}
+
+	selected, err := selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: ccID}})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error selecting endorsers for chaincode [%s]", ccID)
+	}
+
+	return &Endorsers{
+		Policy:   policy,
+		Groups:   groups,
+		Selected: selected,
+	}, nil
+}
+
+// queryEndorsementPolicy retrieves the endorsement policy of the chaincode from the given peer. The legacy
+// lifecycle (lscc) is queried first and, if the chaincode isn't found there, the Fabric 2.x _lifecycle.
+func (c *Client) queryEndorsementPolicy(ctx context.Context, ccID string, peer fab.Peer) (*fabricCommon.SignaturePolicyEnvelope, error) {
+	channelClient, err := c.action.ChannelClient()
+	if err != nil {
+		return nil, errors.Errorf("error retrieving channel client: %v", err)
+	}
+
+	policy, err := queryLegacyEndorsementPolicy(ctx, channelClient, c.Config().ChannelID(), ccID, peer)
+	if err == nil {
+		return policy, nil
+	}
+	c.Config().Logger().Debugf("Chaincode data for chaincode [%s] not found in %s: %s", ccID, lifecycleSCC, err)
+
+	policy, lifecycleErr := queryLifecycleEndorsementPolicy(ctx, channelClient, ccID, peer)
+	if lifecycleErr != nil {
+		return nil, errors.Errorf("error querying the endorsement policy of chaincode [%s]: %s: %s; %s: %s", ccID, lifecycleSCC, err, newLifecycleSCC, lifecycleErr)
+	}
+	return policy, nil
+}
+
+func queryLegacyEndorsementPolicy(ctx context.Context, channelClient *channel.Client, channelID, ccID string, peer fab.Peer) (*fabricCommon.SignaturePolicyEnvelope, error) {
+	response, err := channelClient.Query(
+		channel.Request{
+			ChaincodeID: lifecycleSCC,
+			Fcn:         getCCDataFunc,
+			Args:        [][]byte{[]byte(channelID), []byte(ccID)},
+		},
+		channel.WithTargets(peer),
+		channel.WithParentContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	ccData := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(response.Payload, ccData); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode data")
+	}
+
+	policy := &fabricCommon.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(ccData.Policy, policy); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling endorsement policy")
+	}
+	return policy, nil
+}
+
+// queryLifecycleEndorsementPolicy returns the signature policy of the committed chaincode definition.
+// Definitions that reference a channel config policy (the default) can't be explained.
+func queryLifecycleEndorsementPolicy(ctx context.Context, channelClient *channel.Client, ccID string, peer fab.Peer) (*fabricCommon.SignaturePolicyEnvelope, error) {
+	args, err := proto.Marshal(&lb.QueryChaincodeDefinitionArgs{Name: ccID})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := channelClient.Query(
+		channel.Request{
+			ChaincodeID: newLifecycleSCC,
+			Fcn:         queryChaincodeDefinitionFunc,
+			Args:        [][]byte{args},
+		},
+		channel.WithTargets(peer),
+		channel.WithParentContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	definition := &lb.QueryChaincodeDefinitionResult{}
+	if err := proto.Unmarshal(response.Payload, definition); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode definition")
+	}
+
+	appPolicy := &pb.ApplicationPolicy{}
+	if err := proto.Unmarshal(definition.ValidationParameter, appPolicy); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling endorsement policy")
+	}
+
+	switch policy := appPolicy.Type.(type) {
+	case *pb.ApplicationPolicy_SignaturePolicy:
+		return policy.SignaturePolicy, nil
+	case *pb.ApplicationPolicy_ChannelConfigPolicyReference:
+		return nil, errors.Errorf("the endorsement policy references the channel policy [%s], which isn't a signature policy", policy.ChannelConfigPolicyReference)
+	default:
+		return nil, errors.New("the chaincode definition has no endorsement policy")
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabriccli
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/invoketask"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/multitask"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/task"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/chaincode/utils"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/executor"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/target"
+)
+
+const defaultProgressInterval = 10 * time.Second
+
+// Args contains the function and arguments of a chaincode invocation. Arguments
+// may contain the expressions supported by the fabric-cli, e.g. $rand(100).
+type Args = action.ArgStruct
+
+// ParseArgs parses args in the format of the fabric-cli --args flag, i.e. either
+// {"Func":"function","Args":["arg1","arg2"]} or an array of these
+func ParseArgs(args string) ([]Args, error) {
+	return action.ParseArgs(args)
+}
+
+// InvokeLoadRequest contains the parameters of InvokeLoad
+type InvokeLoadRequest struct {
+	// ChaincodeID is the ID of the chaincode to invoke
+	ChaincodeID string
+
+	// Args contains the invocations that make up one iteration. They are invoked in order.
+	Args []Args
+
+	// Iterations is the number of times that Args are invoked (default 1)
+	Iterations int
+
+	// Concurrency is the number of iterations that are invoked concurrently (default 1)
+	Concurrency uint16
+
+	// Sleep is the time to wait between submitting iterations
+	Sleep time.Duration
+
+	// Retry contains the retry options of each invocation. If RetryableCodes is
+	// not set then the channel client's retryable codes are used.
+	Retry retry.Opts
+
+	// OnResult, if set, is called after each invocation completes
+	OnResult func(InvocationResult)
+
+	// OnProgress, if set, is called periodically while the invocations are in progress
+	OnProgress func(Progress)
+
+	// ProgressInterval is the interval at which OnProgress is called (default 10s)
+	ProgressInterval time.Duration
+}
+
+// InvocationResult contains the result of a single invocation
+type InvocationResult struct {
+	Args     Args
+	TxID     string
+	Response *channel.Response
+	Attempts int
+	Duration time.Duration
+	Err      error
+}
+
+// Progress contains the number of completed invocations
+type Progress struct {
+	Invocations int
+	Successful  int
+	Failed      int
+}
+
+// Report contains the statistics of an InvokeLoad
+type Report struct {
+	// Invocations is the number of invocations that were started
+	Invocations      int
+	Iterations       int
+	Concurrency      uint16
+	Successful       int
+	Attempts         int
+	Duration         time.Duration
+	Errors           []error
+	SuccessDurations []time.Duration
+	FailDurations    []time.Duration
+
+	// TransientErrors contains the last error of each iteration that had an error,
+	// including the iterations that succeeded after retrying
+	TransientErrors []error
+}
+
+// Rate returns the number of invocations per second
+func (r *Report) Rate() float64 {
+	if r.Duration == 0 {
+		return 0
+	}
+	return float64(r.Invocations) / r.Duration.Seconds()
+}
+
+// Average returns the average duration of all invocations
+func (r *Report) Average() time.Duration {
+	return average(append(append([]time.Duration{}, r.SuccessDurations...), r.FailDurations...))
+}
+
+// AverageSuccess returns the average duration of successful invocations
+func (r *Report) AverageSuccess() time.Duration {
+	return average(r.SuccessDurations)
+}
+
+// AverageFail returns the average duration of failed invocations
+func (r *Report) AverageFail() time.Duration {
+	return average(r.FailDurations)
+}
+
+// MinSuccess returns the shortest duration of a successful invocation
+func (r *Report) MinSuccess() time.Duration {
+	min, _ := minMax(r.SuccessDurations)
+	return min
+}
+
+// MaxSuccess returns the longest duration of a successful invocation
+func (r *Report) MaxSuccess() time.Duration {
+	_, max := minMax(r.SuccessDurations)
+	return max
+}
+
+// InvokeLoad invokes the chaincode for the given number of iterations (with retries)
+// and returns a report of the results. If the context is cancelled then no further
+// invocations are started, the invocations in flight are allowed to complete and the
+// report covers the invocations that were made. The context's error is returned
+// along with the report in this case.
+func (c *Client) InvokeLoad(ctx context.Context, req InvokeLoadRequest) (*Report, error) {
+	if req.ChaincodeID == "" {
+		return nil, errors.New("chaincode ID is required")
+	}
+	if len(req.Args) == 0 {
+		return nil, errors.New("at least one set of args is required")
+	}
+
+	iterations := req.Iterations
+	if iterations <= 0 {
+		iterations = 1
+	}
+	concurrency := req.Concurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	retryOpts := req.Retry
+	if retryOpts.RetryableCodes == nil {
+		retryOpts.RetryableCodes = retry.ChannelClientRetryableCodes
+	}
+	progressInterval := req.ProgressInterval
+	if progressInterval <= 0 {
+		progressInterval = defaultProgressInterval
+	}
+
+	selector, err := target.NewSelector(c.Config().TargetStrategy())
+	if err != nil {
+		return nil, err
+	}
+
+	channelClients, err := c.action.ChannelClients()
+	if err != nil {
+		return nil, errors.Errorf("Error getting channel client: %v", err)
+	}
+
+	var targets func() []fab.Peer
+	if len(c.Config().PeerURLs()) > 0 || len(c.Config().OrgIDs()) > 0 {
+		if err := c.action.StartHealthMonitor(); err != nil {
+			return nil, err
+		}
+		targets = c.action.Targets
+	}
+
+	executor := executor.NewConcurrent(c.Config(), "Invoke Chaincode", concurrency)
+	executor.Start()
+	defer executor.Stop(true)
+
+	report := &Report{
+		Iterations:  iterations,
+		Concurrency: concurrency,
+	}
+
+	var wg sync.WaitGroup
+	var mutex sync.RWMutex
+	var tasks []task.Task
+	var taskID int
+	for i := 0; i < iterations; i++ {
+		ctxt := utils.NewContext()
+		multiTask := multitask.New(wg.Done)
+		for _, args := range req.Args {
+			taskID++
+			var startTime time.Time
+			var t *invoketask.Task
+			cargs := args
+			t = invoketask.New(
+				c.Config(), ctxt,
+				strconv.Itoa(taskID), channelClients[(taskID-1)%len(channelClients)], targets, selector,
+				req.ChaincodeID,
+				&cargs, executor, retryOpts,
+				func() {
+					startTime = time.Now()
+					mutex.Lock()
+					report.Invocations++
+					mutex.Unlock()
+				},
+				func(err error) {
+					duration := time.Since(startTime)
+					mutex.Lock()
+					if err != nil {
+						report.Errors = append(report.Errors, err)
+						report.FailDurations = append(report.FailDurations, duration)
+					} else {
+						report.Successful++
+						report.SuccessDurations = append(report.SuccessDurations, duration)
+					}
+					mutex.Unlock()
+
+					if req.OnResult != nil {
+						req.OnResult(InvocationResult{
+							Args:     cargs,
+							TxID:     t.TxID(),
+							Response: t.Response(),
+							Attempts: t.Attempts(),
+							Duration: duration,
+							Err:      err,
+						})
+					}
+				})
+			multiTask.Add(t)
+		}
+		tasks = append(tasks, multiTask)
+	}
+
+	numInvocations := len(tasks) * len(req.Args)
+
+	done := make(chan struct{})
+	if req.OnProgress != nil {
+		go func() {
+			ticker := time.NewTicker(progressInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					mutex.RLock()
+					progress := Progress{
+						Invocations: numInvocations,
+						Successful:  report.Successful,
+						Failed:      len(report.Errors),
+					}
+					mutex.RUnlock()
+					req.OnProgress(progress)
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	startTime := time.Now()
+
+	var submitErr error
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		if err := executor.Submit(ctx, t); err != nil {
+			wg.Done()
+			if ctx.Err() == nil {
+				submitErr = errors.Errorf("error submitting task: %s", err)
+			}
+			break
+		}
+		if req.Sleep > 0 {
+			select {
+			case <-time.After(req.Sleep):
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	// Wait for all submitted tasks to complete. Tasks that were queued when
+	// the context was cancelled complete without being started.
+	wg.Wait()
+	close(done)
+
+	report.Duration = time.Since(startTime)
+	for _, t := range tasks {
+		report.Attempts += t.Attempts()
+		if err := t.LastError(); err != nil {
+			report.TransientErrors = append(report.TransientErrors, err)
+		}
+	}
+
+	if submitErr != nil {
+		return report, submitErr
+	}
+	return report, ctx.Err()
+}
+
+func average(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+	return total / time.Duration(len(durations))
+}
+
+func minMax(durations []time.Duration) (min time.Duration, max time.Duration) {
+	for _, duration := range durations {
+		if min == 0 || min > duration {
+			min = duration
+		}
+		if max == 0 || max < duration {
+			max = duration
+		}
+	}
+	return
+}
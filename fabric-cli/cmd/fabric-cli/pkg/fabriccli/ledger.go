@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabriccli
+
+import (
+	"context"
+	"sync"
+
+	fabricCommon "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/health"
+)
+
+// QueryBlockRequest contains the parameters of QueryBlock
+type QueryBlockRequest struct {
+	// Number is the number of the block to query. It is ignored if Hash is set.
+	Number uint64
+
+	// Hash is the hash of the block to query
+	Hash []byte
+
+	// Traverse is the total number of blocks to return, starting with the queried
+	// block and following the previous-block hashes. Values less than 1 return one block.
+	Traverse int
+
+	// OnBlock, if set, is called with each block as soon as it's retrieved. The blocks
+	// aren't returned in this case so that a long traversal isn't held in memory.
+	OnBlock func(block *fabricCommon.Block)
+}
+
+// QueryBlocksRequest contains the parameters of QueryBlocks
+type QueryBlocksRequest struct {
+	// From is the number of the first block in the range
+	From uint64
+
+	// To is the number of the last block in the range (inclusive)
+	To uint64
+}
+
+// QueryInfo returns the blockchain info of the channel
+func (c *Client) QueryInfo(ctx context.Context) (*fab.BlockchainInfoResponse, error) {
+	ledgerClient, err := c.action.LedgerClient()
+	if err != nil {
+		return nil, errors.Errorf("Error getting ledger client: %v", err)
+	}
+	return ledgerClient.QueryInfo(ledger.WithParentContext(ctx))
+}
+
+// QueryHeights queries the blockchain info of the channel on each of the selected peers and compares
+// the results, so that peers that are behind or have a different block hash at the same height may be
+// identified. An error from a peer is returned in its height rather than failing the query.
+func (c *Client) QueryHeights(ctx context.Context) ([]*health.Height, error) {
+	ledgerClient, err := c.action.LedgerClient()
+	if err != nil {
+		return nil, errors.Errorf("Error getting ledger client: %v", err)
+	}
+
+	var peers []fab.Peer
+	for _, orgPeers := range c.action.PeersByOrg() {
+		peers = append(peers, orgPeers...)
+	}
+
+	heights := make([]*health.Height, len(peers))
+	var wg sync.WaitGroup
+	wg.Add(len(peers))
+	for i, peer := range peers {
+		go func(i int, peer fab.Peer) {
+			defer wg.Done()
+
+			h := &health.Height{URL: peer.URL(), MSPID: peer.MSPID()}
+			info, err := ledgerClient.QueryInfo(ledger.WithTargets(peer), ledger.WithParentContext(ctx))
+			if err != nil {
+				h.Err = err
+			} else {
+				h.Height = info.BCI.Height
+				h.CurrentBlockHash = info.BCI.CurrentBlockHash
+				h.PreviousBlockHash = info.BCI.PreviousBlockHash
+			}
+			heights[i] = h
+		}(i, peer)
+	}
+	wg.Wait()
+
+	health.CompareHeights(heights)
+
+	return heights, nil
+}
+
+// QueryBlock returns the block with the given number or hash followed by
+// the blocks that precede it, up to the requested number of blocks
+func (c *Client) QueryBlock(ctx context.Context, req QueryBlockRequest) ([]*fabricCommon.Block, error) {
+	ledgerClient, err := c.action.LedgerClient()
+	if err != nil {
+		return nil, errors.Errorf("Error getting ledger client: %v", err)
+	}
+
+	var block *fabricCommon.Block
+	if req.Hash != nil {
+		block, err = ledgerClient.QueryBlockByHash(req.Hash, ledger.WithParentContext(ctx))
+	} else {
+		block, err = ledgerClient.QueryBlock(req.Number, ledger.WithParentContext(ctx))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []*fabricCommon.Block
+	add := func(block *fabricCommon.Block) {
+		if req.OnBlock != nil {
+			req.OnBlock(block)
+		} else {
+			blocks = append(blocks, block)
+		}
+	}
+
+	add(block)
+	for i := 1; i < req.Traverse && block.Header.PreviousHash != nil; i++ {
+		block, err = ledgerClient.QueryBlockByHash(block.Header.PreviousHash, ledger.WithParentContext(ctx))
+		if err != nil {
+			return blocks, err
+		}
+		add(block)
+	}
+
+	return blocks, nil
+}
+
+// QueryBlocks returns the blocks in the given range in ascending order. If an error occurs
+// then the blocks that were retrieved are returned along with the error.
+func (c *Client) QueryBlocks(ctx context.Context, req QueryBlocksRequest) ([]*fabricCommon.Block, error) {
+	if req.To < req.From {
+		return nil, errors.Errorf("invalid block range [%d-%d]", req.From, req.To)
+	}
+
+	ledgerClient, err := c.action.LedgerClient()
+	if err != nil {
+		return nil, errors.Errorf("Error getting ledger client: %v", err)
+	}
+
+	var blocks []*fabricCommon.Block
+	for num := req.From; num <= req.To; num++ {
+		if err := ctx.Err(); err != nil {
+			return blocks, err
+		}
+		block, err := ledgerClient.QueryBlock(num, ledger.WithParentContext(ctx))
+		if err != nil {
+			return blocks, errors.WithMessagef(err, "error querying block %d", num)
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, nil
+}
+
+// BlockReader queries blocks by number. A single ledger client is used for all of
+// the queries so that many blocks may be queried concurrently with little overhead.
+type BlockReader struct {
+	ledgerClient *ledger.Client
+}
+
+// NewBlockReader returns a BlockReader for the channel
+func (c *Client) NewBlockReader() (*BlockReader, error) {
+	ledgerClient, err := c.action.LedgerClient()
+	if err != nil {
+		return nil, errors.Errorf("Error getting ledger client: %v", err)
+	}
+	return &BlockReader{ledgerClient: ledgerClient}, nil
+}
+
+// Block returns the block with the given number
+func (r *BlockReader) Block(ctx context.Context, num uint64) (*fabricCommon.Block, error) {
+	block, err := r.ledgerClient.QueryBlock(num, ledger.WithParentContext(ctx))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error querying block %d", num)
+	}
+	return block, nil
+}
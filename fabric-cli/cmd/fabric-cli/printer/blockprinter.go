@@ -12,13 +12,16 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	fabriccmn "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/discovery"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric-protos-go/msp"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	mspclient "github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
@@ -101,6 +104,12 @@ type Printer interface {
 	// PrintProcessedTransaction outputs a ProcessedTransaction
 	PrintProcessedTransaction(tx *pb.ProcessedTransaction)
 
+	// PrintTx outputs a summary of a transaction found in a block, optionally followed by the full transaction
+	PrintTx(tx *TxSummary, full bool)
+
+	// PrintKeyHistory outputs the writes and deletes of a key and the transactions that read each version
+	PrintKeyHistory(namespace, key string, versions []*KeyVersion)
+
 	// PrintChaincodeData outputs ChaincodeData
 	PrintChaincodeData(ccdata *ccprovider.ChaincodeData, collConfig *pb.CollectionConfigPackage)
 
@@ -116,6 +125,34 @@ type Printer interface {
 	// PrintPeers outputs the array of Peers
 	PrintPeers(peers []fab.Peer)
 
+	// PrintPeerHealth outputs the health status of peers
+	PrintPeerHealth(statuses []*PeerStatus)
+
+	// PrintPeerHeights outputs the ledger heights of peers
+	PrintPeerHeights(heights []*PeerHeight)
+
+	// PrintDiscoveredConfig outputs the channel config returned by the discovery service
+	PrintDiscoveredConfig(config *discovery.ConfigResult)
+
+	// PrintDiscoveredPeers outputs the peer membership returned by the discovery service
+	PrintDiscoveredPeers(members *discovery.PeerMembershipResult)
+
+	// PrintEndorsementDescriptors outputs the endorsement descriptors returned by the discovery service
+	PrintEndorsementDescriptors(descriptors []*discovery.EndorsementDescriptor)
+
+	// PrintEndorsers outputs the endorsement policy of a chaincode, the groups of peers that satisfy it
+	// and the endorsers chosen by the selection provider
+	PrintEndorsers(policy *fabriccmn.SignaturePolicyEnvelope, groups [][]fab.Peer, selected []fab.Peer)
+
+	// PrintIdentities outputs the identities registered with a CA
+	PrintIdentities(identities []*mspclient.IdentityResponse)
+
+	// PrintEnrollment outputs the enrollment certificate of an identity
+	PrintEnrollment(enrollmentID string, cert []byte)
+
+	// PrintRevocation outputs the certificates revoked by a CA
+	PrintRevocation(response *mspclient.RevocationResponse)
+
 	// Print outputs a formatted string
 	Print(frmt string, vars ...interface{})
 }
@@ -237,6 +274,137 @@ func (p *BlockPrinter) PrintPeers(peers []fab.Peer) {
 	p.PrintFooter()
 }
 
+// PeerStatus is the health status of a peer
+type PeerStatus struct {
+	URL     string
+	MSPID   string
+	Healthy bool
+	Latency time.Duration
+	Err     error
+}
+
+// PeerHeight is the blockchain info of a peer on a channel. Lag is the number of blocks by which the peer
+// is behind the highest peer and HashMismatch is set if another peer at the same height has a different hash.
+type PeerHeight struct {
+	URL               string
+	MSPID             string
+	Height            uint64
+	CurrentBlockHash  []byte
+	PreviousBlockHash []byte
+	Lag               uint64
+	HashMismatch      bool
+	Err               error
+}
+
+// PrintPeerHealth prints the health status of peers
+func (p *BlockPrinter) PrintPeerHealth(statuses []*PeerStatus) {
+	if p.Formatter == nil {
+		for _, s := range statuses {
+			fmt.Printf("%+v\n", *s)
+		}
+		return
+	}
+
+	p.PrintHeader()
+	p.Array("Peers")
+	for _, s := range statuses {
+		p.Item("Peer", s.URL)
+		p.Field("URL", s.URL)
+		p.Field("MSPID", s.MSPID)
+		if s.Healthy {
+			p.Field("Status", "UP")
+		} else {
+			p.Field("Status", "DOWN")
+		}
+		p.Field("Latency", s.Latency.String())
+		if s.Err != nil {
+			p.Field("Error", s.Err.Error())
+		}
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.PrintFooter()
+}
+
+// PrintPeerHeights prints the ledger heights of peers. Peers that are behind the highest peer,
+// that have a different block hash than another peer at the same height or that couldn't be
+// queried are flagged in the Status field.
+func (p *BlockPrinter) PrintPeerHeights(heights []*PeerHeight) {
+	if p.Formatter == nil {
+		for _, h := range heights {
+			fmt.Printf("%s\t%d\t%s\t%s\n", h.URL, h.Height, Base64URLEncode(h.CurrentBlockHash), heightStatus(h))
+		}
+		return
+	}
+
+	p.PrintHeader()
+	p.Array("Peers")
+	for _, h := range heights {
+		p.Item("Peer", h.URL)
+		p.Field("URL", h.URL)
+		p.Field("MSPID", h.MSPID)
+		if h.Err != nil {
+			p.Field("Status", heightStatus(h))
+			p.Field("Error", h.Err.Error())
+			p.ItemEnd()
+			continue
+		}
+		p.Field("Height", h.Height)
+		p.Field("CurrentBlockHash", Base64URLEncode(h.CurrentBlockHash))
+		p.Field("PreviousBlockHash", Base64URLEncode(h.PreviousBlockHash))
+		p.Field("Status", heightStatus(h))
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.PrintFooter()
+}
+
+func heightStatus(h *PeerHeight) string {
+	switch {
+	case h.Err != nil:
+		return "ERROR"
+	case h.HashMismatch && h.Lag > 0:
+		return fmt.Sprintf("*** BEHIND %d, HASH MISMATCH", h.Lag)
+	case h.HashMismatch:
+		return "*** HASH MISMATCH"
+	case h.Lag > 0:
+		return fmt.Sprintf("*** BEHIND %d", h.Lag)
+	default:
+		return "IN SYNC"
+	}
+}
+
+// PrintEndorsers prints the endorsement policy of a chaincode, the groups of peers that satisfy it
+// and the endorsers chosen by the selection provider
+func (p *BlockPrinter) PrintEndorsers(policy *fabriccmn.SignaturePolicyEnvelope, groups [][]fab.Peer, selected []fab.Peer) {
+	if p.Formatter == nil {
+		fmt.Printf("Policy: %s\nGroups: %s\nSelected: %s\n", policy, groups, selected)
+		return
+	}
+
+	p.PrintHeader()
+	p.Element("Policy")
+	p.PrintSignaturePolicyEnvelope(policy)
+	p.ElementEnd()
+	p.Array("Groups")
+	for i, group := range groups {
+		p.Item("Group", i)
+		p.printPeerURLs(group)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.Array("Selected")
+	p.printPeerURLs(selected)
+	p.ArrayEnd()
+	p.PrintFooter()
+}
+
+func (p *BlockPrinter) printPeerURLs(peers []fab.Peer) {
+	for _, peer := range peers {
+		p.Field(peer.MSPID(), peer.URL())
+	}
+}
+
 // PrintChaincodes prints the array of ChaincodeInfo
 func (p *BlockPrinter) PrintChaincodes(chaincodes []*pb.ChaincodeInfo) {
 	if p.Formatter == nil {
@@ -268,6 +436,154 @@ func (p *BlockPrinter) PrintProcessedTransaction(tx *pb.ProcessedTransaction) {
 	p.PrintFooter()
 }
 
+// TxSummary is a summary of a transaction in a block
+type TxSummary struct {
+	BlockNum       uint64
+	TxNum          int
+	TxID           string
+	Type           fabriccmn.HeaderType
+	Timestamp      time.Time
+	CreatorMSPID   string
+	ChaincodeID    string
+	Function       string
+	ValidationCode pb.TxValidationCode
+	Writes         []KeyWrite
+	Envelope       *fabriccmn.Envelope
+
+	// DecodeErr is set if the transaction couldn't be decoded
+	DecodeErr error
+}
+
+// KeyWrite is a key written or deleted by a transaction
+type KeyWrite struct {
+	Namespace string
+	Key       string
+	IsDelete  bool
+	Value     []byte
+}
+
+// PrintTx prints a summary of a transaction found in a block. If full is true then
+// the transaction envelope is printed after the summary.
+func (p *BlockPrinter) PrintTx(tx *TxSummary, full bool) {
+	if p.Formatter == nil {
+		fmt.Printf("%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s", tx.BlockNum, tx.TxNum, tx.TxID, tx.ValidationCode,
+			tx.Timestamp.Format(time.RFC3339), tx.CreatorMSPID, tx.ChaincodeID, tx.Function)
+		if tx.DecodeErr != nil {
+			fmt.Printf("\tERROR: %s", tx.DecodeErr)
+		}
+		fmt.Printf("\n")
+		if full && tx.Envelope != nil {
+			fmt.Printf("%s\n", tx.Envelope)
+		}
+		return
+	}
+
+	p.PrintHeader()
+	p.Field("BlockNum", tx.BlockNum)
+	p.Field("TxIndex", tx.TxNum)
+	p.Field("TxID", tx.TxID)
+	p.Field("Type", tx.Type)
+	p.Field("ValidationCode", tx.ValidationCode)
+	p.Field("Timestamp", tx.Timestamp.Format(time.RFC3339Nano))
+	p.Field("CreatorMSPID", tx.CreatorMSPID)
+	p.Field("ChaincodeID", tx.ChaincodeID)
+	p.Field("Function", tx.Function)
+	if tx.DecodeErr != nil {
+		p.Field("Error", tx.DecodeErr.Error())
+	}
+	p.Array("Writes")
+	for i, w := range tx.Writes {
+		p.Item("Write", i)
+		p.Field("Namespace", w.Namespace)
+		p.Field("Key", w.Key)
+		p.Field("IsDelete", w.IsDelete)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	if full && tx.Envelope != nil {
+		p.Element("Envelope")
+		p.PrintEnvelope(tx.Envelope)
+		p.ElementEnd()
+	}
+	p.PrintFooter()
+}
+
+// KeyVersion is a write or delete of a key along with the transactions that read the version that it created
+type KeyVersion struct {
+	BlockNum uint64
+	TxNum    uint64
+
+	// Tx and Write are nil if the version was written before the scanned blocks
+	Tx    *TxSummary
+	Write *KeyWrite
+
+	Reads []*TxSummary
+}
+
+// PrintKeyHistory prints the writes and deletes of a key in the order in which they were committed.
+// Each entry is followed by the transactions that read the version of the key that it created.
+func (p *BlockPrinter) PrintKeyHistory(namespace, key string, versions []*KeyVersion) {
+	if p.Formatter == nil {
+		for _, v := range versions {
+			if v.Tx == nil {
+				fmt.Printf("%d:%d\t(written before the scanned blocks)\n", v.BlockNum, v.TxNum)
+			} else {
+				fmt.Printf("%d:%d\t%s\t%s\t%s\t%s\t%s\t%s\n", v.BlockNum, v.TxNum, v.Tx.TxID,
+					v.Tx.Timestamp.Format(time.RFC3339), v.Tx.CreatorMSPID, v.Tx.ValidationCode, keyOperation(v.Write), v.Write.Value)
+			}
+			for _, tx := range v.Reads {
+				fmt.Printf("\tREAD\t%d:%d\t%s\t%s\n", tx.BlockNum, tx.TxNum, tx.TxID, tx.ValidationCode)
+			}
+		}
+		return
+	}
+
+	p.PrintHeader()
+	p.Field("Namespace", namespace)
+	p.Field("Key", key)
+	p.Array("History")
+	for i, v := range versions {
+		p.Item("Version", i)
+		p.Field("Version", fmt.Sprintf("%d:%d", v.BlockNum, v.TxNum))
+		if v.Tx == nil {
+			p.Field("Operation", "written before the scanned blocks")
+		} else {
+			p.Field("Operation", keyOperation(v.Write))
+			p.Field("TxID", v.Tx.TxID)
+			p.Field("BlockNum", v.Tx.BlockNum)
+			p.Field("TxNum", v.Tx.TxNum)
+			p.Field("Timestamp", v.Tx.Timestamp.Format(time.RFC3339Nano))
+			p.Field("CreatorMSPID", v.Tx.CreatorMSPID)
+			p.Field("ValidationCode", v.Tx.ValidationCode)
+			if !v.Write.IsDelete {
+				p.Field("Value", NewPayload(namespace, key, v.Write.Value))
+			}
+		}
+		p.Array("Reads")
+		for j, tx := range v.Reads {
+			p.Item("Read", j)
+			p.Field("TxID", tx.TxID)
+			p.Field("BlockNum", tx.BlockNum)
+			p.Field("TxNum", tx.TxNum)
+			p.Field("Timestamp", tx.Timestamp.Format(time.RFC3339Nano))
+			p.Field("CreatorMSPID", tx.CreatorMSPID)
+			p.Field("ValidationCode", tx.ValidationCode)
+			p.ItemEnd()
+		}
+		p.ArrayEnd()
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.PrintFooter()
+}
+
+func keyOperation(w *KeyWrite) string {
+	if w.IsDelete {
+		return "DELETE"
+	}
+	return "WRITE"
+}
+
 // PrintChaincodeData prints the given ChaincodeData
 func (p *BlockPrinter) PrintChaincodeData(ccData *ccprovider.ChaincodeData, collConfig *pb.CollectionConfigPackage) {
 	if p.Formatter == nil {
@@ -369,7 +685,7 @@ func (p *BlockPrinter) PrintChaincodeEvent(event *fab.CCEvent) {
 	p.Field("EventName", event.EventName)
 	//p.Field("ChannelID", event.ChannelID)
 	p.Field("TxID", event.TxID)
-	p.Field("Payload", event.Payload)
+	p.Field("Payload", NewPayload(event.ChaincodeID, "", event.Payload))
 	p.PrintFooter()
 }
 
@@ -381,7 +697,7 @@ func (p *BlockPrinter) PrintTxProposalResponse(response *fab.TransactionProposal
 		} else if response.ProposalResponse == nil || response.ProposalResponse.Response == nil {
 			p.Field("Response", nil)
 		} else {
-			p.Field("Payload", response.ProposalResponse.Response.Payload)
+			p.Field("Payload", NewPayload(chaincodeIDFromProposalResponse(response.ProposalResponse), "", response.ProposalResponse.Response.Payload))
 		}
 	} else {
 		p.Field("Endorser", response.Endorser)
@@ -397,13 +713,13 @@ func (p *BlockPrinter) PrintProposalResponse(response *pb.ProposalResponse) {
 	if response == nil {
 		return
 	}
-	p.Element("Response")
-	p.PrintResponse(response.Response)
-	p.ElementEnd()
-
 	prp := &pb.ProposalResponsePayload{}
 	unmarshalOrPanic(response.Payload, prp)
 
+	p.Element("Response")
+	p.PrintChaincodeResponse(response.Response, chaincodeIDFromProposalResponse(response))
+	p.ElementEnd()
+
 	p.Element("ProposalResponsePayload")
 	p.PrintProposalResponsePayload(prp)
 	p.ElementEnd()
@@ -437,7 +753,7 @@ func (p *BlockPrinter) PrintResponses(responses []*pb.Response) {
 func (p *BlockPrinter) PrintResponse(response *pb.Response) {
 	p.Field("Message", response.Message)
 	p.Field("Status", response.Status)
-	p.Field("Payload", response.Payload)
+	p.Field("Payload", NewPayload("", "", response.Payload))
 }
 
 // PrintCDSData prints the chaincode deployment spec data (CDSData)
@@ -533,7 +849,9 @@ func (p *BlockPrinter) PrintChaincodeInfo(ccInfo *pb.ChaincodeInfo) {
 // PrintSignatureHeader prints a SignatureHeader
 func (p *BlockPrinter) PrintSignatureHeader(sigHdr *fabriccmn.SignatureHeader) {
 	p.Field("Nonce", sigHdr.Nonce)
-	p.Field("Creator", sigHdr.Creator)
+	p.Element("Creator")
+	p.PrintSerializedIdentity(sigHdr.Creator)
+	p.ElementEnd()
 }
 
 // PrintData prints the block of data formatted according to the given HeaderType
@@ -569,9 +887,12 @@ func (p *BlockPrinter) PrintConfigEnvelope(envelope *fabriccmn.ConfigEnvelope) {
 	p.Element("Config")
 	p.PrintConfig(envelope.Config)
 	p.ElementEnd()
-	p.Element("LastUpdate")
-	p.PrintEnvelope(envelope.LastUpdate)
-	p.ElementEnd()
+	// The config envelope of a genesis block has no last update
+	if envelope.LastUpdate != nil {
+		p.Element("LastUpdate")
+		p.PrintEnvelope(envelope.LastUpdate)
+		p.ElementEnd()
+	}
 }
 
 // PrintConfigUpdateEnvelope prints a ConfigUpdateEnvelope
@@ -651,7 +972,7 @@ func (p *BlockPrinter) PrintFilteredCCAction(action *pb.FilteredChaincodeAction)
 	p.Field("Txid", ccEvent.GetTxId())
 	p.Field("ChaincodeId", ccEvent.GetChaincodeId())
 	p.Field("EventName", ccEvent.GetEventName())
-	p.Field("Payload", ccEvent.GetPayload())
+	p.Field("Payload", NewPayload(ccEvent.GetChaincodeId(), "", ccEvent.GetPayload()))
 }
 
 // PrintChaincodeActionPayload prints a ChaincodeActionPayload
@@ -732,7 +1053,7 @@ func (p *BlockPrinter) PrintProposalResponsePayload(prp *pb.ProposalResponsePayl
 // PrintChaincodeAction prints a ChaincodeAction
 func (p *BlockPrinter) PrintChaincodeAction(chaincodeAction *pb.ChaincodeAction) {
 	p.Element("Response")
-	p.PrintChaincodeResponse(chaincodeAction.Response)
+	p.PrintChaincodeResponse(chaincodeAction.Response, chaincodeAction.ChaincodeId.GetName())
 	p.ElementEnd()
 
 	p.Element("Results")
@@ -795,7 +1116,7 @@ func (p *BlockPrinter) PrintKvRwSet(kvRWSet *kvrwset.KVRWSet, namespace string)
 		if namespace == "lscc" {
 			p.PrintLSCCWrite(w)
 		} else {
-			p.PrintWrite(w)
+			p.PrintWrite(w, namespace)
 		}
 		p.ItemEnd()
 	}
@@ -936,11 +1257,11 @@ func (p *BlockPrinter) PrintVersion(version *kvrwset.Version) {
 	p.Field("TxNum", version.TxNum)
 }
 
-// PrintWrite prints a key-value write (KVWrite)
-func (p *BlockPrinter) PrintWrite(w *kvrwset.KVWrite) {
+// PrintWrite prints a key-value write (KVWrite) for the given namespace
+func (p *BlockPrinter) PrintWrite(w *kvrwset.KVWrite, namespace string) {
 	p.Field("Key", w.Key)
 	p.Field("IsDelete", w.IsDelete)
-	p.Field("Value", w.Value)
+	p.Field("Value", NewPayload(namespace, w.Key, w.Value))
 }
 
 // PrintLSCCWrite prints a key-value write (KVWrite)
@@ -978,11 +1299,11 @@ func (p *BlockPrinter) printCollectionConfig(value []byte) {
 	p.doPrintCollConfig(cp)
 }
 
-// PrintChaincodeResponse prints a response
-func (p *BlockPrinter) PrintChaincodeResponse(response *pb.Response) {
+// PrintChaincodeResponse prints a response from the given chaincode
+func (p *BlockPrinter) PrintChaincodeResponse(response *pb.Response, ccID string) {
 	p.Field("Message", response.Message)
 	p.Field("Status", response.Status)
-	p.Field("Payload", response.Payload)
+	p.Field("Payload", NewPayload(ccID, "", response.Payload))
 }
 
 // PrintChaincodeEventFromBlock prints a ChaincodeEvent
@@ -990,12 +1311,14 @@ func (p *BlockPrinter) PrintChaincodeEventFromBlock(chaincodeEvent *pb.Chaincode
 	p.Field("ChaincodeId", chaincodeEvent.ChaincodeId)
 	p.Field("EventName", chaincodeEvent.EventName)
 	p.Field("TxID", chaincodeEvent.TxId)
-	p.Field("Payload", chaincodeEvent.Payload)
+	p.Field("Payload", NewPayload(chaincodeEvent.ChaincodeId, "", chaincodeEvent.Payload))
 }
 
 // PrintEndorsement prints an Endorsement
 func (p *BlockPrinter) PrintEndorsement(endorsement *pb.Endorsement) {
-	p.Field("Endorser", endorsement.Endorser)
+	p.Element("Endorser")
+	p.PrintSerializedIdentity(endorsement.Endorser)
+	p.ElementEnd()
 	p.Field("Signature", endorsement.Signature)
 }
 
@@ -1311,11 +1634,35 @@ func (p *BlockPrinter) PrintMSPConfig(mspConfig *msp.MSPConfig) {
 // PrintFabricMSPConfig prints a FabricMSPConfig
 func (p *BlockPrinter) PrintFabricMSPConfig(mspConfig *msp.FabricMSPConfig) {
 	p.Field("Name", mspConfig.Name)
-	p.Array("Admins")
-	for i, admCert := range mspConfig.Admins {
-		p.ItemValue("Admin Cert", i, admCert)
+	p.PrintCertificates("RootCerts", mspConfig.RootCerts)
+	p.PrintCertificates("IntermediateCerts", mspConfig.IntermediateCerts)
+	p.PrintCertificates("Admins", mspConfig.Admins)
+	p.PrintCertificates("TLSRootCerts", mspConfig.TlsRootCerts)
+	p.PrintCertificates("TLSIntermediateCerts", mspConfig.TlsIntermediateCerts)
+	p.Field("RevocationListSize", len(mspConfig.RevocationList))
+
+	if len(mspConfig.OrganizationalUnitIdentifiers) > 0 {
+		p.Array("OrganizationalUnitIdentifiers")
+		for i, ouID := range mspConfig.OrganizationalUnitIdentifiers {
+			p.Item("OUIdentifier", i)
+			p.PrintOUIdentifier(ouID)
+			p.ItemEnd()
+		}
+		p.ArrayEnd()
+	}
+
+	if mspConfig.FabricNodeOus != nil {
+		p.Element("NodeOUs")
+		p.PrintNodeOUs(mspConfig.FabricNodeOus)
+		p.ElementEnd()
+	}
+
+	if mspConfig.CryptoConfig != nil {
+		p.Element("CryptoConfig")
+		p.Field("SignatureHashFamily", mspConfig.CryptoConfig.SignatureHashFamily)
+		p.Field("IdentityIdentifierHashFunction", mspConfig.CryptoConfig.IdentityIdentifierHashFunction)
+		p.ElementEnd()
 	}
-	p.ArrayEnd()
 }
 
 // PrintAnchorPeers prints AnchorPeers
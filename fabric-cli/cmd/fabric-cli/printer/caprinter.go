@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"fmt"
+
+	mspclient "github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+)
+
+// PrintIdentities prints the identities registered with a CA
+func (p *BlockPrinter) PrintIdentities(identities []*mspclient.IdentityResponse) {
+	if p.Formatter == nil {
+		for _, identity := range identities {
+			fmt.Printf("%+v\n", *identity)
+		}
+		return
+	}
+
+	p.PrintHeader()
+	p.Array("Identities")
+	for _, identity := range identities {
+		p.Item("Identity", identity.ID)
+		p.PrintIdentity(identity)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.PrintFooter()
+}
+
+// PrintIdentity prints an identity registered with a CA
+func (p *BlockPrinter) PrintIdentity(identity *mspclient.IdentityResponse) {
+	p.Field("ID", identity.ID)
+	p.Field("Type", identity.Type)
+	p.Field("Affiliation", identity.Affiliation)
+	p.Field("MaxEnrollments", identity.MaxEnrollments)
+	if identity.CAName != "" {
+		p.Field("CAName", identity.CAName)
+	}
+
+	p.Array("Attributes")
+	for _, attr := range identity.Attributes {
+		p.Item("Attribute", attr.Name)
+		p.Field("Name", attr.Name)
+		p.Field("Value", attr.Value)
+		p.Field("ECert", attr.ECert)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+}
+
+// PrintEnrollment prints the enrollment certificate of an identity
+func (p *BlockPrinter) PrintEnrollment(enrollmentID string, cert []byte) {
+	if p.Formatter == nil {
+		fmt.Printf("%s:\n%s\n", enrollmentID, cert)
+		return
+	}
+
+	p.PrintHeader()
+	p.Field("ID", enrollmentID)
+	p.Element("Certificate")
+	p.PrintCertificate(cert)
+	p.ElementEnd()
+	p.PrintFooter()
+}
+
+// PrintRevocation prints the certificates revoked by a CA
+func (p *BlockPrinter) PrintRevocation(response *mspclient.RevocationResponse) {
+	if p.Formatter == nil {
+		fmt.Printf("%+v\n", *response)
+		return
+	}
+
+	p.PrintHeader()
+	p.Array("RevokedCerts")
+	for i, cert := range response.RevokedCerts {
+		p.Item("RevokedCert", i)
+		p.Field("Serial", cert.Serial)
+		p.Field("AKI", cert.AKI)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.PrintFooter()
+}
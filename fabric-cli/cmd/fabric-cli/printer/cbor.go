@@ -0,0 +1,24 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+)
+
+type cborDecoder struct {
+}
+
+// Decode decodes a single CBOR data item (RFC 8949). The keys of maps are output in sorted order.
+func (d *cborDecoder) Decode(data []byte) (string, error) {
+	var value interface{}
+	if err := cbor.Unmarshal(data, &value); err != nil {
+		return "", errors.Wrap(err, "invalid CBOR")
+	}
+	return formatValue(value), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ANSI escape codes used by the display formatter
+const (
+	colorReset  = "\x1b[0m"
+	colorDim    = "\x1b[2m"
+	colorName   = "\x1b[36m"
+	colorString = "\x1b[32m"
+	colorNumber = "\x1b[33m"
+	colorBool   = "\x1b[35m"
+)
+
+const (
+	// maxSummaryGroups is the maximum number of child names listed in the summary of a collapsed element
+	maxSummaryGroups = 3
+)
+
+// IsTerminal returns true if the given writer type writes to a terminal that supports colors
+func IsTerminal(writerType WriterType) bool {
+	var f *os.File
+	switch writerType {
+	case STDOUT:
+		f = os.Stdout
+	case STDERR:
+		f = os.Stderr
+	default:
+		return false
+	}
+
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// collapsedNode holds the children of an element that's collapsed by the display formatter
+type collapsedNode struct {
+	name   string
+	level  int
+	names  []string
+	counts map[string]int
+	total  int
+}
+
+// addChild adds a child to the collapsed element. Only direct children are included in the
+// summary and the index of an item, e.g. Cert[2], is ignored.
+func (n *collapsedNode) addChild(name string) {
+	if n.level > 0 {
+		return
+	}
+	if i := strings.Index(name, "["); i > 0 {
+		name = name[:i]
+	}
+	if n.counts == nil {
+		n.counts = make(map[string]int)
+	}
+	if _, ok := n.counts[name]; !ok {
+		n.names = append(n.names, name)
+	}
+	n.counts[name]++
+	n.total++
+}
+
+// summary returns a summary of the children of the collapsed element, e.g. "{…3 Cert}" or "{…1 Name, 2 Admin}"
+func (n *collapsedNode) summary() string {
+	if n.total == 0 {
+		return "{}"
+	}
+	if len(n.names) > maxSummaryGroups {
+		return fmt.Sprintf("{…%d fields}", n.total)
+	}
+
+	var groups []string
+	for _, name := range n.names {
+		label := name
+		if label == "" {
+			label = "items"
+		}
+		groups = append(groups, fmt.Sprintf("%d %s", n.counts[name], label))
+	}
+	return fmt.Sprintf("{…%s}", strings.Join(groups, ", "))
+}
@@ -0,0 +1,358 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// DecoderType specifies the type of payload decoder
+type DecoderType string
+
+const (
+	// JSONDecoder pretty-prints JSON payloads
+	JSONDecoder DecoderType = "json"
+
+	// ProtobufDecoder decodes protobuf payloads using either a registered message type
+	// or a message type from a descriptor set
+	ProtobufDecoder DecoderType = "protobuf"
+
+	// CBORDecoder decodes CBOR (RFC 7049) payloads
+	CBORDecoder DecoderType = "cbor"
+
+	// MsgPackDecoder decodes MessagePack payloads
+	MsgPackDecoder DecoderType = "msgpack"
+
+	// ExecDecoder decodes payloads using an external command which reads the
+	// payload from stdin and writes the decoded payload to stdout
+	ExecDecoder DecoderType = "exec"
+)
+
+const (
+	defaultExecTimeout = 5 * time.Second
+	keySeparator       = "/"
+)
+
+// Decoder decodes a binary payload into a human readable form
+type Decoder interface {
+	Decode(data []byte) (string, error)
+}
+
+// DecoderSpec is the configuration of a payload decoder
+type DecoderSpec struct {
+	// Type is the type of decoder (json, protobuf, cbor, msgpack or exec)
+	Type DecoderType `yaml:"type"`
+
+	// DescriptorSet is the path of a protobuf descriptor set, i.e. the output of
+	// protoc --include_imports --descriptor_set_out (protobuf only). If not specified
+	// then Message must be a message type that's registered with the protobuf library.
+	DescriptorSet string `yaml:"descriptorset"`
+
+	// Message is the fully qualified protobuf message type, e.g. example.Account (protobuf only)
+	Message string `yaml:"message"`
+
+	// Command is the external command to run (exec only)
+	Command string `yaml:"command"`
+
+	// Args are the arguments passed to the external command (exec only)
+	Args []string `yaml:"args"`
+
+	// Timeout is the maximum time that the external command may run, e.g. 5s (exec only)
+	Timeout string `yaml:"timeout"`
+}
+
+type decoderEntry struct {
+	keyPrefix string
+	decoder   Decoder
+}
+
+// DecoderRegistry holds the payload decoders, keyed by chaincode ID and key prefix
+type DecoderRegistry struct {
+	decoders map[string][]*decoderEntry
+}
+
+// NewDecoderRegistry returns a new, empty decoder registry
+func NewDecoderRegistry() *DecoderRegistry {
+	return &DecoderRegistry{decoders: make(map[string][]*decoderEntry)}
+}
+
+// LoadDecoders loads the payload decoders from the given YAML file. Each key in the file
+// is either a chaincode ID or 'chaincodeID/keyPrefix' and each value is a DecoderSpec, e.g.
+//
+//	examplecc:
+//	  type: json
+//	examplecc/account_:
+//	  type: protobuf
+//	  descriptorset: ./accounts.pb
+//	  message: example.Account
+//
+// Relative paths are resolved against the directory containing the file.
+func LoadDecoders(path string) (*DecoderRegistry, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading decoders file [%s]", path)
+	}
+
+	specs := make(map[string]*DecoderSpec)
+	if err := yaml.UnmarshalStrict(raw, &specs); err != nil {
+		return nil, errors.Wrapf(err, "error parsing decoders file [%s]", path)
+	}
+
+	registry := NewDecoderRegistry()
+	baseDir := filepath.Dir(path)
+	for key, spec := range specs {
+		if spec == nil {
+			return nil, errors.Errorf("no decoder specified for [%s] in decoders file [%s]", key, path)
+		}
+		decoder, err := newDecoder(spec, baseDir)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("invalid decoder for [%s] in decoders file [%s]", key, path))
+		}
+
+		ccID, keyPrefix := splitDecoderKey(key)
+		registry.Register(ccID, keyPrefix, decoder)
+	}
+
+	return registry, nil
+}
+
+// Register registers a decoder for the given chaincode and key prefix. An empty
+// key prefix matches all keys as well as chaincode responses and events.
+func (r *DecoderRegistry) Register(ccID, keyPrefix string, decoder Decoder) {
+	entries := append(r.decoders[ccID], &decoderEntry{keyPrefix: keyPrefix, decoder: decoder})
+
+	// Longest prefix first so that the most specific decoder is chosen
+	sort.SliceStable(entries, func(i, j int) bool {
+		return len(entries[i].keyPrefix) > len(entries[j].keyPrefix)
+	})
+	r.decoders[ccID] = entries
+}
+
+// Decoder returns the decoder for the given chaincode and key or nil if no decoder is registered
+func (r *DecoderRegistry) Decoder(ccID, key string) Decoder {
+	if r == nil {
+		return nil
+	}
+	for _, entry := range r.decoders[ccID] {
+		if strings.HasPrefix(key, entry.keyPrefix) {
+			return entry.decoder
+		}
+	}
+	return nil
+}
+
+func splitDecoderKey(key string) (ccID, keyPrefix string) {
+	s := strings.SplitN(key, keySeparator, 2)
+	if len(s) == 1 {
+		return s[0], ""
+	}
+	return s[0], s[1]
+}
+
+func newDecoder(spec *DecoderSpec, baseDir string) (Decoder, error) {
+	switch spec.Type {
+	case JSONDecoder:
+		return &jsonDecoder{}, nil
+	case CBORDecoder:
+		return &cborDecoder{}, nil
+	case MsgPackDecoder:
+		return &msgPackDecoder{}, nil
+	case ProtobufDecoder:
+		if spec.Message == "" {
+			return nil, errors.New("message must be specified for protobuf decoder")
+		}
+		if spec.DescriptorSet == "" {
+			return newRegisteredProtoDecoder(spec.Message), nil
+		}
+		return newDescriptorProtoDecoder(resolvePath(baseDir, spec.DescriptorSet), spec.Message)
+	case ExecDecoder:
+		return newExecDecoder(spec, baseDir)
+	default:
+		return nil, errors.Errorf("unsupported decoder type [%s]", spec.Type)
+	}
+}
+
+func resolvePath(baseDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+type jsonDecoder struct {
+}
+
+func (d *jsonDecoder) Decode(data []byte) (string, error) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+type execDecoder struct {
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+func newExecDecoder(spec *DecoderSpec, baseDir string) (*execDecoder, error) {
+	if spec.Command == "" {
+		return nil, errors.New("command must be specified for exec decoder")
+	}
+
+	timeout := defaultExecTimeout
+	if spec.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(spec.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timeout [%s]", spec.Timeout)
+		}
+	}
+
+	command := spec.Command
+	if strings.Contains(command, string(filepath.Separator)) {
+		command = resolvePath(baseDir, command)
+	}
+
+	return &execDecoder{command: command, args: spec.Args, timeout: timeout}, nil
+}
+
+func (d *execDecoder) Decode(data []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, d.command, d.args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", errors.Errorf("decoder command [%s] timed out after %s", d.command, d.timeout)
+		}
+		return "", errors.Wrapf(err, "decoder command [%s] failed: %s", d.command, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSuffix(stdout.String(), "\n"), nil
+}
+
+// mapEntry is a key-value pair of a decoded MessagePack map. A slice of entries is used
+// instead of a Go map in order to preserve the order of the encoded map.
+type mapEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+// writeValue writes a value decoded from CBOR or MessagePack in a JSON-like notation.
+// Byte strings are written in hex notation, e.g. h'0a0b'.
+func writeValue(out *bytes.Buffer, value interface{}, indent int) {
+	prefix := strings.Repeat(wireIndent, indent)
+	switch v := value.(type) {
+	case nil:
+		out.WriteString("null")
+	case string:
+		out.WriteString(strconv.Quote(v))
+	case []byte:
+		fmt.Fprintf(out, "h'%s'", hex.EncodeToString(v))
+	case float32:
+		writeValue(out, float64(v), indent)
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			out.WriteString("NaN")
+		case math.IsInf(v, 1):
+			out.WriteString("Infinity")
+		case math.IsInf(v, -1):
+			out.WriteString("-Infinity")
+		default:
+			out.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			out.WriteString("[]")
+			return
+		}
+		out.WriteString("[\n")
+		for i, item := range v {
+			out.WriteString(prefix + wireIndent)
+			writeValue(out, item, indent+1)
+			if i < len(v)-1 {
+				out.WriteString(",")
+			}
+			out.WriteString("\n")
+		}
+		out.WriteString(prefix + "]")
+	case map[interface{}]interface{}:
+		writeValue(out, sortedEntries(v), indent)
+	case []mapEntry:
+		if len(v) == 0 {
+			out.WriteString("{}")
+			return
+		}
+		out.WriteString("{\n")
+		for i, entry := range v {
+			out.WriteString(prefix + wireIndent)
+			writeValue(out, entry.key, indent+1)
+			out.WriteString(": ")
+			writeValue(out, entry.value, indent+1)
+			if i < len(v)-1 {
+				out.WriteString(",")
+			}
+			out.WriteString("\n")
+		}
+		out.WriteString(prefix + "}")
+	case time.Time:
+		out.WriteString(strconv.Quote(v.Format(time.RFC3339Nano)))
+	case big.Int:
+		out.WriteString(v.String())
+	case cbor.Tag:
+		fmt.Fprintf(out, "%d(", v.Number)
+		writeValue(out, v.Content, indent)
+		out.WriteString(")")
+	case cbor.SimpleValue:
+		fmt.Fprintf(out, "simple(%d)", v)
+	default:
+		fmt.Fprintf(out, "%v", v)
+	}
+}
+
+func formatValue(value interface{}) string {
+	var out bytes.Buffer
+	writeValue(&out, value, 0)
+	return out.String()
+}
+
+// sortedEntries returns the entries of a decoded CBOR map sorted by key
+func sortedEntries(m map[interface{}]interface{}) []mapEntry {
+	entries := make([]mapEntry, 0, len(m))
+	for key, value := range m {
+		entries = append(entries, mapEntry{key: key, value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return formatValue(entries[i].key) < formatValue(entries[j].key)
+	})
+	return entries
+}
@@ -0,0 +1,215 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestDecoderRegistry(t *testing.T) {
+	ccDecoder := &jsonDecoder{}
+	accountDecoder := &cborDecoder{}
+	adminDecoder := &msgPackDecoder{}
+
+	r := NewDecoderRegistry()
+	r.Register("examplecc", "", ccDecoder)
+	r.Register("examplecc", "account_admin", adminDecoder)
+	r.Register("examplecc", "account_", accountDecoder)
+
+	assert.Equal(t, ccDecoder, r.Decoder("examplecc", ""))
+	assert.Equal(t, ccDecoder, r.Decoder("examplecc", "other"))
+	assert.Equal(t, accountDecoder, r.Decoder("examplecc", "account_1"))
+	assert.Equal(t, adminDecoder, r.Decoder("examplecc", "account_admin1"))
+	assert.Nil(t, r.Decoder("othercc", "account_1"))
+
+	var nilRegistry *DecoderRegistry
+	assert.Nil(t, nilRegistry.Decoder("examplecc", ""))
+}
+
+func TestLoadDecoders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "decoders")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "decoders.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+examplecc:
+  type: json
+examplecc/account_:
+  type: protobuf
+  message: common.Header
+othercc:
+  type: exec
+  command: cat
+  timeout: 2s
+`), 0600))
+
+	r, err := LoadDecoders(path)
+	require.NoError(t, err)
+	assert.IsType(t, &jsonDecoder{}, r.Decoder("examplecc", "x"))
+	assert.IsType(t, &registeredProtoDecoder{}, r.Decoder("examplecc", "account_1"))
+
+	d := r.Decoder("othercc", "")
+	require.IsType(t, &execDecoder{}, d)
+	s, err := d.Decode([]byte("hello\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", s)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("examplecc:\n  type: xml\n"), 0600))
+	_, err = LoadDecoders(path)
+	assert.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("examplecc:\n  type: protobuf\n"), 0600))
+	_, err = LoadDecoders(path)
+	assert.Error(t, err)
+}
+
+func TestCBORDecoder(t *testing.T) {
+	d := &cborDecoder{}
+
+	// {"a": 1, "b": [-2, h'0102', 1.5], "c": true, "d": null}
+	s, err := d.Decode([]byte{0xa4,
+		0x61, 'a', 0x01,
+		0x61, 'b', 0x83, 0x21, 0x42, 0x01, 0x02, 0xf9, 0x3e, 0x00,
+		0x61, 'c', 0xf5,
+		0x61, 'd', 0xf6,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": 1,\n  \"b\": [\n    -2,\n    h'0102',\n    1.5\n  ],\n  \"c\": true,\n  \"d\": null\n}", s)
+
+	// Indefinite length text string and array, an epoch time and an unknown tag
+	s, err = d.Decode([]byte{0x9f, 0x7f, 0x62, 'a', 'b', 0x61, 'c', 0xff, 0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0, 0xd8, 0x64, 0x01, 0xff})
+	assert.NoError(t, err)
+	assert.Equal(t, "[\n  \"abc\",\n  \"2013-03-21T20:04:00Z\",\n  100(1)\n]", s)
+
+	// Map keys are sorted
+	s, err = d.Decode([]byte{0xa2, 0x61, 'b', 0x02, 0x61, 'a', 0x01})
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": 1,\n  \"b\": 2\n}", s)
+
+	_, err = d.Decode([]byte{0x83, 0x01, 0x02})
+	assert.Error(t, err)
+
+	_, err = d.Decode([]byte{0x01, 0x02})
+	assert.Error(t, err)
+}
+
+func TestMsgPackDecoder(t *testing.T) {
+	d := &msgPackDecoder{}
+
+	// {"a": 1, "b": [-2, bin(0102), 1.5], "c": true, "d": nil}
+	s, err := d.Decode([]byte{0x84,
+		0xa1, 'a', 0x01,
+		0xa1, 'b', 0x93, 0xfe, 0xc4, 0x02, 0x01, 0x02, 0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0,
+		0xa1, 'c', 0xc3,
+		0xa1, 'd', 0xc0,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": 1,\n  \"b\": [\n    -2,\n    h'0102',\n    1.5\n  ],\n  \"c\": true,\n  \"d\": null\n}", s)
+
+	s, err = d.Decode([]byte{0x92, 0xd1, 0xff, 0x00, 0xcd, 0x01, 0x00})
+	assert.NoError(t, err)
+	assert.Equal(t, "[\n  -256,\n  256\n]", s)
+
+	_, err = d.Decode([]byte{0x92, 0x01})
+	assert.Error(t, err)
+
+	_, err = d.Decode([]byte{0xc1})
+	assert.Error(t, err)
+}
+
+func TestDescriptorProtoDecoder(t *testing.T) {
+	fds := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("account.proto"),
+				Package: proto.String("example"),
+				EnumType: []*descriptorpb.EnumDescriptorProto{
+					{
+						Name: proto.String("Status"),
+						Value: []*descriptorpb.EnumValueDescriptorProto{
+							{Name: proto.String("ACTIVE"), Number: proto.Int32(0)},
+							{Name: proto.String("CLOSED"), Number: proto.Int32(1)},
+						},
+					},
+				},
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Account"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							newField("id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+							newField("balance", 2, descriptorpb.FieldDescriptorProto_TYPE_SINT64, ""),
+							newField("status", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".example.Status"),
+							newField("owner", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".example.Account.Owner"),
+							newRepeatedField("scores", 5, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+						},
+						NestedType: []*descriptorpb.DescriptorProto{
+							{
+								Name: proto.String("Owner"),
+								Field: []*descriptorpb.FieldDescriptorProto{
+									newField("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	d, err := newDescriptorProtoDecoderFromSet(fds, "example.Account")
+	require.NoError(t, err)
+
+	data := []byte{
+		0x0a, 0x02, 'a', '1', // id
+		0x10, 0x03, // balance (zigzag -2)
+		0x18, 0x01, // status
+		0x22, 0x05, 0x0a, 0x03, 'b', 'o', 'b', // owner
+		0x2a, 0x02, 0x07, 0x08, // scores (packed)
+		0x30, 0x09, // unknown field
+	}
+	s, err := d.Decode(data)
+	assert.NoError(t, err)
+
+	// The text format randomly adds whitespace after separators so that its output isn't relied upon
+	s = regexp.MustCompile(`: +`).ReplaceAllString(s, ": ")
+	assert.Equal(t, "id: \"a1\"\nbalance: -2\nstatus: CLOSED\nowner: {\n  name: \"bob\"\n}\nscores: 7\nscores: 8\n6: 9", s)
+
+	// Truncated field
+	_, err = d.Decode([]byte{0x0a, 0x05, 'a'})
+	assert.Error(t, err)
+
+	_, err = newDescriptorProtoDecoderFromSet(fds, "example.Unknown")
+	assert.Error(t, err)
+}
+
+func newField(name string, number int32, fieldType descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	fd := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Type:   fieldType.Enum(),
+	}
+	if typeName != "" {
+		fd.TypeName = proto.String(typeName)
+	}
+	return fd
+}
+
+func newRepeatedField(name string, number int32, fieldType descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+	fd := newField(name, number, fieldType, "")
+	fd.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	return fd
+}
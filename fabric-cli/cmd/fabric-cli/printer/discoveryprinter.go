@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+)
+
+// PrintDiscoveredConfig prints the channel config returned by the discovery service
+func (p *BlockPrinter) PrintDiscoveredConfig(config *discovery.ConfigResult) {
+	if p.Formatter == nil {
+		fmt.Printf("%s\n", config)
+		return
+	}
+
+	p.PrintHeader()
+	p.Array("MSPs")
+	for _, mspID := range sortedKeys(config.Msps) {
+		p.Item("MSP", mspID)
+		p.PrintFabricMSPConfig(config.Msps[mspID])
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.Array("Orderers")
+	for _, mspID := range sortedKeys(config.Orderers) {
+		p.Item("MSP", mspID)
+		for _, endpoint := range config.Orderers[mspID].Endpoint {
+			p.Field("Endpoint", fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port))
+		}
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.PrintFooter()
+}
+
+// PrintDiscoveredPeers prints the peer membership returned by the discovery service
+func (p *BlockPrinter) PrintDiscoveredPeers(members *discovery.PeerMembershipResult) {
+	if p.Formatter == nil {
+		fmt.Printf("%s\n", members)
+		return
+	}
+
+	p.PrintHeader()
+	p.Array("Orgs")
+	for _, mspID := range sortedKeys(members.PeersByOrg) {
+		p.Item("MSP", mspID)
+		p.PrintDiscoveredPeerList(members.PeersByOrg[mspID].Peers)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.PrintFooter()
+}
+
+// PrintEndorsementDescriptors prints the endorsement descriptors returned by the discovery service
+func (p *BlockPrinter) PrintEndorsementDescriptors(descriptors []*discovery.EndorsementDescriptor) {
+	if p.Formatter == nil {
+		for _, desc := range descriptors {
+			fmt.Printf("%s\n", desc)
+		}
+		return
+	}
+
+	p.PrintHeader()
+	p.Array("EndorsementDescriptors")
+	for _, desc := range descriptors {
+		p.Item("Chaincode", desc.Chaincode)
+		p.PrintEndorsementDescriptor(desc)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+	p.PrintFooter()
+}
+
+// PrintEndorsementDescriptor prints an endorsement descriptor. Any one of the layouts satisfies the
+// endorsement policy, where a layout is the number of endorsements required from each group of peers.
+func (p *BlockPrinter) PrintEndorsementDescriptor(desc *discovery.EndorsementDescriptor) {
+	p.Field("Chaincode", desc.Chaincode)
+
+	p.Array("Layouts")
+	for i, layout := range desc.Layouts {
+		p.Item("Layout", i)
+		for _, group := range sortedKeys(layout.QuantitiesByGroup) {
+			p.Field(group, layout.QuantitiesByGroup[group])
+		}
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+
+	p.Array("EndorsersByGroups")
+	for _, group := range sortedKeys(desc.EndorsersByGroups) {
+		p.Item("Group", group)
+		p.PrintDiscoveredPeerList(desc.EndorsersByGroups[group].Peers)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+}
+
+// PrintDiscoveredPeerList prints a list of peers returned by the discovery service
+func (p *BlockPrinter) PrintDiscoveredPeerList(peers []*discovery.Peer) {
+	p.Array("Peers")
+	for i, peer := range peers {
+		p.Item("Peer", i)
+		p.PrintDiscoveredPeer(peer)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+}
+
+// PrintDiscoveredPeer prints a peer returned by the discovery service
+func (p *BlockPrinter) PrintDiscoveredPeer(peer *discovery.Peer) {
+	if alive := gossipMessage(peer.MembershipInfo).GetAliveMsg(); alive != nil {
+		p.Field("Endpoint", alive.GetMembership().GetEndpoint())
+	}
+
+	if stateInfo := gossipMessage(peer.StateInfo).GetStateInfo(); stateInfo != nil {
+		properties := stateInfo.GetProperties()
+		p.Field("LedgerHeight", properties.GetLedgerHeight())
+		p.Field("LeftChannel", properties.GetLeftChannel())
+		p.Array("Chaincodes")
+		for _, cc := range properties.GetChaincodes() {
+			p.Item("Chaincode", cc.Name)
+			p.Field("Name", cc.Name)
+			p.Field("Version", cc.Version)
+			p.ItemEnd()
+		}
+		p.ArrayEnd()
+	}
+
+	p.Element("Identity")
+	p.PrintSerializedIdentity(peer.Identity)
+	p.ElementEnd()
+}
+
+// gossipMessage returns the gossip message in the envelope, or nil if there is none
+func gossipMessage(envelope *gossip.Envelope) *gossip.GossipMessage {
+	if envelope == nil {
+		return nil
+	}
+	msg := &gossip.GossipMessage{}
+	if err := proto.Unmarshal(envelope.Payload, msg); err != nil {
+		return nil
+	}
+	return msg
+}
+
+// sortedKeys returns the keys of a map with string keys in sorted order
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,336 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FsyncNever leaves it up to the operating system to flush the output file to disk
+	FsyncNever = "never"
+
+	// FsyncAlways syncs the output file to disk after every record
+	FsyncAlways = "always"
+
+	rotatedTimeFormat = "20060102T150405.000"
+	gzipExt           = ".gz"
+)
+
+// RecordWriter is a Writer that needs to know where a record (e.g. a block or an event) ends.
+// EndRecord is invoked by the formatter after the footer of each record has been written.
+type RecordWriter interface {
+	Writer
+	EndRecord() error
+}
+
+// FileWriterOpts contains options for the file writer
+type FileWriterOpts struct {
+	// Path is the path of the output file
+	Path string
+
+	// MaxSize is the size in bytes after which the output file is rotated (0 disables size-based rotation)
+	MaxSize int64
+
+	// RotateInterval is the interval after which the output file is rotated (0 disables time-based rotation)
+	RotateInterval time.Duration
+
+	// Compress indicates whether rotated files are compressed with gzip
+	Compress bool
+
+	// Fsync is the policy for syncing the output file to disk - 'never', 'always' (after every record)
+	// or the minimum number of milliseconds between syncs
+	Fsync string
+}
+
+// fileWriter writes the output to a file. The file is only rotated at record boundaries
+// so that a record is never split across files.
+type fileWriter struct {
+	opts         FileWriterOpts
+	syncAlways   bool
+	syncInterval time.Duration
+
+	mutex    sync.Mutex
+	file     *os.File
+	buf      *bufio.Writer
+	size     int64
+	openedAt time.Time
+	syncedAt time.Time
+	now      func() time.Time
+	rename   func(oldpath, newpath string) error
+
+	// compressing tracks the rotated files that are being compressed in the background
+	compressing sync.WaitGroup
+	compressErr error
+}
+
+// NewFileWriter returns a new Writer that writes to the file at the given path. If the file
+// exists then output is appended to it.
+func NewFileWriter(opts *FileWriterOpts) (Writer, error) {
+	if opts.Path == "" {
+		return nil, errors.New("path of output file not specified")
+	}
+
+	w := &fileWriter{opts: *opts, now: time.Now, rename: os.Rename}
+
+	switch strings.ToLower(opts.Fsync) {
+	case "", FsyncNever:
+	case FsyncAlways:
+		w.syncAlways = true
+	default:
+		millis, err := strconv.ParseInt(opts.Fsync, 10, 64)
+		if err != nil || millis <= 0 {
+			return nil, errors.Errorf("invalid fsync policy [%s] - expecting 'never', 'always' or a number of milliseconds", opts.Fsync)
+		}
+		w.syncInterval = time.Duration(millis) * time.Millisecond
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *fileWriter) Write(format string, a ...interface{}) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return errors.New("file writer is closed")
+	}
+
+	n, err := fmt.Fprintf(w.buf, format, a...)
+	w.size += int64(n)
+	return err
+}
+
+// EndRecord flushes the record, syncs the file according to the fsync policy and
+// rotates the file if either its maximum size or rotation interval has been reached
+func (w *fileWriter) EndRecord() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return errors.New("file writer is closed")
+	}
+
+	if err := w.buf.Flush(); err != nil {
+		return errors.Wrapf(err, "error writing to file [%s]", w.opts.Path)
+	}
+
+	now := w.now()
+	if w.syncAlways || (w.syncInterval > 0 && now.Sub(w.syncedAt) >= w.syncInterval) {
+		if err := w.file.Sync(); err != nil {
+			return errors.Wrapf(err, "error syncing file [%s]", w.opts.Path)
+		}
+		w.syncedAt = now
+	}
+
+	if (w.opts.MaxSize > 0 && w.size >= w.opts.MaxSize) ||
+		(w.opts.RotateInterval > 0 && now.Sub(w.openedAt) >= w.opts.RotateInterval) {
+		return w.rotate()
+	}
+
+	return nil
+}
+
+// Close flushes and closes the file and waits for the rotated files to be compressed
+func (w *fileWriter) Close() error {
+	err := w.closeFile()
+
+	w.compressing.Wait()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err == nil {
+		err = w.compressErr
+	}
+	return err
+}
+
+func (w *fileWriter) closeFile() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.close()
+	w.file = nil
+	return err
+}
+
+func (w *fileWriter) open() error {
+	file, size, err := w.openFile()
+	if err != nil {
+		return err
+	}
+	w.setFile(file, size)
+	return nil
+}
+
+// openFile opens (or creates) the file at the configured path and returns the file and its size
+func (w *fileWriter) openFile() (*os.File, int64, error) {
+	if dir := filepath.Dir(w.opts.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, 0, errors.Wrapf(err, "error creating directory for file [%s]", w.opts.Path)
+		}
+	}
+
+	file, err := os.OpenFile(w.opts.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "error opening file [%s]", w.opts.Path)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, errors.Wrapf(err, "error getting info for file [%s]", w.opts.Path)
+	}
+	return file, info.Size(), nil
+}
+
+func (w *fileWriter) setFile(file *os.File, size int64) {
+	w.file = file
+	w.buf = bufio.NewWriter(file)
+	w.size = size
+	w.openedAt = w.now()
+	w.syncedAt = w.openedAt
+}
+
+// flush flushes the buffer and syncs the file unless the fsync policy is 'never'
+func (w *fileWriter) flush() error {
+	if err := w.buf.Flush(); err != nil {
+		return errors.Wrapf(err, "error writing to file [%s]", w.opts.Path)
+	}
+	if w.opts.Fsync != "" && strings.ToLower(w.opts.Fsync) != FsyncNever {
+		if err := w.file.Sync(); err != nil {
+			return errors.Wrapf(err, "error syncing file [%s]", w.opts.Path)
+		}
+	}
+	return nil
+}
+
+func (w *fileWriter) close() error {
+	if err := w.flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// rotate renames the current file with a timestamp suffix and opens a new file at the original path.
+// The current file is only closed once the new file has been opened. If rotation fails then output
+// continues to be written to the current file at the original path and rotation is retried at the end
+// of the next record. Rotated files are compressed in the background (if required).
+func (w *fileWriter) rotate() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	rotatedPath := w.rotatedPath()
+	if err := w.rename(w.opts.Path, rotatedPath); err != nil {
+		return errors.Wrapf(err, "error renaming file [%s] to [%s]", w.opts.Path, rotatedPath)
+	}
+
+	file, size, err := w.openFile()
+	if err != nil {
+		// The current file is still open so move it back to the original path
+		if rerr := w.rename(rotatedPath, w.opts.Path); rerr != nil {
+			return errors.Wrapf(err, "error renaming file [%s] back to [%s]: %s", rotatedPath, w.opts.Path, rerr)
+		}
+		return err
+	}
+
+	if err := w.file.Close(); err != nil {
+		file.Close()
+		return errors.Wrapf(err, "error closing file [%s]", rotatedPath)
+	}
+	w.setFile(file, size)
+
+	if w.opts.Compress {
+		w.compressing.Add(1)
+		go w.compress(rotatedPath)
+	}
+	return nil
+}
+
+// compress compresses a rotated file. The first error is returned by Close.
+func (w *fileWriter) compress(path string) {
+	defer w.compressing.Done()
+
+	if err := compressFile(path); err != nil {
+		w.mutex.Lock()
+		if w.compressErr == nil {
+			w.compressErr = err
+		}
+		w.mutex.Unlock()
+	}
+}
+
+// rotatedPath returns a path for the rotated file, e.g. blocks.json -> blocks-20181018T101510.123.json
+func (w *fileWriter) rotatedPath() string {
+	ext := filepath.Ext(w.opts.Path)
+	base := strings.TrimSuffix(w.opts.Path, ext)
+	timestamp := w.now().Format(rotatedTimeFormat)
+
+	path := fmt.Sprintf("%s-%s%s", base, timestamp, ext)
+	for i := 1; exists(path) || exists(path+gzipExt); i++ {
+		path = fmt.Sprintf("%s-%s-%d%s", base, timestamp, i, ext)
+	}
+	return path
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// compressFile compresses the given file with gzip and removes the original
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "error opening file [%s]", path)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+gzipExt, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "error creating file [%s]", path+gzipExt)
+	}
+
+	gz := gzip.NewWriter(out)
+	gz.Name = filepath.Base(path)
+	if _, err := io.Copy(gz, in); err != nil {
+		gz.Close()
+		out.Close()
+		return errors.Wrapf(err, "error compressing file [%s]", path)
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		return errors.Wrapf(err, "error compressing file [%s]", path)
+	}
+	if err := out.Close(); err != nil {
+		return errors.Wrapf(err, "error closing file [%s]", path+gzipExt)
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsWriterType(t *testing.T) {
+	assert.Equal(t, FILE, AsWriterType("file:/tmp/blocks.json"))
+	assert.Equal(t, "/tmp/blocks.json", WriterPath("file:/tmp/blocks.json"))
+	assert.Equal(t, "", WriterPath("stdout"))
+	assert.Equal(t, STDERR, AsWriterType("stderr"))
+}
+
+func TestFileWriterRotateBySize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filewriter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "out", "blocks.json")
+	w, err := NewFileWriter(&FileWriterOpts{Path: path, MaxSize: 10, Fsync: FsyncAlways})
+	require.NoError(t, err)
+	fw := w.(*fileWriter)
+
+	now := time.Date(2018, 10, 18, 10, 15, 10, 0, time.UTC)
+	fw.now = func() time.Time { return now }
+
+	// The file is not rotated in the middle of a record
+	require.NoError(t, w.Write("%s", "0123456789"))
+	require.NoError(t, w.Write("%s\n", "abc"))
+	assertFiles(t, dir, "blocks.json")
+
+	require.NoError(t, fw.EndRecord())
+	assertFiles(t, dir, "blocks-20181018T101510.000.json", "blocks.json")
+
+	require.NoError(t, w.Write("%s\n", "record2-abcdef"))
+	require.NoError(t, fw.EndRecord())
+	require.NoError(t, w.Write("%s\n", "record3"))
+	require.NoError(t, fw.Close())
+	assertFiles(t, dir, "blocks-20181018T101510.000-1.json", "blocks-20181018T101510.000.json", "blocks.json")
+
+	assertContents(t, filepath.Join(dir, "out", "blocks-20181018T101510.000.json"), "0123456789abc\n")
+	assertContents(t, filepath.Join(dir, "out", "blocks-20181018T101510.000-1.json"), "record2-abcdef\n")
+	assertContents(t, path, "record3\n")
+
+	assert.Error(t, w.Write("closed"))
+}
+
+func TestFileWriterRotateByInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filewriter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "out", "blocks.log")
+	w, err := NewFileWriter(&FileWriterOpts{Path: path, RotateInterval: time.Minute, Compress: true, Fsync: "1000"})
+	require.NoError(t, err)
+	fw := w.(*fileWriter)
+
+	now := time.Date(2018, 10, 18, 10, 15, 10, 0, time.UTC)
+	fw.now = func() time.Time { return now }
+	fw.openedAt = now
+
+	require.NoError(t, w.Write("%s\n", "record1"))
+	require.NoError(t, fw.EndRecord())
+	assertFiles(t, dir, "blocks.log")
+
+	now = now.Add(time.Minute)
+	require.NoError(t, w.Write("%s\n", "record2"))
+	require.NoError(t, fw.EndRecord())
+	require.NoError(t, fw.Close())
+	assertFiles(t, dir, "blocks-20181018T101610.000.log.gz", "blocks.log")
+
+	f, err := os.Open(filepath.Join(dir, "out", "blocks-20181018T101610.000.log.gz"))
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	contents, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, "record1\nrecord2\n", string(contents))
+
+	assertContents(t, path, "")
+}
+
+func TestFileWriterRotateFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filewriter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "out", "blocks.json")
+	w, err := NewFileWriter(&FileWriterOpts{Path: path, MaxSize: 5})
+	require.NoError(t, err)
+	fw := w.(*fileWriter)
+
+	now := time.Date(2018, 10, 18, 10, 15, 10, 0, time.UTC)
+	fw.now = func() time.Time { return now }
+	fw.rename = func(oldpath, newpath string) error { return errors.New("rename failed") }
+
+	// Output continues to be written to the original file if it can't be rotated
+	require.NoError(t, w.Write("%s\n", "record1"))
+	assert.Error(t, fw.EndRecord())
+	require.NoError(t, w.Write("%s\n", "record2"))
+	assert.Error(t, fw.EndRecord())
+	assertFiles(t, dir, "blocks.json")
+
+	// Rotation is retried at the end of the next record
+	fw.rename = os.Rename
+	require.NoError(t, w.Write("%s\n", "record3"))
+	require.NoError(t, fw.EndRecord())
+	require.NoError(t, w.Write("%s\n", "record4"))
+	require.NoError(t, fw.Close())
+	assertFiles(t, dir, "blocks-20181018T101510.000.json", "blocks.json")
+
+	assertContents(t, filepath.Join(dir, "out", "blocks-20181018T101510.000.json"), "record1\nrecord2\nrecord3\n")
+	assertContents(t, path, "record4\n")
+}
+
+func TestFileWriterInvalidOpts(t *testing.T) {
+	_, err := NewFileWriter(&FileWriterOpts{})
+	assert.Error(t, err)
+
+	_, err = NewFileWriter(&FileWriterOpts{Path: filepath.Join(os.TempDir(), "blocks.json"), Fsync: "sometimes"})
+	assert.Error(t, err)
+}
+
+func assertFiles(t *testing.T, dir string, expected ...string) {
+	infos, err := ioutil.ReadDir(filepath.Join(dir, "out"))
+	require.NoError(t, err)
+
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	assert.Equal(t, expected, names)
+}
+
+func assertContents(t *testing.T, path, expected string) {
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(contents))
+}
@@ -9,6 +9,8 @@ package printer
 import (
 	"fmt"
 	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 )
 
 // OutputFormat specifies the format for printing data
@@ -93,6 +95,22 @@ type Formatter interface {
 type FormatterOpts struct {
 	// Base64Encode indicates whether binary values are to be encoded in base 64
 	Base64Encode bool
+	// DecodePayloads indicates whether the content of payloads (KV write values, chaincode
+	// responses and chaincode events) is to be detected and decoded
+	DecodePayloads bool
+	// MessageTypes contains the protobuf message types used to decode payloads, keyed by chaincode ID
+	MessageTypes map[string]string
+	// Decoders contains the payload decoders configured for specific chaincodes. If set then payloads are decoded.
+	Decoders *DecoderRegistry
+	// Writer is the writer to use instead of the one created for the writer type (required for the FILE writer type)
+	Writer Writer
+	// Color indicates whether field names and values are highlighted with ANSI colors
+	Color bool
+	// Depth is the maximum depth of nested elements that are displayed. Deeper elements are
+	// collapsed into a summary. 0 means that all elements are displayed.
+	Depth int
+	// Logger is the logger used by the LOG writer and for reporting errors (a default logger is used if nil)
+	Logger *logging.Logger
 }
 
 // NewFormatter returns a new Formatter given the format and writer type. nil is returned
@@ -104,11 +122,30 @@ func NewFormatter(format OutputFormat, writerType WriterType) Formatter {
 // NewFormatterWithOpts returns a new Formatter given the format and writer type. nil is returned
 // if no formatter exists for the given type
 func NewFormatterWithOpts(format OutputFormat, writerType WriterType, opts *FormatterOpts) Formatter {
+	logger := opts.Logger
+	if logger == nil {
+		logger = logging.NewLogger(loggerName)
+	}
+
+	writer := opts.Writer
+	if writer == nil {
+		writer = newWriter(writerType, logger)
+	}
+
 	switch format {
 	case JSON:
-		return &jsonFormatter{formatter: formatter{writer: NewWriter(writerType)}}
+		return &jsonFormatter{formatter: formatter{writer: writer, logger: logger}}
 	case DISPLAY:
-		return &displayFormatter{formatter: formatter{writer: NewWriter(writerType)}, base64Encode: opts.Base64Encode}
+		f := &displayFormatter{
+			formatter:    formatter{writer: writer, logger: logger},
+			base64Encode: opts.Base64Encode,
+			colorize:     opts.Color,
+			depth:        opts.Depth,
+		}
+		if opts.DecodePayloads || opts.Decoders != nil {
+			f.decoder = newPayloadDecoder(opts.MessageTypes, opts.Decoders, logger)
+		}
+		return f
 	default:
 		return nil
 	}
@@ -116,59 +153,88 @@ func NewFormatterWithOpts(format OutputFormat, writerType WriterType, opts *Form
 
 type formatter struct {
 	writer Writer
+	logger *logging.Logger
 }
 
 func (f *formatter) write(format string, a ...interface{}) error {
 	return f.writer.Write(format, a...)
 }
 
+// endRecord notifies the writer (if it's a RecordWriter) that a record has been written
+func (f *formatter) endRecord() {
+	if w, ok := f.writer.(RecordWriter); ok {
+		if err := w.EndRecord(); err != nil {
+			f.logger.Errorf("Error ending record: %s", err)
+		}
+	}
+}
+
 type displayFormatter struct {
 	formatter
 	indent       int
 	base64Encode bool
+	decoder      *payloadDecoder
+	colorize     bool
+	depth        int
+	collapsed    *collapsedNode
 }
 
 func (p *displayFormatter) Print(frmt string, vars ...interface{}) {
+	if p.collapsed != nil {
+		return
+	}
 	format := fmt.Sprintf("%s%s\n", p.prefix(), frmt)
 	p.write(format, vars...)
 }
 
 func (p *displayFormatter) Field(field string, value interface{}) {
+	if p.collapsed != nil {
+		p.collapsed.addChild(field)
+		return
+	}
+	if payload, ok := value.(*Payload); ok && p.decoder != nil {
+		p.printDecoded(field, p.decoder.Decode(payload))
+		return
+	}
 	if value != nil {
-		p.write("%s%s: %v\n", p.prefix(), field, p.encodeValue(value))
+		p.write("%s%s: %s\n", p.prefix(), p.name(field), p.value(p.encodeValue(value)))
 	} else {
-		p.write("%s%s:\n", p.prefix(), field)
+		p.write("%s%s:\n", p.prefix(), p.name(field))
 	}
 }
 
 func (p *displayFormatter) Element(element string) {
+	if p.collapse(element) {
+		return
+	}
 	if element != "" {
-		p.write("%s%s:\n", p.prefix(), element)
+		p.write("%s%s:\n", p.prefix(), p.name(element))
 	}
 	p.indent++
 }
 
 func (p *displayFormatter) ElementEnd() {
+	if p.collapsed != nil {
+		p.endCollapsed()
+		return
+	}
 	p.indent--
 }
 
 func (p *displayFormatter) Array(element string) {
-	if element != "" {
-		p.write("%s%s:\n", p.prefix(), element)
-	}
-	p.indent++
+	p.Element(element)
 }
 
 func (p *displayFormatter) ArrayEnd() {
-	p.indent--
-
+	p.ElementEnd()
 }
 
 func (p *displayFormatter) Item(element string, index interface{}) {
+	name := ""
 	if element != "" {
-		p.write("%s%s[%v]:\n", p.prefix(), element, index)
+		name = fmt.Sprintf("%s[%v]", element, index)
 	}
-	p.indent++
+	p.Element(name)
 }
 
 func (p *displayFormatter) ItemEnd() {
@@ -176,21 +242,77 @@ func (p *displayFormatter) ItemEnd() {
 }
 
 func (p *displayFormatter) ItemValue(element string, index interface{}, value interface{}) {
+	if p.collapsed != nil {
+		p.collapsed.addChild(element)
+		return
+	}
 	if element != "" {
-		p.write("%s%s[%v]: %v\n", p.prefix(), element, index, p.encodeValue(value))
+		p.write("%s%s: %s\n", p.prefix(), p.name(fmt.Sprintf("%s[%v]", element, index)), p.value(p.encodeValue(value)))
 	}
 }
 
 func (p *displayFormatter) Value(value interface{}) {
-	p.write("%s%v", p.prefix(), p.encodeValue(value))
+	if p.collapsed != nil {
+		p.collapsed.addChild("value")
+		return
+	}
+	p.write("%s%s", p.prefix(), p.value(p.encodeValue(value)))
 }
 
 func (p *displayFormatter) PrintHeader() {
-	p.write("%s\n", strings.Repeat("*", 100))
+	p.write("%s\n", p.color(colorDim, strings.Repeat("*", 100)))
 }
 
 func (p *displayFormatter) PrintFooter() {
-	p.write("%s\n", strings.Repeat("*", 100))
+	p.write("%s\n", p.color(colorDim, strings.Repeat("*", 100)))
+	p.endRecord()
+}
+
+// printDecoded prints a decoded payload. Multi-line values are printed on separate lines
+// under the field name.
+func (p *displayFormatter) printDecoded(field string, value string) {
+	if !strings.Contains(value, "\n") {
+		p.write("%s%s: %s\n", p.prefix(), p.name(field), p.color(colorString, value))
+		return
+	}
+	p.write("%s%s:\n", p.prefix(), p.name(field))
+	p.indent++
+	for _, line := range strings.Split(value, "\n") {
+		p.write("%s%s\n", p.prefix(), p.color(colorString, line))
+	}
+	p.indent--
+}
+
+// collapse starts collapsing the given element if the maximum depth has been reached
+// (or if a parent element is already collapsed). Returns true if the element is collapsed.
+func (p *displayFormatter) collapse(element string) bool {
+	if p.collapsed != nil {
+		p.collapsed.addChild(element)
+		p.collapsed.level++
+		return true
+	}
+	if p.depth > 0 && p.indent >= p.depth {
+		p.collapsed = &collapsedNode{name: element}
+		return true
+	}
+	return false
+}
+
+// endCollapsed ends an element within a collapsed subtree. When the collapsed
+// element itself ends, a summary of its children is printed.
+func (p *displayFormatter) endCollapsed() {
+	if p.collapsed.level > 0 {
+		p.collapsed.level--
+		return
+	}
+
+	node := p.collapsed
+	p.collapsed = nil
+	if node.name != "" {
+		p.write("%s%s %s\n", p.prefix(), p.name(node.name), p.color(colorDim, node.summary()))
+	} else {
+		p.write("%s%s\n", p.prefix(), p.color(colorDim, node.summary()))
+	}
 }
 
 func (p *displayFormatter) prefix() string {
@@ -198,11 +320,44 @@ func (p *displayFormatter) prefix() string {
 	for i := 0; i < p.indent; i++ {
 		s = s + fmt.Sprintf("|%s", strings.Repeat(" ", indentSize-1))
 	}
-	return s
+	return p.color(colorDim, s)
 }
 
-func (p *displayFormatter) encodeValue(value interface{}) interface{} {
+func (p *displayFormatter) name(name string) string {
+	return p.color(colorName, name)
+}
+
+func (p *displayFormatter) value(value interface{}) string {
+	s := fmt.Sprintf("%v", value)
+	if !p.colorize {
+		return s
+	}
 	switch value.(type) {
+	case string:
+		return p.color(colorString, s)
+	case bool:
+		return p.color(colorBool, s)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return p.color(colorNumber, s)
+	default:
+		return s
+	}
+}
+
+func (p *displayFormatter) color(code, s string) string {
+	if !p.colorize || s == "" {
+		return s
+	}
+	return code + s + colorReset
+}
+
+func (p *displayFormatter) encodeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *Payload:
+		if p.decoder != nil {
+			return p.decoder.Decode(v)
+		}
+		return p.encodeValue(v.Data)
 	case []byte:
 		if p.base64Encode {
 			return Base64URLEncode(value.([]byte))
@@ -306,10 +461,13 @@ func (p *jsonFormatter) PrintFooter() {
 	p.ElementEnd()
 	p.write("\n")
 	p.commaRequired = false
+	p.endRecord()
 }
 
 func (p *jsonFormatter) encodeValue(value interface{}) interface{} {
-	switch value.(type) {
+	switch v := value.(type) {
+	case *Payload:
+		return Base64URLEncode(v.Data)
 	case []byte:
 		return Base64URLEncode(value.([]byte))
 	default:
@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bufferWriter struct {
+	bytes.Buffer
+}
+
+func (w *bufferWriter) Write(format string, a ...interface{}) error {
+	_, err := fmt.Fprintf(&w.Buffer, format, a...)
+	return err
+}
+
+func TestDisplayFormatterDepth(t *testing.T) {
+	w := &bufferWriter{}
+	f := NewFormatterWithOpts(DISPLAY, STDOUT, &FormatterOpts{Writer: w, Depth: 2})
+
+	// Elements at depth 2 and deeper are collapsed
+	f.Field("Number", 1)
+	f.Element("Config")
+	f.Field("Name", "org1")
+	f.Element("MSPConfig")
+	f.Field("Name", "Org1MSP")
+	f.Array("RootCerts")
+	f.ItemValue("Cert", 0, "cert0")
+	f.ArrayEnd()
+	f.Array("Admins")
+	f.Item("Admin", 0)
+	f.Field("Name", "admin")
+	f.ItemEnd()
+	f.ArrayEnd()
+	f.ElementEnd()
+	f.Array("Certs")
+	f.ItemValue("Cert", 0, "cert0")
+	f.ItemValue("Cert", 1, "cert1")
+	f.ArrayEnd()
+	f.ElementEnd()
+	f.Field("Last", true)
+
+	f.Element("Empty")
+	f.Element("Level1")
+	f.Element("Level2")
+	f.ElementEnd()
+	f.ElementEnd()
+	f.ElementEnd()
+
+	expected := strings.Join([]string{
+		"***** Number: 1",
+		"***** Config:",
+		"***** |  Name: org1",
+		"***** |  MSPConfig:",
+		"***** |  |  Name: Org1MSP",
+		"***** |  |  RootCerts {…1 Cert}",
+		"***** |  |  Admins {…1 Admin}",
+		"***** |  Certs:",
+		"***** |  |  Cert[0]: cert0",
+		"***** |  |  Cert[1]: cert1",
+		"***** Last: true",
+		"***** Empty:",
+		"***** |  Level1:",
+		"***** |  |  Level2 {}",
+		"",
+	}, "\n")
+	assert.Equal(t, expected, w.String())
+}
+
+func TestDisplayFormatterColor(t *testing.T) {
+	w := &bufferWriter{}
+	f := NewFormatterWithOpts(DISPLAY, STDOUT, &FormatterOpts{Writer: w, Color: true})
+
+	f.Field("Name", "org1")
+	f.Field("Count", 2)
+	assert.Equal(t, "\x1b[2m***** \x1b[0m\x1b[36mName\x1b[0m: \x1b[32morg1\x1b[0m\n"+
+		"\x1b[2m***** \x1b[0m\x1b[36mCount\x1b[0m: \x1b[33m2\x1b[0m\n", w.String())
+}
@@ -0,0 +1,208 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+)
+
+const (
+	// CertValid indicates that the current time is within the certificate's validity period
+	CertValid = "VALID"
+
+	// CertExpired indicates that the certificate has expired
+	CertExpired = "EXPIRED"
+
+	// CertNotYetValid indicates that the certificate's validity period has not yet started
+	CertNotYetValid = "NOT YET VALID"
+)
+
+// nodeOURoles are the default organizational units used by Fabric to classify identities (NodeOUs)
+var nodeOURoles = []string{"client", "peer", "admin", "orderer"}
+
+// now returns the current time (overridden in unit tests)
+var now = time.Now
+
+// PrintSerializedIdentity prints a serialized identity (e.g. the creator of a transaction or
+// an endorser). If the identity can't be decoded then the raw bytes are printed.
+func (p *BlockPrinter) PrintSerializedIdentity(identity []byte) {
+	sid := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(identity, sid); err != nil || sid.Mspid == "" {
+		p.Field("Raw", identity)
+		return
+	}
+
+	p.Field("MSPID", sid.Mspid)
+	p.Element("Certificate")
+	p.PrintCertificate(sid.IdBytes)
+	p.ElementEnd()
+}
+
+// PrintCertificate prints the details of an X.509 certificate which may be PEM or DER encoded.
+// If the certificate can't be parsed then the raw bytes are printed.
+func (p *BlockPrinter) PrintCertificate(certBytes []byte) {
+	cert, err := parseCertificate(certBytes)
+	if err != nil {
+		p.Field("Raw", certBytes)
+		return
+	}
+
+	p.Field("Subject", cert.Subject.String())
+	p.Field("Issuer", cert.Issuer.String())
+	p.Field("SerialNumber", cert.SerialNumber.String())
+	p.Field("NotBefore", cert.NotBefore.UTC().Format(time.RFC3339))
+	p.Field("NotAfter", cert.NotAfter.UTC().Format(time.RFC3339))
+	p.Field("Status", certStatus(cert, now()))
+	p.Field("KeyAlgorithm", keyAlgorithm(cert))
+	p.Field("SignatureAlgorithm", cert.SignatureAlgorithm.String())
+
+	if sans := subjectAltNames(cert); len(sans) > 0 {
+		p.Array("SANs")
+		for i, san := range sans {
+			p.ItemValue("SAN", i, san)
+		}
+		p.ArrayEnd()
+	}
+
+	if len(cert.Subject.OrganizationalUnit) > 0 {
+		p.Array("OUs")
+		for i, ou := range cert.Subject.OrganizationalUnit {
+			p.ItemValue("OU", i, ou)
+		}
+		p.ArrayEnd()
+	}
+	if role := certRole(cert); role != "" {
+		p.Field("Role", role)
+	}
+
+	p.Field("Fingerprint", fingerprint(cert))
+}
+
+// PrintCertificates prints an array of certificates
+func (p *BlockPrinter) PrintCertificates(element string, certs [][]byte) {
+	if len(certs) == 0 {
+		return
+	}
+	p.Array(element)
+	for i, cert := range certs {
+		p.Item("Cert", i)
+		p.PrintCertificate(cert)
+		p.ItemEnd()
+	}
+	p.ArrayEnd()
+}
+
+// PrintOUIdentifier prints a FabricOUIdentifier
+func (p *BlockPrinter) PrintOUIdentifier(ouID *msp.FabricOUIdentifier) {
+	p.Field("OrganizationalUnitIdentifier", ouID.OrganizationalUnitIdentifier)
+	if len(ouID.Certificate) > 0 {
+		p.Element("Certificate")
+		p.PrintCertificate(ouID.Certificate)
+		p.ElementEnd()
+	}
+}
+
+// PrintNodeOUs prints FabricNodeOUs
+func (p *BlockPrinter) PrintNodeOUs(nodeOUs *msp.FabricNodeOUs) {
+	p.Field("Enable", nodeOUs.Enable)
+	p.printNodeOU("ClientOUIdentifier", nodeOUs.ClientOuIdentifier)
+	p.printNodeOU("PeerOUIdentifier", nodeOUs.PeerOuIdentifier)
+	p.printNodeOU("AdminOUIdentifier", nodeOUs.AdminOuIdentifier)
+	p.printNodeOU("OrdererOUIdentifier", nodeOUs.OrdererOuIdentifier)
+}
+
+func (p *BlockPrinter) printNodeOU(element string, ouID *msp.FabricOUIdentifier) {
+	if ouID == nil {
+		return
+	}
+	p.Element(element)
+	p.PrintOUIdentifier(ouID)
+	p.ElementEnd()
+}
+
+// parseCertificate parses a PEM or DER encoded X.509 certificate
+func parseCertificate(certBytes []byte) (*x509.Certificate, error) {
+	if block, _ := pem.Decode(certBytes); block != nil {
+		certBytes = block.Bytes
+	}
+	return x509.ParseCertificate(certBytes)
+}
+
+func certStatus(cert *x509.Certificate, t time.Time) string {
+	switch {
+	case t.Before(cert.NotBefore):
+		return CertNotYetValid
+	case t.After(cert.NotAfter):
+		return CertExpired
+	default:
+		return CertValid
+	}
+}
+
+func keyAlgorithm(cert *x509.Certificate) string {
+	switch key := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", key.Curve.Params().Name)
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", key.N.BitLen())
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return cert.PublicKeyAlgorithm.String()
+	}
+}
+
+func subjectAltNames(cert *x509.Certificate) []string {
+	var sans []string
+	for _, name := range cert.DNSNames {
+		sans = append(sans, "DNS:"+name)
+	}
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, "IP:"+ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		sans = append(sans, "email:"+email)
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, "URI:"+uri.String())
+	}
+	return sans
+}
+
+// certRole returns the Fabric role (client, peer, admin or orderer) of the certificate according
+// to the default NodeOU identifiers or an empty string if the certificate has no such OU
+func certRole(cert *x509.Certificate) string {
+	for _, ou := range cert.Subject.OrganizationalUnit {
+		for _, role := range nodeOURoles {
+			if strings.EqualFold(ou, role) {
+				return role
+			}
+		}
+	}
+	return ""
+}
+
+// fingerprint returns the SHA-256 fingerprint of the certificate, e.g. 3A:0F:...
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	s := make([]string, len(sum))
+	for i, b := range sum {
+		s[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(s, ":")
+}
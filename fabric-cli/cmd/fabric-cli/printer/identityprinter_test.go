@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSerializedIdentity(t *testing.T) {
+	notBefore := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC)
+	certPEM := newTestCert(t, notBefore, notAfter)
+
+	sid, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: certPEM})
+	require.NoError(t, err)
+
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	w := &bufferWriter{}
+	p := NewBlockPrinterWithOpts(DISPLAY, STDOUT, &FormatterOpts{Writer: w})
+	p.PrintSerializedIdentity(sid)
+
+	out := w.String()
+	assert.Contains(t, out, "MSPID: Org1MSP")
+	assert.Contains(t, out, "Subject: CN=peer0.org1.example.com,OU=peer,O=org1.example.com")
+	assert.Contains(t, out, "NotAfter: 2028-01-01T00:00:00Z")
+	assert.Contains(t, out, "Status: VALID")
+	assert.Contains(t, out, "KeyAlgorithm: ECDSA P-256")
+	assert.Contains(t, out, "SAN[0]: DNS:peer0.org1.example.com")
+	assert.Contains(t, out, "SAN[1]: IP:127.0.0.1")
+	assert.Contains(t, out, "Role: peer")
+	assert.Regexp(t, "Fingerprint: ([0-9A-F]{2}:){31}[0-9A-F]{2}", out)
+
+	// Invalid identities are printed raw
+	w.Reset()
+	p.PrintSerializedIdentity([]byte("invalid"))
+	assert.Equal(t, "***** Raw: invalid\n", w.String())
+}
+
+func TestCertStatus(t *testing.T) {
+	notBefore := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert, err := parseCertificate(newTestCert(t, notBefore, notAfter))
+	require.NoError(t, err)
+
+	assert.Equal(t, CertNotYetValid, certStatus(cert, notBefore.Add(-time.Hour)))
+	assert.Equal(t, CertValid, certStatus(cert, notBefore.Add(time.Hour)))
+	assert.Equal(t, CertExpired, certStatus(cert, notAfter.Add(time.Hour)))
+}
+
+func newTestCert(t *testing.T, notBefore, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1234),
+		Subject: pkix.Name{
+			CommonName:         "peer0.org1.example.com",
+			Organization:       []string{"org1.example.com"},
+			OrganizationalUnit: []string{"peer"},
+		},
+		NotBefore:   notBefore,
+		NotAfter:    notAfter,
+		DNSNames:    []string{"peer0.org1.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
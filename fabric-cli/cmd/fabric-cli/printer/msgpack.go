@@ -0,0 +1,58 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type msgPackDecoder struct {
+}
+
+// Decode decodes a single MessagePack value. Extension types other than timestamps aren't supported.
+func (d *msgPackDecoder) Decode(data []byte) (string, error) {
+	r := bytes.NewReader(data)
+	dec := msgpack.NewDecoder(r)
+	dec.SetMapDecoder(decodeMsgPackMap)
+
+	value, err := dec.DecodeInterface()
+	if err != nil {
+		return "", errors.Wrap(err, "invalid MessagePack")
+	}
+	if r.Len() > 0 {
+		return "", errors.Errorf("invalid MessagePack: %d unexpected trailing bytes", r.Len())
+	}
+	return formatValue(value), nil
+}
+
+// decodeMsgPackMap decodes a map into entries so that the order of the encoded map is preserved
+func decodeMsgPackMap(dec *msgpack.Decoder) (interface{}, error) {
+	n, err := dec.DecodeMapLen()
+	if err != nil {
+		return nil, err
+	}
+	if n == -1 {
+		return nil, nil
+	}
+
+	entries := make([]mapEntry, 0, n)
+	for i := 0; i < n; i++ {
+		key, err := dec.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		value, err := dec.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapEntry{key: key, value: value})
+	}
+	return entries, nil
+}
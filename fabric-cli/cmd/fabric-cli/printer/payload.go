@@ -0,0 +1,229 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+)
+
+const (
+	// maxWireDepth is the maximum depth of nested messages that are decoded in a generic protobuf dump
+	maxWireDepth = 16
+
+	// wireIndent is the indentation of nested messages in a generic protobuf dump
+	wireIndent = "  "
+)
+
+// Payload is a binary value produced by a chaincode (i.e. a KV write value, a chaincode
+// response payload or a chaincode event payload). When payload decoding is enabled, the
+// content of the payload is detected and the payload is displayed in a human readable form.
+type Payload struct {
+	// ChaincodeID is the ID of the chaincode that produced the payload (may be empty if unknown)
+	ChaincodeID string
+	// Key is the key of the KV write (only applies to KV writes)
+	Key string
+	// Data contains the raw bytes of the payload
+	Data []byte
+}
+
+// NewPayload returns a new Payload
+func NewPayload(ccID, key string, data []byte) *Payload {
+	return &Payload{ChaincodeID: ccID, Key: key, Data: data}
+}
+
+// payloadDecoder detects the content of a payload and decodes it into a human readable form.
+// The following are attempted (in order):
+// - The decoder registered for the chaincode and key (if any)
+// - A protobuf message of the type registered for the chaincode (if any)
+// - UTF-8 text (JSON is pretty-printed)
+// - A generic protobuf message (similar to protoc --decode_raw)
+// - A hex dump
+type payloadDecoder struct {
+	messageTypes map[string]string
+	registry     *DecoderRegistry
+	logger       *logging.Logger
+}
+
+func newPayloadDecoder(messageTypes map[string]string, registry *DecoderRegistry, logger *logging.Logger) *payloadDecoder {
+	if logger == nil {
+		logger = logging.NewLogger(loggerName)
+	}
+	return &payloadDecoder{messageTypes: messageTypes, registry: registry, logger: logger}
+}
+
+// Decode returns the decoded payload
+func (d *payloadDecoder) Decode(payload *Payload) string {
+	data := payload.Data
+	if len(data) == 0 {
+		return ""
+	}
+
+	if decoder := d.registry.Decoder(payload.ChaincodeID, payload.Key); decoder != nil {
+		s, err := decoder.Decode(data)
+		if err == nil {
+			return s
+		}
+		d.logger.Warnf("Error decoding payload of chaincode [%s], key [%s]: %s", payload.ChaincodeID, payload.Key, err)
+	}
+
+	if msgType, ok := d.messageTypes[payload.ChaincodeID]; ok {
+		if s, err := decodeProtoMessage(msgType, data); err == nil {
+			return s
+		}
+	}
+
+	if isPrintable(data) {
+		var out bytes.Buffer
+		if json.Valid(data) && json.Indent(&out, data, "", "  ") == nil {
+			return out.String()
+		}
+		return string(data)
+	}
+
+	if fields, err := decodeWire(data, 0); err == nil {
+		var out bytes.Buffer
+		writeWireFields(&out, fields, 0)
+		return strings.TrimSuffix(out.String(), "\n")
+	}
+
+	return strings.TrimSuffix(hex.Dump(data), "\n")
+}
+
+// decodeProtoMessage unmarshals the data into a registered protobuf message of the given type
+// (e.g. "common.Envelope") and returns the message in text format
+func decodeProtoMessage(msgType string, data []byte) (string, error) {
+	t := proto.MessageType(msgType)
+	if t == nil {
+		return "", fmt.Errorf("protobuf message type not registered: %s", msgType)
+	}
+	msg, ok := reflect.New(t.Elem()).Interface().(proto.Message)
+	if !ok {
+		return "", fmt.Errorf("type is not a protobuf message: %s", msgType)
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(proto.MarshalTextString(msg), "\n"), nil
+}
+
+// isPrintable returns true if the data is valid UTF-8 and contains only printable characters and whitespace
+func isPrintable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// wireField is a field decoded from the protobuf wire format without a schema
+type wireField struct {
+	number   uint64
+	wireType uint64
+	value    uint64
+	bytes    []byte
+	fields   []*wireField
+}
+
+// decodeWire decodes the data as a protobuf message without a schema. An error is returned if
+// the data is not a well formed message (all bytes must be consumed).
+func decodeWire(data []byte, depth int) ([]*wireField, error) {
+	var fields []*wireField
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid tag")
+		}
+		data = data[n:]
+
+		field := &wireField{number: tag >> 3, wireType: tag & 0x7}
+		if field.number == 0 || field.number > math.MaxInt32>>3 {
+			return nil, fmt.Errorf("invalid field number: %d", field.number)
+		}
+
+		switch field.wireType {
+		case wireVarint:
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				return nil, fmt.Errorf("invalid varint")
+			}
+			field.value = v
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return nil, fmt.Errorf("invalid fixed64")
+			}
+			field.value = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return nil, fmt.Errorf("invalid fixed32")
+			}
+			field.value = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		case wireBytes:
+			l, n := binary.Uvarint(data)
+			if n <= 0 || l > uint64(len(data)-n) {
+				return nil, fmt.Errorf("invalid length")
+			}
+			field.bytes = data[n : n+int(l)]
+			data = data[n+int(l):]
+			if depth < maxWireDepth && len(field.bytes) > 0 && !isPrintable(field.bytes) {
+				if nested, err := decodeWire(field.bytes, depth+1); err == nil {
+					field.fields = nested
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported wire type: %d", field.wireType)
+		}
+
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func writeWireFields(out *bytes.Buffer, fields []*wireField, indent int) {
+	prefix := strings.Repeat(wireIndent, indent)
+	for _, field := range fields {
+		switch {
+		case field.fields != nil:
+			fmt.Fprintf(out, "%s%d {\n", prefix, field.number)
+			writeWireFields(out, field.fields, indent+1)
+			fmt.Fprintf(out, "%s}\n", prefix)
+		case field.wireType == wireBytes:
+			fmt.Fprintf(out, "%s%d: %q\n", prefix, field.number, field.bytes)
+		case field.wireType == wireFixed64:
+			fmt.Fprintf(out, "%s%d: 0x%016x\n", prefix, field.number, field.value)
+		case field.wireType == wireFixed32:
+			fmt.Fprintf(out, "%s%d: 0x%08x\n", prefix, field.number, field.value)
+		default:
+			fmt.Fprintf(out, "%s%d: %d\n", prefix, field.number, field.value)
+		}
+	}
+}
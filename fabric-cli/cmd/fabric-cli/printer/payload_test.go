@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeEmptyPayload(t *testing.T) {
+	d := newPayloadDecoder(nil, nil, nil)
+	assert.Equal(t, "", d.Decode(NewPayload("examplecc", "", nil)))
+}
+
+func TestDecodeTextPayload(t *testing.T) {
+	d := newPayloadDecoder(nil, nil, nil)
+	assert.Equal(t, "hello world", d.Decode(NewPayload("examplecc", "", []byte("hello world"))))
+	assert.Equal(t, "100", d.Decode(NewPayload("examplecc", "", []byte("100"))))
+}
+
+func TestDecodeJSONPayload(t *testing.T) {
+	d := newPayloadDecoder(nil, nil, nil)
+	assert.Equal(t, "{\n  \"a\": 1,\n  \"b\": [\n    \"x\"\n  ]\n}", d.Decode(NewPayload("examplecc", "", []byte(`{"a":1,"b":["x"]}`))))
+}
+
+func TestDecodeRegisteredProtoPayload(t *testing.T) {
+	data, err := proto.Marshal(&common.Header{ChannelHeader: []byte("ch"), SignatureHeader: []byte{0x01, 0xff}})
+	assert.NoError(t, err)
+
+	d := newPayloadDecoder(map[string]string{"examplecc": "common.Header"}, nil, nil)
+	result := d.Decode(NewPayload("examplecc", "", data))
+	assert.Contains(t, result, `channel_header: "ch"`)
+	assert.Contains(t, result, `signature_header: "\001\377"`)
+
+	// Unknown message types fall back to content detection
+	d = newPayloadDecoder(map[string]string{"examplecc": "unknown.Type"}, nil, nil)
+	assert.Equal(t, "1: \"ch\"\n2: \"\\x01\\xff\"", d.Decode(NewPayload("examplecc", "", data)))
+}
+
+func TestDecodeGenericProtoPayload(t *testing.T) {
+	inner, err := proto.Marshal(&common.BlockHeader{Number: 150, DataHash: []byte{0x00, 0x01}})
+	assert.NoError(t, err)
+	data, err := proto.Marshal(&common.Block{Header: &common.BlockHeader{Number: 1}, Data: &common.BlockData{Data: [][]byte{inner}}})
+	assert.NoError(t, err)
+
+	d := newPayloadDecoder(nil, nil, nil)
+	expected := strings.Join([]string{
+		"1 {",
+		"  1: 1",
+		"}",
+		"2 {",
+		"  1 {",
+		"    1: 150",
+		"    3: \"\\x00\\x01\"",
+		"  }",
+		"}",
+	}, "\n")
+	assert.Equal(t, expected, d.Decode(NewPayload("examplecc", "", data)))
+}
+
+func TestDecodeBinaryPayload(t *testing.T) {
+	d := newPayloadDecoder(nil, nil, nil)
+	result := d.Decode(NewPayload("examplecc", "", []byte{0xff, 0xfe, 0x00}))
+	assert.Equal(t, "00000000  ff fe 00                                          |...|", result)
+}
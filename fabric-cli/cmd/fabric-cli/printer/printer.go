@@ -29,7 +29,7 @@ func newPrinterWithOpts(format OutputFormat, writerType WriterType, opts *Format
 // Print prints a formatted string
 func (p *printer) Print(frmt string, vars ...interface{}) {
 	if p.Formatter == nil {
-		fmt.Printf(frmt, vars)
+		fmt.Printf(frmt, vars...)
 		return
 	}
 	p.Formatter.Print(frmt, vars...)
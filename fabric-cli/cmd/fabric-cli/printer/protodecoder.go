@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package printer
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// registeredProtoDecoder decodes payloads using a message type that's registered
+// with the protobuf library (e.g. common.Envelope)
+type registeredProtoDecoder struct {
+	msgType string
+}
+
+func newRegisteredProtoDecoder(msgType string) *registeredProtoDecoder {
+	return &registeredProtoDecoder{msgType: msgType}
+}
+
+func (d *registeredProtoDecoder) Decode(data []byte) (string, error) {
+	return decodeProtoMessage(d.msgType, data)
+}
+
+// descriptorProtoDecoder decodes payloads using a message type from a protobuf
+// descriptor set. The output is in protobuf text format.
+type descriptorProtoDecoder struct {
+	message protoreflect.MessageDescriptor
+}
+
+func newDescriptorProtoDecoder(path, message string) (*descriptorProtoDecoder, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading descriptor set [%s]", path)
+	}
+
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(raw, fds); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshalling descriptor set [%s]", path)
+	}
+
+	return newDescriptorProtoDecoderFromSet(fds, message)
+}
+
+func newDescriptorProtoDecoderFromSet(fds *descriptorpb.FileDescriptorSet, message string) (*descriptorProtoDecoder, error) {
+	files, err := protodesc.NewFiles(fds)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid descriptor set")
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(strings.TrimPrefix(message, ".")))
+	if err != nil {
+		return nil, errors.Errorf("message type [%s] not found in descriptor set", message)
+	}
+	msgDesc, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, errors.Errorf("[%s] is not a message type", message)
+	}
+
+	return &descriptorProtoDecoder{message: msgDesc}, nil
+}
+
+func (d *descriptorProtoDecoder) Decode(data []byte) (string, error) {
+	msg := dynamicpb.NewMessage(d.message)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return "", errors.Wrapf(err, "invalid %s", d.message.FullName())
+	}
+
+	out, err := prototext.MarshalOptions{Multiline: true, Indent: wireIndent, EmitUnknown: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
@@ -100,3 +100,20 @@ func GetChaincodeActionPayload(capBytes []byte) (*peer.ChaincodeActionPayload, e
 	err := proto.Unmarshal(capBytes, cap)
 	return cap, errors.Wrap(err, "error unmarshaling ChaincodeActionPayload")
 }
+
+// chaincodeIDFromProposalResponse returns the name of the chaincode that produced the given
+// proposal response or an empty string if the name could not be determined
+func chaincodeIDFromProposalResponse(response *peer.ProposalResponse) string {
+	if response == nil {
+		return ""
+	}
+	prp := &peer.ProposalResponsePayload{}
+	if err := proto.Unmarshal(response.Payload, prp); err != nil {
+		return ""
+	}
+	chaincodeAction := &peer.ChaincodeAction{}
+	if err := proto.Unmarshal(prp.Extension, chaincodeAction); err != nil {
+		return ""
+	}
+	return chaincodeAction.ChaincodeId.GetName()
+}
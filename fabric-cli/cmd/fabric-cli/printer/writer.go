@@ -11,7 +11,7 @@ import (
 	"os"
 	"strings"
 
-	config "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 )
 
 // WriterType specifies the format for printing data
@@ -26,12 +26,20 @@ const (
 
 	// LOG writes to the logger
 	LOG
+
+	// FILE writes to a file (see NewFileWriter)
+	FILE
 )
 
 const (
 	stdout = "stdout"
 	stderr = "stderr"
 	log    = "log"
+	file   = "file"
+
+	filePrefix = file + ":"
+
+	loggerName = "fabriccli"
 )
 
 func (f WriterType) String() string {
@@ -42,13 +50,19 @@ func (f WriterType) String() string {
 		return stderr
 	case LOG:
 		return log
+	case FILE:
+		return file
 	default:
 		return "unknown"
 	}
 }
 
-// AsWriterType returns the WriterType given a Writer Type string
+// AsWriterType returns the WriterType given a Writer Type string. A file
+// writer is specified as file:<path>, e.g. file:/tmp/blocks.json
 func AsWriterType(t string) WriterType {
+	if strings.HasPrefix(strings.ToLower(t), filePrefix) {
+		return FILE
+	}
 	switch strings.ToLower(t) {
 	case log:
 		return LOG
@@ -59,18 +73,32 @@ func AsWriterType(t string) WriterType {
 	}
 }
 
+// WriterPath returns the path of the file given a file Writer Type string (file:<path>),
+// or an empty string if the writer is not a file writer
+func WriterPath(t string) string {
+	if !strings.HasPrefix(strings.ToLower(t), filePrefix) {
+		return ""
+	}
+	return t[len(filePrefix):]
+}
+
 // Writer writes the output
 type Writer interface {
 	Write(format string, a ...interface{}) error
 }
 
-// NewWriter returns a new writer given the writer type
+// NewWriter returns a new writer given the writer type. A FILE writer requires
+// a path and is created with NewFileWriter.
 func NewWriter(writerType WriterType) Writer {
+	return newWriter(writerType, logging.NewLogger(loggerName))
+}
+
+func newWriter(writerType WriterType, logger *logging.Logger) Writer {
 	switch writerType {
 	case STDERR:
 		return &stdErrWriter{}
 	case LOG:
-		return &logWriter{}
+		return &logWriter{logger: logger}
 	default:
 		return &stdOutWriter{}
 	}
@@ -93,9 +121,10 @@ func (w *stdErrWriter) Write(format string, a ...interface{}) error {
 }
 
 type logWriter struct {
+	logger *logging.Logger
 }
 
 func (w *logWriter) Write(format string, a ...interface{}) error {
-	config.Config().Logger().Infof(format, a...)
+	w.logger.Infof(format, a...)
 	return nil
 }
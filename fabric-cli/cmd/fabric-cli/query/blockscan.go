@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	fabricCommon "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/archive"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/printer"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/txfilter"
+	"github.com/spf13/pflag"
+)
+
+// initScanAction initializes an action that scans blocks. If --input is given then the blocks
+// are read from disk and the action doesn't connect to the network.
+func initScanAction(a *action.Action, cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) error {
+	if cfg.Input() != "" {
+		return a.InitializeOffline(cfg, flags)
+	}
+	return a.Initialize(cfg, flags)
+}
+
+// scanBlocks invokes fn with each of the blocks in the range given by --from and --to, in order. The blocks
+// are read from --input or else queried from the channel one at a time, up to the last block on the channel
+// if --to isn't specified. If the command is interrupted then the context's error is returned.
+func scanBlocks(a *action.Action, fn func(block *fabricCommon.Block) error) error {
+	if a.Config().Input() != "" {
+		return scanInput(a, fn)
+	}
+	return scanChannel(a, fn)
+}
+
+func scanInput(a *action.Action, fn func(block *fabricCommon.Block) error) error {
+	ctx := a.Config().Context()
+	return archive.ReadBlocks(a.Config().Input(), func(block *fabricCommon.Block) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		num := block.Header.Number
+		if num < a.Config().FromBlock() || (a.Config().IsFlagSpecified(cliconfig.ToBlockFlag) && num > a.Config().ToBlock()) {
+			return nil
+		}
+		return fn(block)
+	})
+}
+
+func scanChannel(a *action.Action, fn func(block *fabricCommon.Block) error) error {
+	ctx := a.Config().Context()
+
+	ledgerClient, err := a.LedgerClient()
+	if err != nil {
+		return err
+	}
+
+	from := a.Config().FromBlock()
+	to := a.Config().ToBlock()
+	if !a.Config().IsFlagSpecified(cliconfig.ToBlockFlag) {
+		info, err := ledgerClient.QueryInfo(ledger.WithParentContext(ctx))
+		if err != nil {
+			return errors.WithMessage(err, "error querying the height of the channel")
+		}
+		if info.BCI.Height == 0 {
+			return nil
+		}
+		to = info.BCI.Height - 1
+	}
+	if to < from {
+		return errors.Errorf("invalid block range [%d-%d]", from, to)
+	}
+
+	for num := from; num <= to; num++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		block, err := ledgerClient.QueryBlock(num, ledger.WithParentContext(ctx))
+		if err != nil {
+			return errors.WithMessagef(err, "error querying block %d", num)
+		}
+		if err := fn(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// txSummary returns the summary of the transaction that's printed
+func txSummary(tx *txfilter.Tx) *printer.TxSummary {
+	summary := &printer.TxSummary{
+		BlockNum:       tx.BlockNum,
+		TxNum:          tx.Index,
+		TxID:           tx.TxID,
+		Type:           tx.Type,
+		Timestamp:      tx.Timestamp,
+		CreatorMSPID:   tx.CreatorMSPID,
+		ChaincodeID:    tx.ChaincodeID,
+		Function:       tx.Function,
+		ValidationCode: tx.ValidationCode,
+		Envelope:       tx.Envelope,
+		DecodeErr:      tx.DecodeErr,
+	}
+	for _, w := range tx.Writes {
+		summary.Writes = append(summary.Writes, printer.KeyWrite{Namespace: w.Namespace, Key: w.Key, IsDelete: w.IsDelete, Value: w.Value})
+	}
+	return summary
+}
@@ -11,106 +11,76 @@ import (
 	"strings"
 
 	fabricCommon "github.com/hyperledger/fabric-protos-go/common"
-	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
 	"github.com/pkg/errors"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-var queryBlockCmd = &cobra.Command{
-	Use:   "block",
-	Short: "Query block",
-	Long:  "Queries a block",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newQueryBlockAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing queryBlockAction: %v", err)
-			return
-		}
-
-		defer action.Terminate()
-
-		err = action.invoke()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running queryBlockAction: %v", err)
-		}
-	},
-}
+func getQueryBlockCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryBlockCmd := &cobra.Command{
+		Use:   "block",
+		Short: "Query block",
+		Long:  "Queries a block",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newQueryBlockAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryBlockAction: %v", err)
+				return
+			}
+
+			defer action.Close()
+
+			err = action.invoke()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryBlockAction: %v", err)
+			}
+		},
+	}
 
-func getQueryBlockCmd() *cobra.Command {
 	flags := queryBlockCmd.Flags()
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitBlockNum(flags)
-	cliconfig.InitBlockHash(flags)
-	cliconfig.InitTraverse(flags)
-	cliconfig.InitPeerURL(flags, "", "The URL of the peer on which to install the chaincode, e.g. grpcs://localhost:7051")
+	cfg.InitChannelID(flags)
+	cfg.InitBlockNum(flags)
+	cfg.InitBlockHash(flags)
+	cfg.InitTraverse(flags)
+	cfg.InitPeerURL(flags, "", "The URL of the peer on which to install the chaincode, e.g. grpcs://localhost:7051")
 	return queryBlockCmd
 }
 
 type queryBlockAction struct {
-	action.Action
+	*fabriccli.Client
 }
 
-func newQueryBlockAction(flags *pflag.FlagSet) (*queryBlockAction, error) {
-	action := &queryBlockAction{}
-	err := action.Initialize(flags)
-	return action, err
+func newQueryBlockAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryBlockAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &queryBlockAction{Client: client}, nil
 }
 
 func (a *queryBlockAction) invoke() error {
-	ledgerClient, err := a.LedgerClient()
-	if err != nil {
-		return errors.Errorf("Error getting admin channel client: %v", err)
+	req := fabriccli.QueryBlockRequest{
+		Traverse: a.Config().Traverse(),
+		OnBlock: func(block *fabricCommon.Block) {
+			a.Printer().PrintBlock(block)
+		},
 	}
-
-	var block *fabricCommon.Block
-	if cliconfig.IsFlagSet(cliconfig.BlockNumFlag) {
-		var err error
-		block, err = ledgerClient.QueryBlock(cliconfig.Config().BlockNum())
-		if err != nil {
-			return err
-		}
-	} else if cliconfig.IsFlagSet(cliconfig.BlockHashFlag) {
-		var err error
-
-		hashBytes, err := Base64URLDecode(cliconfig.Config().BlockHash())
-		if err != nil {
-			return err
-		}
-
-		block, err = ledgerClient.QueryBlockByHash(hashBytes)
+	if a.Config().IsFlagSpecified(cliconfig.BlockNumFlag) {
+		req.Number = a.Config().BlockNum()
+	} else if a.Config().IsFlagSpecified(cliconfig.BlockHashFlag) {
+		hashBytes, err := Base64URLDecode(a.Config().BlockHash())
 		if err != nil {
 			return err
 		}
+		req.Hash = hashBytes
 	} else {
 		return errors.Errorf("must specify either a block number of a block hash")
 	}
 
-	a.Printer().PrintBlock(block)
-
-	a.traverse(ledgerClient, block, cliconfig.Config().Traverse()-1)
-
-	return nil
-}
-
-func (a *queryBlockAction) traverse(ledgerClient *ledger.Client, currentBlock *fabricCommon.Block, num int) error {
-	if num <= 0 {
-		return nil
-	}
-
-	block, err := ledgerClient.QueryBlockByHash(currentBlock.Header.PreviousHash)
-	if err != nil {
-		return err
-	}
-
-	a.Printer().PrintBlock(block)
-
-	if block.Header.PreviousHash != nil {
-		return a.traverse(ledgerClient, block, num-1)
-	}
-	return nil
+	_, err := a.QueryBlock(a.Config().Context(), req)
+	return err
 }
 
 // Base64URLDecode decodes the base64 string into a byte array
@@ -0,0 +1,295 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	fabricCommon "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/archive"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/executor"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/printer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getQueryBlocksCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryBlocksCmd := &cobra.Command{
+		Use:   "blocks",
+		Short: "Export a range of blocks",
+		Long:  "Queries a range of blocks concurrently and writes each block to a file in the output directory, or to a gzipped tar archive if the output ends with .tar.gz or .tgz. Blocks that were already exported are skipped so that an interrupted export may be resumed by running the same command again.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChannelID() == "" {
+				fmt.Printf("\nMust specify channel ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			if cfg.Output() == "" {
+				fmt.Printf("\nMust specify the output directory or archive\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newQueryBlocksAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryBlocksAction: %v", err)
+				return
+			}
+			defer action.Close()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryBlocksAction: %v", err)
+			}
+		},
+	}
+
+	flags := queryBlocksCmd.Flags()
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitFromBlock(flags)
+	cfg.InitToBlock(flags)
+	cfg.InitBlockFormat(flags)
+	cfg.InitConcurrency(flags, "10", "The number of blocks that are queried concurrently")
+	cfg.InitOutput(flags, "", "The directory to which the blocks are written, or the path of a .tar.gz/.tgz archive")
+	return queryBlocksCmd
+}
+
+type queryBlocksAction struct {
+	*fabriccli.Client
+}
+
+func newQueryBlocksAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryBlocksAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &queryBlocksAction{Client: client}, nil
+}
+
+func (a *queryBlocksAction) run() error {
+	format, err := archive.AsFormat(a.Config().BlockFormat())
+	if err != nil {
+		return err
+	}
+
+	output := a.Config().Output()
+	dirPath := output
+	if archive.IsArchive(output) {
+		if _, err := os.Stat(output); err == nil {
+			return errors.Errorf("archive [%s] already exists", output)
+		}
+		// Blocks are staged in a directory so that the export may be resumed if it's interrupted
+		dirPath = archive.StagingDir(output)
+	}
+
+	dir, err := archive.NewDir(dirPath, format)
+	if err != nil {
+		return err
+	}
+
+	ctx := a.Config().Context()
+
+	from := a.Config().FromBlock()
+	to := a.Config().ToBlock()
+	if !a.Config().IsFlagSpecified(cliconfig.ToBlockFlag) {
+		info, err := a.QueryInfo(ctx)
+		if err != nil {
+			return errors.WithMessage(err, "error querying the height of the channel")
+		}
+		if info.BCI.Height == 0 {
+			return errors.New("there are no blocks on the channel")
+		}
+		to = info.BCI.Height - 1
+	}
+	if to < from {
+		return errors.Errorf("invalid block range [%d-%d]", from, to)
+	}
+
+	reader, err := a.NewBlockReader()
+	if err != nil {
+		return err
+	}
+
+	total := to - from + 1
+	missing := dir.Missing(from, to)
+	if skipped := total - uint64(len(missing)); skipped > 0 {
+		fmt.Printf("*** %d of %d blocks were already exported to [%s]\n", skipped, total, dirPath)
+	}
+
+	concurrency := a.Config().Concurrency()
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	executor := executor.NewConcurrent(a.Config(), "Export Blocks", concurrency)
+	executor.Start()
+	defer executor.Stop(true)
+
+	var errs []error
+	var mutex sync.RWMutex
+	var wg sync.WaitGroup
+	var numExported int
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(3 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				mutex.RLock()
+				fmt.Printf("*** %d of %d blocks exported, %d failed\n", numExported, len(missing), len(errs))
+				mutex.RUnlock()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	startTime := time.Now()
+	for _, num := range missing {
+		if ctx.Err() != nil {
+			break
+		}
+
+		t := &exportBlockTask{
+			num:    num,
+			export: func(ctx context.Context, num uint64) error { return a.exportBlock(ctx, reader, dir, num) },
+			completed: func(exported bool, err error) {
+				defer wg.Done()
+				mutex.Lock()
+				defer mutex.Unlock()
+				if err != nil {
+					errs = append(errs, err)
+					return
+				}
+				if exported {
+					numExported++
+				}
+			},
+		}
+
+		wg.Add(1)
+		if err := executor.Submit(ctx, t); err != nil {
+			wg.Done()
+			if ctx.Err() != nil {
+				break
+			}
+			return errors.Errorf("error submitting task: %s", err)
+		}
+	}
+
+	// Wait for all tasks to complete. Tasks that were queued when the command
+	// was interrupted complete without being started.
+	wg.Wait()
+	close(done)
+
+	if ctx.Err() != nil {
+		fmt.Printf("\n*** Interrupted after exporting %d of %d blocks. Run the command again to resume the export.\n", numExported, len(missing))
+		return nil
+	}
+
+	if len(errs) > 0 {
+		fmt.Printf("\n*** %d errors exporting blocks:\n", len(errs))
+		for _, err := range errs {
+			fmt.Printf("%s\n", err)
+		}
+		return errors.Errorf("%d of %d blocks were not exported. Run the command again to resume the export.", len(errs), len(missing))
+	}
+
+	if dirPath != output {
+		if err := dir.Pack(output); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(dirPath); err != nil {
+			return errors.Wrapf(err, "error removing staging directory [%s]", dirPath)
+		}
+	}
+
+	a.Printer().Print("Exported blocks %d-%d (%d queried) in %2.2fs to [%s]\n",
+		from, to, numExported, time.Since(startTime).Seconds(), output)
+
+	return nil
+}
+
+// exportBlock queries the block with the given number and writes it in each of the formats of the directory
+func (a *queryBlocksAction) exportBlock(ctx context.Context, reader *fabriccli.BlockReader, dir *archive.Dir, num uint64) error {
+	block, err := reader.Block(ctx, num)
+	if err != nil {
+		return err
+	}
+
+	for _, ext := range dir.Format().Extensions() {
+		var data []byte
+		if ext == archive.JSONExt {
+			data = a.blockJSON(block)
+		} else {
+			data, err = proto.Marshal(block)
+			if err != nil {
+				return errors.Wrapf(err, "error marshalling block %d", num)
+			}
+		}
+
+		if err := dir.Write(num, ext, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockJSON returns the block as printed by the JSON formatter. A printer is created
+// for each block since printers may not be used concurrently.
+func (a *queryBlocksAction) blockJSON(block *fabricCommon.Block) []byte {
+	buf := &bytes.Buffer{}
+	printer.NewBlockPrinterWithOpts(printer.JSON, printer.STDOUT, &printer.FormatterOpts{
+		Base64Encode:   a.Config().Base64(),
+		DecodePayloads: a.Config().DecodePayloads(),
+		MessageTypes:   a.Config().MessageTypes(),
+		Writer:         &bufferWriter{buf: buf},
+		Logger:         a.Config().Logger(),
+	}).PrintBlock(block)
+	return buf.Bytes()
+}
+
+// exportBlockTask exports a single block. The completed callback is invoked with
+// exported set to false if the task was cancelled before it started.
+type exportBlockTask struct {
+	num       uint64
+	export    func(ctx context.Context, num uint64) error
+	completed func(exported bool, err error)
+}
+
+// Invoke exports the block
+func (t *exportBlockTask) Invoke(ctx context.Context) {
+	if ctx.Err() != nil {
+		// The command was interrupted so the block isn't exported
+		t.completed(false, nil)
+		return
+	}
+
+	err := t.export(ctx, t.num)
+	t.completed(err == nil, err)
+}
+
+// bufferWriter is a printer.Writer that writes to a buffer
+type bufferWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w *bufferWriter) Write(format string, a ...interface{}) error {
+	_, err := fmt.Fprintf(w.buf, format, a...)
+	return err
+}
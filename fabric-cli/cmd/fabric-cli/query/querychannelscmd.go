@@ -17,33 +17,33 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var queryChannelsCmd = &cobra.Command{
-	Use:   "channels",
-	Short: "Query channels",
-	Long:  "Queries the channels of the specified peer",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newQueryChannelsAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing queryChannelsAction: %v", err)
-			return
-		}
-		defer action.Terminate()
-
-		if len(cliconfig.Config().PeerURLs()) != 1 {
-			fmt.Printf("\nMust specify exactly one peer URL\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-
-		err = action.run()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running queryChannelsAction: %v", err)
-		}
-	},
-}
+func getQueryChannelsCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryChannelsCmd := &cobra.Command{
+		Use:   "channels",
+		Short: "Query channels",
+		Long:  "Queries the channels of the specified peer",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newQueryChannelsAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryChannelsAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			if len(cfg.PeerURLs()) != 1 {
+				fmt.Printf("\nMust specify exactly one peer URL\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryChannelsAction: %v", err)
+			}
+		},
+	}
 
-func getQueryChannelsCmd() *cobra.Command {
-	cliconfig.InitPeerURL(queryChannelsCmd.Flags())
+	cfg.InitPeerURL(queryChannelsCmd.Flags())
 	return queryChannelsCmd
 }
 
@@ -51,15 +51,15 @@ type queryChannelsAction struct {
 	action.Action
 }
 
-func newQueryChannelsAction(flags *pflag.FlagSet) (*queryChannelsAction, error) {
+func newQueryChannelsAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryChannelsAction, error) {
 	action := &queryChannelsAction{}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
 func (a *queryChannelsAction) run() error {
 
-	url := cliconfig.Config().PeerURLs()
+	url := a.Config().PeerURLs()
 	if len(url) != 1 {
 		return errors.New("must specify exactly one peer URL")
 	}
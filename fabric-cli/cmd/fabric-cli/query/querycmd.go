@@ -11,26 +11,32 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var queryCmd = &cobra.Command{
-	Use:   "query",
-	Short: "Query commands",
-	Long:  "Query commands",
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.HelpFunc()(cmd, args)
-	},
-}
-
 // Cmd returns the query command
-func Cmd() *cobra.Command {
-	cliconfig.InitChannelID(queryCmd.Flags())
+func Cmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryCmd := &cobra.Command{
+		Use:   "query",
+		Short: "Query commands",
+		Long:  "Query commands",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	cfg.InitChannelID(queryCmd.Flags())
 
-	queryCmd.AddCommand(getQueryBlockCmd())
-	queryCmd.AddCommand(getQueryInfoCmd())
-	queryCmd.AddCommand(getQueryTXCmd())
-	queryCmd.AddCommand(getQueryChannelsCmd())
-	queryCmd.AddCommand(getQueryInstalledCmd())
-	queryCmd.AddCommand(getQueryPeersCmd())
-	queryCmd.AddCommand(getQueryLocalPeersCmd())
+	queryCmd.AddCommand(getQueryBlockCmd(cfg))
+	queryCmd.AddCommand(getQueryBlocksCmd(cfg))
+	queryCmd.AddCommand(getQueryInfoCmd(cfg))
+	queryCmd.AddCommand(getQueryTXCmd(cfg))
+	queryCmd.AddCommand(getQueryTXsCmd(cfg))
+	queryCmd.AddCommand(getQueryKeyHistoryCmd(cfg))
+	queryCmd.AddCommand(getQueryChannelsCmd(cfg))
+	queryCmd.AddCommand(getQueryInstalledCmd(cfg))
+	queryCmd.AddCommand(getQueryPeersCmd(cfg))
+	queryCmd.AddCommand(getQueryLocalPeersCmd(cfg))
+	queryCmd.AddCommand(getQueryHealthCmd(cfg))
+	queryCmd.AddCommand(getQueryEndorsersCmd(cfg))
+	queryCmd.AddCommand(getQueryHeightsCmd(cfg))
 
 	return queryCmd
 }
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"fmt"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getQueryEndorsersCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryEndorsersCmd := &cobra.Command{
+		Use:   "endorsers",
+		Short: "Query endorsers",
+		Long:  "Shows the endorsement policy of a chaincode, the groups of channel peers that satisfy the policy and the endorsers chosen by the selection provider",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChannelID() == "" {
+				fmt.Printf("\nMust specify channel ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			if cfg.ChaincodeID() == "" {
+				fmt.Printf("\nMust specify the chaincode ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newQueryEndorsersAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryEndorsersAction: %v", err)
+				return
+			}
+			defer action.Close()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryEndorsersAction: %v", err)
+			}
+		},
+	}
+
+	flags := queryEndorsersCmd.Flags()
+	cfg.InitChannelID(flags)
+	cfg.InitChaincodeID(flags)
+	cfg.InitSelectionProvider(flags)
+	cfg.InitSelectionRules(flags)
+	return queryEndorsersCmd
+}
+
+type queryEndorsersAction struct {
+	*fabriccli.Client
+}
+
+func newQueryEndorsersAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryEndorsersAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &queryEndorsersAction{Client: client}, nil
+}
+
+func (a *queryEndorsersAction) run() error {
+	endorsers, err := a.QueryEndorsers(a.Config().Context(), a.Config().ChaincodeID())
+	if err != nil {
+		return err
+	}
+
+	a.Printer().PrintEndorsers(endorsers.Policy, endorsers.Groups, endorsers.Selected)
+
+	return nil
+}
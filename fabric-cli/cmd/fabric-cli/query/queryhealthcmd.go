@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"fmt"
+
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/printer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getQueryHealthCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryHealthCmd := &cobra.Command{
+		Use:   "health",
+		Short: "Query peer health",
+		Long:  "Checks the health of the peers (all peers or those specified by --peer or --orgid) by connecting to each one and querying the blockchain info of the channel. The status and latency of each peer is output.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChannelID() == "" {
+				fmt.Printf("\nMust specify channel ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newQueryHealthAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryHealthAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryHealthAction: %v", err)
+			}
+		},
+	}
+
+	flags := queryHealthCmd.Flags()
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
+	return queryHealthCmd
+}
+
+type queryHealthAction struct {
+	action.Action
+}
+
+func newQueryHealthAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryHealthAction, error) {
+	action := &queryHealthAction{}
+	err := action.Initialize(cfg, flags)
+	return action, err
+}
+
+func (a *queryHealthAction) run() error {
+	monitor, err := a.NewHealthMonitor(a.Peers())
+	if err != nil {
+		return err
+	}
+
+	var statuses []*printer.PeerStatus
+	for _, s := range monitor.CheckAll(a.Config().Context()) {
+		statuses = append(statuses, &printer.PeerStatus{URL: s.URL, MSPID: s.MSPID, Healthy: s.Healthy, Latency: s.Latency, Err: s.Err})
+	}
+	a.Printer().PrintPeerHealth(statuses)
+
+	return nil
+}
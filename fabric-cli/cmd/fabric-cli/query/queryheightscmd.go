@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"fmt"
+	"time"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/printer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getQueryHeightsCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryHeightsCmd := &cobra.Command{
+		Use:   "heights",
+		Short: "Query ledger heights",
+		Long:  "Queries the ledger height and block hashes of the channel on each of the selected peers and flags the peers that are behind or that have a different block hash at the same height",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChannelID() == "" {
+				fmt.Printf("\nMust specify channel ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newQueryHeightsAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryHeightsAction: %v", err)
+				return
+			}
+			defer action.Close()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryHeightsAction: %v", err)
+			}
+		},
+	}
+
+	flags := queryHeightsCmd.Flags()
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitOrgIDs(flags)
+	cfg.InitWatchInterval(flags)
+	return queryHeightsCmd
+}
+
+type queryHeightsAction struct {
+	*fabriccli.Client
+}
+
+func newQueryHeightsAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryHeightsAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &queryHeightsAction{Client: client}, nil
+}
+
+func (a *queryHeightsAction) run() error {
+	ctx := a.Config().Context()
+	for {
+		heights, err := a.QueryHeights(ctx)
+		if ctx.Err() != nil {
+			// The command was interrupted so the heights of the peers that were cancelled aren't printed
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var peerHeights []*printer.PeerHeight
+		for _, h := range heights {
+			peerHeights = append(peerHeights, &printer.PeerHeight{
+				URL:               h.URL,
+				MSPID:             h.MSPID,
+				Height:            h.Height,
+				CurrentBlockHash:  h.CurrentBlockHash,
+				PreviousBlockHash: h.PreviousBlockHash,
+				Lag:               h.Lag,
+				HashMismatch:      h.HashMismatch,
+				Err:               h.Err,
+			})
+		}
+		a.Printer().PrintPeerHeights(peerHeights)
+
+		if a.Config().WatchInterval() <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(a.Config().WatchInterval()):
+			fmt.Println()
+		}
+	}
+}
@@ -9,63 +9,59 @@ package query
 import (
 	"fmt"
 
-	"github.com/pkg/errors"
-	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
 	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/pkg/fabriccli"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-var queryInfoCmd = &cobra.Command{
-	Use:   "info",
-	Short: "Query info",
-	Long:  "Queries general info",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newQueryInfoAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing queryInfoAction: %v", err)
-			return
-		}
-		defer action.Terminate()
+func getQueryInfoCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryInfoCmd := &cobra.Command{
+		Use:   "info",
+		Short: "Query info",
+		Long:  "Queries general info",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newQueryInfoAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryInfoAction: %v", err)
+				return
+			}
+			defer action.Close()
 
-		if cliconfig.Config().ChannelID() == "" {
-			fmt.Printf("\nMust specify channel ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
+			if cfg.ChannelID() == "" {
+				fmt.Printf("\nMust specify channel ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
 
-		err = action.run()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running queryInfoAction: %v", err)
-		}
-	},
-}
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryInfoAction: %v", err)
+			}
+		},
+	}
 
-func getQueryInfoCmd() *cobra.Command {
 	flags := queryInfoCmd.Flags()
-	cliconfig.InitTxID(flags)
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitPeerURL(flags)
+	cfg.InitTxID(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
 	return queryInfoCmd
 }
 
 type queryInfoAction struct {
-	action.Action
-}
-
-func newQueryInfoAction(flags *pflag.FlagSet) (*queryInfoAction, error) {
-	action := &queryInfoAction{}
-	err := action.Initialize(flags)
-	return action, err
+	*fabriccli.Client
 }
 
-func (a *queryInfoAction) run() error {
-	channelClient, err := a.LedgerClient()
+func newQueryInfoAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryInfoAction, error) {
+	client, err := fabriccli.NewFromFlags(cfg, flags)
 	if err != nil {
-		return errors.Errorf("Error getting admin ledger client: %v", err)
+		return nil, err
 	}
+	return &queryInfoAction{Client: client}, nil
+}
 
-	info, err := channelClient.QueryInfo()
+func (a *queryInfoAction) run() error {
+	info, err := a.QueryInfo(a.Config().Context())
 	if err != nil {
 		return err
 	}
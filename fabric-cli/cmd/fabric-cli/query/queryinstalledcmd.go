@@ -17,34 +17,34 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var queryInstalledCmd = &cobra.Command{
-	Use:   "installed",
-	Short: "Query installed chaincodes",
-	Long:  "Queries the chaincodes installed to the specified peer",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newqueryInstalledAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing queryInstalledAction: %v", err)
-			return
-		}
-
-		if len(cliconfig.Config().PeerURLs()) != 1 {
-			fmt.Printf("\nMust specify exactly one peer URL\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-
-		defer action.Terminate()
-
-		err = action.run()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running queryInstalledAction: %v", err)
-		}
-	},
-}
+func getQueryInstalledCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryInstalledCmd := &cobra.Command{
+		Use:   "installed",
+		Short: "Query installed chaincodes",
+		Long:  "Queries the chaincodes installed to the specified peer",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newqueryInstalledAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryInstalledAction: %v", err)
+				return
+			}
+
+			if len(cfg.PeerURLs()) != 1 {
+				fmt.Printf("\nMust specify exactly one peer URL\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryInstalledAction: %v", err)
+			}
+		},
+	}
 
-func getQueryInstalledCmd() *cobra.Command {
-	cliconfig.InitPeerURL(queryInstalledCmd.Flags())
+	cfg.InitPeerURL(queryInstalledCmd.Flags())
 	return queryInstalledCmd
 }
 
@@ -52,15 +52,15 @@ type queryInstalledAction struct {
 	action.Action
 }
 
-func newqueryInstalledAction(flags *pflag.FlagSet) (*queryInstalledAction, error) {
+func newqueryInstalledAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryInstalledAction, error) {
 	action := &queryInstalledAction{}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
 func (a *queryInstalledAction) run() error {
 
-	url := cliconfig.Config().PeerURLs()
+	url := a.Config().PeerURLs()
 	if len(url) != 1 {
 		return errors.New("must specify exactly one peer URL")
 	}
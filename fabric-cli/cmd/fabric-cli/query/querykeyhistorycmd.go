@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"fmt"
+
+	fabricCommon "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/printer"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/txfilter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getQueryKeyHistoryCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryKeyHistoryCmd := &cobra.Command{
+		Use:   "keyhistory",
+		Short: "Reconstruct the history of a key",
+		Long:  "Scans a range of blocks, queried from the channel or read from disk with --input, and prints every write and delete of a key along with the transactions that read each version of the key. The chaincode doesn't need to support GetHistoryForKey since the writes are decoded from the blocks.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChannelID() == "" && cfg.Input() == "" {
+				fmt.Printf("\nMust specify channel ID or input\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			if cfg.ChaincodeID() == "" || cfg.StateKey() == "" {
+				fmt.Printf("\nMust specify the chaincode ID and the key\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newQueryKeyHistoryAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryKeyHistoryAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryKeyHistoryAction: %v", err)
+			}
+		},
+	}
+
+	flags := queryKeyHistoryCmd.Flags()
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitInput(flags)
+	cfg.InitFromBlock(flags)
+	cfg.InitToBlock(flags)
+	cfg.InitChaincodeID(flags, "", "The chaincode whose state contains the key")
+	cfg.InitStateKey(flags)
+	return queryKeyHistoryCmd
+}
+
+type queryKeyHistoryAction struct {
+	action.Action
+	history *txfilter.KeyHistory
+}
+
+func newQueryKeyHistoryAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryKeyHistoryAction, error) {
+	action := &queryKeyHistoryAction{}
+	err := initScanAction(&action.Action, cfg, flags)
+	return action, err
+}
+
+func (a *queryKeyHistoryAction) run() error {
+	a.history = txfilter.NewKeyHistory(a.Config().ChaincodeID(), a.Config().StateKey())
+
+	err := scanBlocks(&a.Action, a.scanBlock)
+	if err != nil && a.Config().Context().Err() == nil {
+		return err
+	}
+
+	// The history of the blocks that were scanned is printed even if the command was interrupted
+	a.Printer().PrintKeyHistory(a.history.Namespace, a.history.Key, keyVersions(a.history))
+	return nil
+}
+
+func (a *queryKeyHistoryAction) scanBlock(block *fabricCommon.Block) error {
+	for _, tx := range txfilter.Extract(block) {
+		if tx.DecodeErr != nil {
+			a.Config().Logger().Warnf("The history may be incomplete: %s\n", tx.DecodeErr)
+		}
+		a.history.Add(tx)
+	}
+	return nil
+}
+
+// keyVersions returns the entries of the history that are printed
+func keyVersions(history *txfilter.KeyHistory) []*printer.KeyVersion {
+	var versions []*printer.KeyVersion
+	for _, entry := range history.Entries {
+		v := &printer.KeyVersion{BlockNum: entry.Version.BlockNum, TxNum: entry.Version.TxNum}
+		if entry.Tx != nil {
+			v.Tx = txSummary(entry.Tx)
+			v.Write = &printer.KeyWrite{Namespace: entry.Write.Namespace, Key: entry.Write.Key, IsDelete: entry.Write.IsDelete, Value: entry.Write.Value}
+		}
+		for _, tx := range entry.Reads {
+			v.Reads = append(v.Reads, txSummary(tx))
+		}
+		versions = append(versions, v)
+	}
+	return versions
+}
@@ -15,35 +15,35 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var queryLocalPeersCmd = &cobra.Command{
-	Use:   "localpeers",
-	Short: "Query local peers",
-	Long:  "Queries the peers for the specified org",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newQueryLocalPeersAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing queryLocalPeersAction: %v", err)
-			return
-		}
-		defer action.Terminate()
+func getQueryLocalPeersCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryLocalPeersCmd := &cobra.Command{
+		Use:   "localpeers",
+		Short: "Query local peers",
+		Long:  "Queries the peers for the specified org",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newQueryLocalPeersAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryLocalPeersAction: %v", err)
+				return
+			}
+			defer action.Terminate()
 
-		if cliconfig.Config().OrgID() == "" {
-			fmt.Printf("\nMust specify org ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
+			if cfg.OrgID() == "" {
+				fmt.Printf("\nMust specify org ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
 
-		err = action.run()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running queryLocalPeersAction: %v", err)
-		}
-	},
-}
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryLocalPeersAction: %v", err)
+			}
+		},
+	}
 
-func getQueryLocalPeersCmd() *cobra.Command {
 	flags := queryLocalPeersCmd.Flags()
-	cliconfig.InitOrgIDs(flags)
-	cliconfig.InitPeerURL(flags)
+	cfg.InitOrgIDs(flags)
+	cfg.InitPeerURL(flags)
 	return queryLocalPeersCmd
 }
 
@@ -51,9 +51,9 @@ type queryLocalPeersAction struct {
 	action.Action
 }
 
-func newQueryLocalPeersAction(flags *pflag.FlagSet) (*queryLocalPeersAction, error) {
+func newQueryLocalPeersAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryLocalPeersAction, error) {
 	action := &queryLocalPeersAction{}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
@@ -15,35 +15,35 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var queryPeersCmd = &cobra.Command{
-	Use:   "peers",
-	Short: "Query peers",
-	Long:  "Queries the peers for the specified channel",
-	Run: func(cmd *cobra.Command, args []string) {
-		action, err := newQueryPeersAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing queryPeersAction: %v", err)
-			return
-		}
-		defer action.Terminate()
-
-		if cliconfig.Config().ChannelID() == "" {
-			fmt.Printf("\nMust specify channel ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-
-		err = action.run()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running queryPeersAction: %v", err)
-		}
-	},
-}
+func getQueryPeersCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryPeersCmd := &cobra.Command{
+		Use:   "peers",
+		Short: "Query peers",
+		Long:  "Queries the peers for the specified channel",
+		Run: func(cmd *cobra.Command, args []string) {
+			action, err := newQueryPeersAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryPeersAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			if cfg.ChannelID() == "" {
+				fmt.Printf("\nMust specify channel ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryPeersAction: %v", err)
+			}
+		},
+	}
 
-func getQueryPeersCmd() *cobra.Command {
 	flags := queryPeersCmd.Flags()
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitPeerURL(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
 	return queryPeersCmd
 }
 
@@ -51,9 +51,9 @@ type queryPeersAction struct {
 	action.Action
 }
 
-func newQueryPeersAction(flags *pflag.FlagSet) (*queryPeersAction, error) {
+func newQueryPeersAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryPeersAction, error) {
 	action := &queryPeersAction{}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 	return action, err
 }
 
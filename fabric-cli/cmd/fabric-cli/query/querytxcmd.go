@@ -17,36 +17,36 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var queryTXCmd = &cobra.Command{
-	Use:   "tx",
-	Short: "Query transaction",
-	Long:  "Queries a transaction",
-	Run: func(cmd *cobra.Command, args []string) {
-		if cliconfig.Config().TxID() == "" {
-			fmt.Printf("\nMust specify the transaction ID\n\n")
-			cmd.HelpFunc()(cmd, args)
-			return
-		}
-		action, err := newQueryTXAction(cmd.Flags())
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while initializing queryTXAction: %v", err)
-			return
-		}
+func getQueryTXCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryTXCmd := &cobra.Command{
+		Use:   "tx",
+		Short: "Query transaction",
+		Long:  "Queries a transaction",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.TxID() == "" {
+				fmt.Printf("\nMust specify the transaction ID\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+			action, err := newQueryTXAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryTXAction: %v", err)
+				return
+			}
 
-		defer action.Terminate()
+			defer action.Terminate()
 
-		err = action.run()
-		if err != nil {
-			cliconfig.Config().Logger().Errorf("Error while running queryTXAction: %v", err)
-		}
-	},
-}
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryTXAction: %v", err)
+			}
+		},
+	}
 
-func getQueryTXCmd() *cobra.Command {
 	flags := queryTXCmd.Flags()
-	cliconfig.InitChannelID(flags)
-	cliconfig.InitTxID(flags)
-	cliconfig.InitPeerURL(flags)
+	cfg.InitChannelID(flags)
+	cfg.InitTxID(flags)
+	cfg.InitPeerURL(flags)
 	return queryTXCmd
 }
 
@@ -54,9 +54,9 @@ type queryTXAction struct {
 	action.Action
 }
 
-func newQueryTXAction(flags *pflag.FlagSet) (*queryTXAction, error) {
+func newQueryTXAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryTXAction, error) {
 	action := &queryTXAction{}
-	err := action.Initialize(flags)
+	err := action.Initialize(cfg, flags)
 
 	return action, err
 }
@@ -67,12 +67,12 @@ func (a *queryTXAction) run() error {
 		return errors.Errorf("Error getting ledger client: %v", err)
 	}
 
-	tx, err := ledgerClient.QueryTransaction(fab.TransactionID(cliconfig.Config().TxID()))
+	tx, err := ledgerClient.QueryTransaction(fab.TransactionID(a.Config().TxID()))
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Transaction %s in channel %s\n", cliconfig.Config().TxID(), cliconfig.Config().ChannelID())
+	fmt.Printf("Transaction %s in channel %s\n", a.Config().TxID(), a.Config().ChannelID())
 	a.Printer().PrintProcessedTransaction(tx)
 
 	return nil
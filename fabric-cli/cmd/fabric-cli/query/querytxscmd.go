@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package query
+
+import (
+	"fmt"
+	"time"
+
+	fabricCommon "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/pkg/errors"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/action"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/txfilter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getQueryTXsCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	queryTXsCmd := &cobra.Command{
+		Use:   "txs",
+		Short: "Search for transactions",
+		Long:  "Scans a range of blocks, queried from the channel or read from disk with --input, and prints the transactions that match all of the given filters",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cfg.ChannelID() == "" && cfg.Input() == "" {
+				fmt.Printf("\nMust specify channel ID or input\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			action, err := newQueryTXsAction(cfg, cmd.Flags())
+			if err != nil {
+				cfg.Logger().Errorf("Error while initializing queryTXsAction: %v", err)
+				return
+			}
+			defer action.Terminate()
+
+			err = action.run()
+			if err != nil {
+				cfg.Logger().Errorf("Error while running queryTXsAction: %v", err)
+			}
+		},
+	}
+
+	flags := queryTXsCmd.Flags()
+	cfg.InitChannelID(flags)
+	cfg.InitPeerURL(flags)
+	cfg.InitInput(flags)
+	cfg.InitFromBlock(flags)
+	cfg.InitToBlock(flags)
+	cfg.InitChaincodeID(flags, "", "Only include transactions that invoked this chaincode")
+	cfg.InitFunction(flags)
+	cfg.InitCreatorMSP(flags)
+	cfg.InitValidationCode(flags)
+	cfg.InitWriteKey(flags)
+	cfg.InitWriteKeyPrefix(flags)
+	cfg.InitSince(flags)
+	cfg.InitUntil(flags)
+	cfg.InitFull(flags)
+	return queryTXsCmd
+}
+
+type queryTXsAction struct {
+	action.Action
+	filter  *txfilter.Filter
+	scanned int
+	matched int
+}
+
+func newQueryTXsAction(cfg *cliconfig.CLIConfig, flags *pflag.FlagSet) (*queryTXsAction, error) {
+	action := &queryTXsAction{}
+	err := initScanAction(&action.Action, cfg, flags)
+	return action, err
+}
+
+func (a *queryTXsAction) run() error {
+	filter, err := a.newFilter()
+	if err != nil {
+		return err
+	}
+	a.filter = filter
+
+	err = scanBlocks(&a.Action, a.scanBlock)
+	if err != nil && a.Config().Context().Err() == nil {
+		return err
+	}
+
+	a.Printer().Print("Found %d matching transactions in %d blocks\n", a.matched, a.scanned)
+	return nil
+}
+
+func (a *queryTXsAction) newFilter() (*txfilter.Filter, error) {
+	filter := &txfilter.Filter{
+		ChaincodeID:    a.Config().ChaincodeID(),
+		Function:       a.Config().Function(),
+		CreatorMSPID:   a.Config().CreatorMSP(),
+		ValidationCode: a.Config().ValidationCode(),
+		Key:            a.Config().WriteKey(),
+		KeyPrefix:      a.Config().WriteKeyPrefix(),
+	}
+
+	var err error
+	if a.Config().Since() != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, a.Config().Since()); err != nil {
+			return nil, errors.Wrapf(err, "invalid value for --%s", cliconfig.SinceFlag)
+		}
+	}
+	if a.Config().Until() != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, a.Config().Until()); err != nil {
+			return nil, errors.Wrapf(err, "invalid value for --%s", cliconfig.UntilFlag)
+		}
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+func (a *queryTXsAction) scanBlock(block *fabricCommon.Block) error {
+	a.scanned++
+
+	for _, tx := range txfilter.Extract(block) {
+		if a.filter.Match(tx) {
+			a.matched++
+			a.Printer().PrintTx(txSummary(tx), a.Config().Full())
+		}
+	}
+	return nil
+}
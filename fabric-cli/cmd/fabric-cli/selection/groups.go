@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package selection
+
+import (
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// PeerGroups returns the groups of the given peers that satisfy the endorsement policy, computed in the
+// same way as the SDK's dynamic selection service. Any one of the groups may be chosen to endorse a transaction.
+func PeerGroups(policy *common.SignaturePolicyEnvelope, peers []fab.Peer) ([][]fab.Peer, error) {
+	retriever, err := pgresolver.CompileSignaturePolicy(policy)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error compiling endorsement policy")
+	}
+
+	hierarchy, err := retriever(func(mspID string) []fab.Peer {
+		var mspPeers []fab.Peer
+		for _, peer := range peers {
+			if mspID == "" || peer.MSPID() == mspID {
+				mspPeers = append(mspPeers, peer)
+			}
+		}
+		return mspPeers
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error evaluating endorsement policy")
+	}
+
+	var groups [][]fab.Peer
+	for _, orgGroup := range hierarchy.Reduce() {
+		alternatives, err := peerAlternatives(orgGroup)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, combinations(alternatives)...)
+	}
+	return groups, nil
+}
+
+// peerAlternatives returns the sets of peers (one set per principal) of a reduced group.
+// A group is satisfied by one peer from each set.
+func peerAlternatives(group pgresolver.Group) ([][]fab.Peer, error) {
+	if pg, ok := group.(pgresolver.PeerGroup); ok {
+		return [][]fab.Peer{pg.Peers()}, nil
+	}
+
+	var alternatives [][]fab.Peer
+	for _, item := range group.Items() {
+		pg, ok := item.(pgresolver.PeerGroup)
+		if !ok {
+			return nil, errors.Errorf("unexpected item in peer group: %T", item)
+		}
+		alternatives = append(alternatives, pg.Peers())
+	}
+	return alternatives, nil
+}
+
+// combinations returns every combination of one peer from each set, e.g. [(A,B),(C,D)] returns [(A,C),(A,D),(B,C),(B,D)]
+func combinations(sets [][]fab.Peer) [][]fab.Peer {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	var result [][]fab.Peer
+	for _, peer := range sets[0] {
+		if len(sets) == 1 {
+			result = append(result, []fab.Peer{peer})
+			continue
+		}
+		for _, rest := range combinations(sets[1:]) {
+			result = append(result, append([]fab.Peer{peer}, rest...))
+		}
+	}
+	return result
+}
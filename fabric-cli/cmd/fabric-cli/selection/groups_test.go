@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package selection
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerGroups(t *testing.T) {
+	p10 := testutil.NewPeer("peer0.org1:7051", "Org1MSP")
+	p11 := testutil.NewPeer("peer1.org1:7051", "Org1MSP")
+	p20 := testutil.NewPeer("peer0.org2:7051", "Org2MSP")
+	p30 := testutil.NewPeer("peer0.org3:7051", "Org3MSP")
+	peers := []fab.Peer{p10, p11, p20, p30}
+
+	signedBy, identities, err := pgresolver.GetPolicies("Org1MSP", "Org2MSP", "Org3MSP")
+	require.NoError(t, err)
+
+	t.Run("Any of", func(t *testing.T) {
+		policy := &common.SignaturePolicyEnvelope{
+			Rule:       pgresolver.NewNOutOfPolicy(1, signedBy[0], signedBy[1]),
+			Identities: identities,
+		}
+		groups, err := PeerGroups(policy, peers)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, [][]fab.Peer{{p10}, {p11}, {p20}}, groups)
+	})
+
+	t.Run("All of", func(t *testing.T) {
+		policy := &common.SignaturePolicyEnvelope{
+			Rule:       pgresolver.NewNOutOfPolicy(2, signedBy[0], signedBy[1]),
+			Identities: identities,
+		}
+		groups, err := PeerGroups(policy, peers)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, [][]fab.Peer{{p10, p20}, {p11, p20}}, groups)
+	})
+
+	t.Run("No peers", func(t *testing.T) {
+		policy := &common.SignaturePolicyEnvelope{
+			Rule:       pgresolver.NewNOutOfPolicy(1, signedBy[2]),
+			Identities: identities,
+		}
+		groups, err := PeerGroups(policy, []fab.Peer{p10})
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+	})
+}
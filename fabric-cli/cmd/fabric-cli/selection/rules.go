@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package selection provides a selection service that chooses endorsers deterministically according
+// to the rules in a local policy file. It's intended for test networks that don't have the Discovery
+// Service enabled but where it's necessary to control which peers endorse a transaction. The package
+// also computes the groups of peers that satisfy an endorsement policy so that a selection may be explained.
+package selection
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Rules contains the endorser selection rules. For example:
+//
+//	maxPeersPerOrg: 1
+//	orgs:
+//	  Org1MSP:
+//	    preferred: [peer1.org1.example.com:7151]
+//	    priority: [peer0.org1.example.com:7051, peer2.org1.example.com:7251]
+//	    exclude: [peer3.org1.example.com:7351]
+//	chaincodes:
+//	  examplecc:
+//	    maxPeersPerOrg: 2
+type Rules struct {
+	// MaxPeersPerOrg is the maximum number of peers chosen from each organization (0 means no limit)
+	MaxPeersPerOrg int `yaml:"maxPeersPerOrg"`
+
+	// Orgs contains the rules of each organization keyed by MSP ID or organization name
+	Orgs map[string]OrgRules `yaml:"orgs"`
+
+	// Chaincodes contains the rules that override the defaults for a chaincode
+	Chaincodes map[string]ChaincodeRules `yaml:"chaincodes"`
+}
+
+// OrgRules contains the rules for the peers of an organization. Peers are identified by URL, with or without the scheme.
+type OrgRules struct {
+	// Preferred peers are chosen in the given order. If any of the preferred peers are in the
+	// channel then only those peers are chosen, otherwise the other peers are chosen by priority.
+	Preferred []string `yaml:"preferred"`
+
+	// Priority lists the order in which peers are chosen. Peers that aren't listed are chosen
+	// after the listed peers, in order of URL.
+	Priority []string `yaml:"priority"`
+
+	// Exclude lists the peers that are never chosen
+	Exclude []string `yaml:"exclude"`
+}
+
+// ChaincodeRules contains the rules for a chaincode
+type ChaincodeRules struct {
+	// MaxPeersPerOrg is the maximum number of peers chosen from each organization (0 means no limit)
+	MaxPeersPerOrg int `yaml:"maxPeersPerOrg"`
+}
+
+// LoadRules reads the rules from the given YAML file
+func LoadRules(path string) (*Rules, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading selection rules [%s]", path)
+	}
+
+	rules := &Rules{}
+	if err := yaml.UnmarshalStrict(data, rules); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshalling selection rules [%s]", path)
+	}
+	if err := rules.validate(); err != nil {
+		return nil, errors.WithMessagef(err, "invalid selection rules [%s]", path)
+	}
+	return rules, nil
+}
+
+// MaxPeers returns the maximum number of peers chosen from each organization for the given chaincode
+func (r *Rules) MaxPeers(ccID string) int {
+	if cc, ok := r.Chaincodes[ccID]; ok && cc.MaxPeersPerOrg > 0 {
+		return cc.MaxPeersPerOrg
+	}
+	return r.MaxPeersPerOrg
+}
+
+func (r *Rules) validate() error {
+	if r.MaxPeersPerOrg < 0 {
+		return errors.Errorf("maxPeersPerOrg must not be negative: %d", r.MaxPeersPerOrg)
+	}
+	for ccID, cc := range r.Chaincodes {
+		if cc.MaxPeersPerOrg < 0 {
+			return errors.Errorf("maxPeersPerOrg of chaincode [%s] must not be negative: %d", ccID, cc.MaxPeersPerOrg)
+		}
+	}
+	for org, orgRules := range r.Orgs {
+		excluded := make(map[string]bool)
+		for _, url := range orgRules.Exclude {
+			excluded[normalizeURL(url)] = true
+		}
+		for _, url := range orgRules.Preferred {
+			if excluded[normalizeURL(url)] {
+				return errors.Errorf("peer [%s] of organization [%s] is both preferred and excluded", url, org)
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeURL strips the scheme from the URL so that peers may be referred to as either
+// grpcs://host:port or host:port
+func normalizeURL(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+	}
+	return strings.ToLower(url)
+}
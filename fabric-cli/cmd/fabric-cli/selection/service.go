@@ -0,0 +1,172 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package selection
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	copts "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// Service is a selection service that chooses endorsers according to the selection rules.
+// For a given set of channel peers the same endorsers are always chosen.
+type Service struct {
+	discovery fab.DiscoveryService
+	rules     *Rules
+	orgRules  map[string]*orgRules
+}
+
+// orgRules contains the normalized rules of an organization
+type orgRules struct {
+	preferred map[string]int
+	priority  map[string]int
+	exclude   map[string]bool
+}
+
+// NewService returns a new rules-based selection service. mspIDs maps organization names to MSP IDs
+// so that the rules may refer to an organization by either its name or its MSP ID.
+func NewService(discovery fab.DiscoveryService, rules *Rules, mspIDs map[string]string) (*Service, error) {
+	s := &Service{
+		discovery: discovery,
+		rules:     rules,
+		orgRules:  make(map[string]*orgRules),
+	}
+
+	for org, r := range rules.Orgs {
+		mspID := org
+		for name, id := range mspIDs {
+			if strings.EqualFold(name, org) {
+				mspID = id
+				break
+			}
+		}
+		if _, exists := s.orgRules[mspID]; exists {
+			return nil, errors.Errorf("duplicate selection rules for organization [%s]", mspID)
+		}
+		s.orgRules[mspID] = newOrgRules(r)
+	}
+
+	return s, nil
+}
+
+func newOrgRules(r OrgRules) *orgRules {
+	rules := &orgRules{
+		preferred: make(map[string]int),
+		priority:  make(map[string]int),
+		exclude:   make(map[string]bool),
+	}
+	for i, url := range r.Preferred {
+		rules.preferred[normalizeURL(url)] = i
+	}
+	for i, url := range r.Priority {
+		rules.priority[normalizeURL(url)] = i
+	}
+	for _, url := range r.Exclude {
+		rules.exclude[normalizeURL(url)] = true
+	}
+	return rules
+}
+
+// GetEndorsersForChaincode returns the endorsers chosen by the selection rules. The maximum number of peers per
+// organization is that of the first chaincode, i.e. the chaincode being invoked. A peer filter option is applied
+// before the rules are applied; a peer sorter option is ignored since the rules determine the order of the peers.
+func (s *Service) GetEndorsersForChaincode(chaincodes []*fab.ChaincodeCall, opts ...copts.Opt) ([]fab.Peer, error) {
+	if len(chaincodes) == 0 {
+		return nil, errors.New("no chaincode IDs provided")
+	}
+
+	params := options.NewParams(opts)
+
+	channelPeers, err := s.discovery.GetPeers()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error retrieving peers from discovery service")
+	}
+
+	if params.PeerFilter != nil {
+		var peers []fab.Peer
+		for _, peer := range channelPeers {
+			if params.PeerFilter(peer) {
+				peers = append(peers, peer)
+			}
+		}
+		channelPeers = peers
+	}
+
+	return s.choose(channelPeers, s.rules.MaxPeers(chaincodes[0].ID)), nil
+}
+
+// choose returns the peers chosen by the rules, grouped by MSP ID in order of MSP ID
+func (s *Service) choose(peers []fab.Peer, maxPeersPerOrg int) []fab.Peer {
+	peersByMSP := make(map[string][]fab.Peer)
+	var mspIDs []string
+	for _, peer := range peers {
+		if _, ok := peersByMSP[peer.MSPID()]; !ok {
+			mspIDs = append(mspIDs, peer.MSPID())
+		}
+		peersByMSP[peer.MSPID()] = append(peersByMSP[peer.MSPID()], peer)
+	}
+	sort.Strings(mspIDs)
+
+	var chosen []fab.Peer
+	for _, mspID := range mspIDs {
+		orgPeers := s.orgRules[mspID].order(peersByMSP[mspID])
+		if maxPeersPerOrg > 0 && len(orgPeers) > maxPeersPerOrg {
+			orgPeers = orgPeers[:maxPeersPerOrg]
+		}
+		chosen = append(chosen, orgPeers...)
+	}
+	return chosen
+}
+
+// order returns the peers of an organization that may be chosen, in the order in which they're chosen
+func (r *orgRules) order(peers []fab.Peer) []fab.Peer {
+	if r == nil {
+		r = &orgRules{}
+	}
+
+	var preferred, others []fab.Peer
+	for _, peer := range peers {
+		url := normalizeURL(peer.URL())
+		if r.exclude[url] {
+			continue
+		}
+		if _, ok := r.preferred[url]; ok {
+			preferred = append(preferred, peer)
+		} else {
+			others = append(others, peer)
+		}
+	}
+
+	if len(preferred) > 0 {
+		sortPeers(preferred, r.preferred)
+		return preferred
+	}
+
+	sortPeers(others, r.priority)
+	return others
+}
+
+// sortPeers sorts the peers that are in the ranking by rank, followed by the remaining peers in order of URL
+func sortPeers(peers []fab.Peer, ranking map[string]int) {
+	sort.SliceStable(peers, func(i, j int) bool {
+		urli, urlj := normalizeURL(peers[i].URL()), normalizeURL(peers[j].URL())
+		ranki, iok := ranking[urli]
+		rankj, jok := ranking[urlj]
+		switch {
+		case iok && jok:
+			return ranki < rankj
+		case iok != jok:
+			return iok
+		default:
+			return urli < urlj
+		}
+	})
+}
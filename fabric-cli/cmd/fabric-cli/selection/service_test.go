@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package selection
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rulesYAML = `
+maxPeersPerOrg: 1
+orgs:
+  org1:
+    priority: [grpcs://peer2.org1:7051, peer1.org1:7051]
+    exclude: [peer3.org1:7051]
+  Org2MSP:
+    preferred: [peer1.org2:7051]
+chaincodes:
+  examplecc:
+    maxPeersPerOrg: 2
+`
+
+func TestService(t *testing.T) {
+	p10 := testutil.NewPeer("grpcs://peer0.org1:7051", "Org1MSP")
+	p11 := testutil.NewPeer("grpcs://peer1.org1:7051", "Org1MSP")
+	p12 := testutil.NewPeer("grpcs://peer2.org1:7051", "Org1MSP")
+	p13 := testutil.NewPeer("grpcs://peer3.org1:7051", "Org1MSP")
+	p20 := testutil.NewPeer("peer0.org2:7051", "Org2MSP")
+	p21 := testutil.NewPeer("peer1.org2:7051", "Org2MSP")
+	p30 := testutil.NewPeer("peer1.org3:7051", "Org3MSP")
+	p31 := testutil.NewPeer("peer0.org3:7051", "Org3MSP")
+
+	rules := loadRules(t, rulesYAML)
+	discovery := mocks.NewMockDiscoveryService(nil, p30, p21, p13, p10, p31, p20, p11, p12)
+	s, err := NewService(discovery, rules, map[string]string{"org1": "Org1MSP", "org2": "Org2MSP"})
+	require.NoError(t, err)
+
+	t.Run("Default max peers", func(t *testing.T) {
+		peers, err := s.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "othercc"}})
+		require.NoError(t, err)
+		assert.Equal(t, []fab.Peer{p12, p21, p31}, peers)
+	})
+
+	t.Run("Chaincode max peers", func(t *testing.T) {
+		peers, err := s.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "examplecc"}})
+		require.NoError(t, err)
+		assert.Equal(t, []fab.Peer{p12, p11, p21, p31, p30}, peers)
+	})
+
+	// The rules for org1 don't apply without a mapping of organization names to MSP IDs
+	t.Run("Preferred peer not in channel", func(t *testing.T) {
+		s, err := NewService(mocks.NewMockDiscoveryService(nil, p20, p13, p10), rules, nil)
+		require.NoError(t, err)
+		peers, err := s.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "examplecc"}})
+		require.NoError(t, err)
+		assert.Equal(t, []fab.Peer{p10, p13, p20}, peers)
+	})
+
+	t.Run("Peer filter", func(t *testing.T) {
+		peers, err := s.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "othercc"}},
+			options.WithPeerFilter(func(peer fab.Peer) bool { return peer != p12 && peer.MSPID() != "Org3MSP" }))
+		require.NoError(t, err)
+		assert.Equal(t, []fab.Peer{p11, p21}, peers)
+	})
+
+	t.Run("No chaincodes", func(t *testing.T) {
+		_, err := s.GetEndorsersForChaincode(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadRules(t *testing.T) {
+	rules := loadRules(t, rulesYAML)
+	assert.Equal(t, 1, rules.MaxPeers("othercc"))
+	assert.Equal(t, 2, rules.MaxPeers("examplecc"))
+
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = LoadRules(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+
+	for _, invalid := range []string{
+		"maxPeersPerOrg: -1",
+		"unknown: 1",
+		"orgs:\n  Org1MSP:\n    preferred: [peer0:7051]\n    exclude: [grpcs://peer0:7051]",
+	} {
+		path := filepath.Join(dir, "invalid.yaml")
+		require.NoError(t, ioutil.WriteFile(path, []byte(invalid), 0600))
+		_, err = LoadRules(path)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func loadRules(t *testing.T, content string) *Rules {
+	f, err := ioutil.TempFile("", "rules")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	rules, err := LoadRules(f.Name())
+	require.NoError(t, err)
+	return rules
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package settings
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func getGenerateCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a connection profile",
+		Long:  "Generates an SDK connection profile from a crypto-config directory (generated by cryptogen or Fabric CA) and a list of peer/orderer endpoints",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := cfg.InitConfig(cmd.Flags()); err != nil {
+				fmt.Printf("Error initializing config: %s\n", err)
+				return
+			}
+
+			if cfg.CryptoConfig() == "" {
+				fmt.Printf("\nMust specify the path of the crypto-config directory\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			opts := &cliconfig.GenerateOpts{
+				CryptoConfigPath: cfg.CryptoConfig(),
+				Endpoints:        cfg.Endpoints(),
+				MSPIDs:           cfg.MSPIDs(),
+				Channels:         cfg.ChannelIDs(),
+				StateStorePath:   cfg.StateStore(),
+			}
+			if orgIDs := cfg.OrgIDs(); len(orgIDs) > 0 {
+				opts.ClientOrg = orgIDs[0]
+			}
+
+			profile, err := cliconfig.GenerateConnectionProfile(opts)
+			if err != nil {
+				fmt.Printf("Error generating connection profile: %s\n", err)
+				return
+			}
+
+			output := cfg.Output()
+			if output == "" {
+				fmt.Print(string(profile))
+				return
+			}
+
+			if err := ioutil.WriteFile(output, profile, 0644); err != nil {
+				fmt.Printf("Error writing connection profile: %s\n", err)
+				return
+			}
+			fmt.Printf("Connection profile written to %s\n", output)
+		},
+	}
+
+	flags := generateCmd.Flags()
+	cfg.InitCryptoConfig(flags)
+	cfg.InitEndpoints(flags)
+	cfg.InitMSPIDs(flags)
+	cfg.InitChannelIDs(flags)
+	cfg.InitStateStore(flags)
+	cfg.InitOutput(flags)
+	return generateCmd
+}
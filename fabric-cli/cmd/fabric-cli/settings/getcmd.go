@@ -0,0 +1,46 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package settings
+
+import (
+	"fmt"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func getGetCmd() *cobra.Command {
+	getCmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Get a setting",
+		Long:  "Displays the value of a flag in the selected profile (--profile, $FABRIC_CLI_PROFILE or the current profile)",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Printf("\nMust specify the key\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			settings, err := cliconfig.LoadSettings()
+			if err != nil {
+				fmt.Printf("Error loading settings: %s\n", err)
+				return
+			}
+
+			profile := selectedProfile(settings, cmd.Flags())
+			value, ok := settings.Get(profile, args[0])
+			if !ok {
+				fmt.Printf("[%s] is not set in profile [%s]\n", args[0], profile)
+				return
+			}
+
+			fmt.Println(value)
+		},
+	}
+
+	return getCmd
+}
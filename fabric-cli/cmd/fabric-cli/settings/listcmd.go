@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package settings
+
+import (
+	"fmt"
+	"sort"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func getListCmd() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		Long:  "Lists the profiles in the settings file and their settings. The active profile is marked with '*'.",
+		Run: func(cmd *cobra.Command, args []string) {
+			settings, err := cliconfig.LoadSettings()
+			if err != nil {
+				fmt.Printf("Error loading settings: %s\n", err)
+				return
+			}
+
+			fmt.Printf("Settings file: %s\n", settings.Path())
+
+			names := settings.ProfileNames()
+			if len(names) == 0 {
+				fmt.Printf("No profiles defined\n")
+				return
+			}
+
+			active := settings.ActiveProfile(cmd.Flags())
+			for _, name := range names {
+				marker := " "
+				if name == active {
+					marker = "*"
+				}
+				fmt.Printf("%s %s\n", marker, name)
+
+				profile, _ := settings.Profile(name)
+				var keys []string
+				for key := range profile {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					fmt.Printf("    %s: %s\n", key, profile[key])
+				}
+			}
+		},
+	}
+
+	return listCmd
+}
@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package settings
+
+import (
+	"fmt"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func getSetCmd() *cobra.Command {
+	var unset bool
+	setCmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a setting",
+		Long:  "Sets the value of a flag in the selected profile (--profile, $FABRIC_CLI_PROFILE or the current profile). The key is the name of the flag without the leading dashes, e.g. cid.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if (unset && len(args) != 1) || (!unset && len(args) != 2) {
+				fmt.Printf("\nMust specify the key and value (or only the key with --unset)\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			key := args[0]
+			if !isValidKey(cmd, key) {
+				fmt.Printf("\nInvalid key [%s] - must be one of: %v\n\n", key, flagNames(cmd.Root()))
+				return
+			}
+
+			settings, err := cliconfig.LoadSettings()
+			if err != nil {
+				fmt.Printf("Error loading settings: %s\n", err)
+				return
+			}
+
+			profile := selectedProfile(settings, cmd.Flags())
+			if unset {
+				settings.Unset(profile, key)
+			} else {
+				settings.Set(profile, key, args[1])
+			}
+			if settings.CurrentProfile == "" {
+				settings.CurrentProfile = profile
+			}
+
+			if err := settings.Save(); err != nil {
+				fmt.Printf("Error saving settings: %s\n", err)
+				return
+			}
+
+			if unset {
+				fmt.Printf("Unset [%s] in profile [%s]\n", key, profile)
+			} else {
+				fmt.Printf("Set [%s] to [%s] in profile [%s]\n", key, args[1], profile)
+			}
+		},
+	}
+
+	setCmd.Flags().BoolVar(&unset, "unset", false, "If true then the key is removed from the profile")
+	return setCmd
+}
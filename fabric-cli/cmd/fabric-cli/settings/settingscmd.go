@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package settings
+
+import (
+	"sort"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const settingsCmdName = "config"
+
+// Cmd returns the config command
+func Cmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	settingsCmd := &cobra.Command{
+		Use:   settingsCmdName,
+		Short: "CLI settings commands",
+		Long:  "Commands for managing the profiles in the settings file ($FABRIC_CLI_HOME/config.yaml or ~/.fabric-cli/config.yaml) and generating and validating connection profiles",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// The settings aren't applied up front so that the profiles may be managed even if the
+			// active profile doesn't exist. The generate and validate commands apply them in InitConfig.
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.HelpFunc()(cmd, args)
+		},
+	}
+
+	settingsCmd.AddCommand(getGetCmd())
+	settingsCmd.AddCommand(getSetCmd())
+	settingsCmd.AddCommand(getListCmd())
+	settingsCmd.AddCommand(getUseProfileCmd())
+	settingsCmd.AddCommand(getValidateCmd(cfg))
+	settingsCmd.AddCommand(getGenerateCmd(cfg))
+
+	return settingsCmd
+}
+
+// selectedProfile returns the profile that the get/set commands apply to
+func selectedProfile(settings *cliconfig.Settings, flags *pflag.FlagSet) string {
+	if profile := settings.ActiveProfile(flags); profile != "" {
+		return profile
+	}
+	return cliconfig.DefaultProfile
+}
+
+// flagNames returns the sorted names of all flags of the given command and its sub-commands
+// (excluding the flags of the config commands)
+func flagNames(cmd *cobra.Command) []string {
+	names := make(map[string]struct{})
+	collectFlagNames(cmd, names)
+
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func collectFlagNames(cmd *cobra.Command, names map[string]struct{}) {
+	if cmd.Name() == settingsCmdName && cmd.HasParent() && !cmd.Parent().HasParent() {
+		return
+	}
+	visit := func(flag *pflag.Flag) {
+		if flag.Name != "help" && flag.Name != cliconfig.ProfileFlag {
+			names[flag.Name] = struct{}{}
+		}
+	}
+	cmd.PersistentFlags().VisitAll(visit)
+	cmd.LocalFlags().VisitAll(visit)
+	for _, c := range cmd.Commands() {
+		collectFlagNames(c, names)
+	}
+}
+
+// isValidKey returns true if the given key is the name of a flag of any command
+func isValidKey(cmd *cobra.Command, key string) bool {
+	for _, name := range flagNames(cmd.Root()) {
+		if name == key {
+			return true
+		}
+	}
+	return false
+}
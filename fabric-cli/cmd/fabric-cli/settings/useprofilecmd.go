@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package settings
+
+import (
+	"fmt"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func getUseProfileCmd() *cobra.Command {
+	useProfileCmd := &cobra.Command{
+		Use:   "use-profile <name>",
+		Short: "Set the current profile",
+		Long:  "Sets the profile that's used when neither --profile nor $FABRIC_CLI_PROFILE is specified",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				fmt.Printf("\nMust specify the profile name\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			settings, err := cliconfig.LoadSettings()
+			if err != nil {
+				fmt.Printf("Error loading settings: %s\n", err)
+				return
+			}
+
+			if _, ok := settings.Profile(args[0]); !ok {
+				fmt.Printf("Profile [%s] not found - use 'config set --profile %s <key> <value>' to create it\n", args[0], args[0])
+				return
+			}
+
+			settings.CurrentProfile = args[0]
+			if err := settings.Save(); err != nil {
+				fmt.Printf("Error saving settings: %s\n", err)
+				return
+			}
+
+			fmt.Printf("Current profile is [%s]\n", args[0])
+		},
+	}
+
+	return useProfileCmd
+}
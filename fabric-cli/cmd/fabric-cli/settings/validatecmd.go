@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package settings
+
+import (
+	"fmt"
+
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/spf13/cobra"
+)
+
+func getValidateCmd(cfg *cliconfig.CLIConfig) *cobra.Command {
+	validateCmd := &cobra.Command{
+		Use:   "validate [<path>]",
+		Short: "Validate the connection profile",
+		Long:  "Checks the organizations, peers, orderers, channels, certificate authorities and file references of the connection profile (the given path or --config) and reports all of the problems with their locations",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Apply the environment and profile so that --config may be taken from the settings
+			if err := cfg.InitConfig(cmd.Flags()); err != nil {
+				fmt.Printf("Error initializing config: %s\n", err)
+				return
+			}
+
+			path := cfg.ConfigFile()
+			if len(args) > 0 {
+				path = args[0]
+			}
+			if path == "" {
+				fmt.Printf("\nMust specify the path of the connection profile\n\n")
+				cmd.HelpFunc()(cmd, args)
+				return
+			}
+
+			problems, err := cliconfig.ValidateConnectionProfile(path)
+			if err != nil {
+				fmt.Printf("Error validating connection profile: %s\n", err)
+				return
+			}
+
+			if len(problems) == 0 {
+				fmt.Printf("No problems found in %s\n", path)
+				return
+			}
+
+			for _, problem := range problems {
+				fmt.Printf("%s: %s\n", problem.Location(path), problem)
+			}
+			fmt.Printf("\n%d error(s), %d warning(s)\n", problems.Count(cliconfig.SeverityError), problems.Count(cliconfig.SeverityWarning))
+		},
+	}
+
+	return validateCmd
+}
@@ -0,0 +1,228 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package target chooses the target peers of each invoke/query request from the peers specified
+// on the command-line so that the load on each peer is comparable to that of a client SDK that
+// balances its requests, rather than every request being sent to every peer.
+package target
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+)
+
+const (
+	// latencyWeight is the weight of the latest sample in the moving average of a peer's latency
+	latencyWeight = 0.2
+
+	// failureBackoff is the time for which a peer isn't chosen after a request to it fails
+	// (unless all of the peers of the organization have failed)
+	failureBackoff = 10 * time.Second
+)
+
+// Selector chooses the targets of requests. A Selector is safe for concurrent use.
+type Selector interface {
+	// Select returns the targets of a request from the given peers
+	Select(peers []fab.Peer) []fab.Peer
+
+	// Done records the completion of a request that was sent to the given targets
+	Done(targets []fab.Peer, duration time.Duration, err error)
+}
+
+// NewSelector returns a Selector for the given strategy (see the cliconfig.XxxTargetStrategy constants)
+func NewSelector(strategy string) (Selector, error) {
+	switch strategy {
+	case "", cliconfig.AllTargetStrategy:
+		return &allSelector{}, nil
+	case cliconfig.RoundRobinTargetStrategy:
+		return newPerOrgSelector(&roundRobin{}), nil
+	case cliconfig.RandomTargetStrategy:
+		return newPerOrgSelector(&random{rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}), nil
+	case cliconfig.LeastLatencyTargetStrategy:
+		return newPerOrgSelector(&leastLatency{}), nil
+	case cliconfig.LeastInflightTargetStrategy:
+		return newPerOrgSelector(&leastInflight{}), nil
+	default:
+		return nil, errors.Errorf("invalid target strategy [%s]: expecting %s, %s, %s, %s or %s", strategy,
+			cliconfig.AllTargetStrategy, cliconfig.RoundRobinTargetStrategy, cliconfig.RandomTargetStrategy,
+			cliconfig.LeastLatencyTargetStrategy, cliconfig.LeastInflightTargetStrategy)
+	}
+}
+
+// allSelector sends each request to all of the peers
+type allSelector struct{}
+
+func (s *allSelector) Select(peers []fab.Peer) []fab.Peer {
+	return peers
+}
+
+func (s *allSelector) Done([]fab.Peer, time.Duration, error) {}
+
+// peerStats contains the statistics of a peer that are used by the strategies
+type peerStats struct {
+	latency  time.Duration
+	inflight int
+	failedAt time.Time
+}
+
+// chooser chooses a peer from the peers of an organization. It's called with the selector's lock held.
+type chooser interface {
+	choose(peers []fab.Peer, stats map[string]*peerStats) fab.Peer
+}
+
+// perOrgSelector chooses one peer per organization (MSP) so that endorsement policies
+// that require an endorsement from each organization may still be satisfied. Peers to
+// which a request failed recently are only chosen if all of the peers of the organization
+// have failed, so that a broken peer (which usually fails quickly) doesn't attract the load.
+type perOrgSelector struct {
+	chooser chooser
+	mutex   sync.Mutex
+	stats   map[string]*peerStats
+	now     func() time.Time
+}
+
+func newPerOrgSelector(c chooser) *perOrgSelector {
+	return &perOrgSelector{chooser: c, stats: make(map[string]*peerStats), now: time.Now}
+}
+
+func (s *perOrgSelector) Select(peers []fab.Peer) []fab.Peer {
+	var mspIDs []string
+	peersByMSP := make(map[string][]fab.Peer)
+	for _, peer := range peers {
+		if _, ok := peersByMSP[peer.MSPID()]; !ok {
+			mspIDs = append(mspIDs, peer.MSPID())
+		}
+		peersByMSP[peer.MSPID()] = append(peersByMSP[peer.MSPID()], peer)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var targets []fab.Peer
+	for _, mspID := range mspIDs {
+		peer := s.chooser.choose(s.available(peersByMSP[mspID]), s.stats)
+		s.statsFor(peer).inflight++
+		targets = append(targets, peer)
+	}
+	return targets
+}
+
+func (s *perOrgSelector) Done(targets []fab.Peer, duration time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, peer := range targets {
+		stats := s.statsFor(peer)
+		stats.inflight--
+		if err != nil {
+			// The request may have failed on another of the targets but it can't be told which one,
+			// so all of the targets back off. The duration isn't included in the latency.
+			stats.failedAt = s.now()
+			continue
+		}
+		stats.failedAt = time.Time{}
+		if stats.latency == 0 {
+			stats.latency = duration
+		} else {
+			stats.latency = time.Duration(latencyWeight*float64(duration) + (1-latencyWeight)*float64(stats.latency))
+		}
+	}
+}
+
+// available returns the peers that haven't failed within the backoff period or, if all of
+// them have, all of the peers
+func (s *perOrgSelector) available(peers []fab.Peer) []fab.Peer {
+	var available []fab.Peer
+	for _, peer := range peers {
+		if stats, ok := s.stats[peer.URL()]; !ok || stats.failedAt.IsZero() || s.now().Sub(stats.failedAt) >= failureBackoff {
+			available = append(available, peer)
+		}
+	}
+	if len(available) == 0 {
+		return peers
+	}
+	return available
+}
+
+func (s *perOrgSelector) statsFor(peer fab.Peer) *peerStats {
+	stats, ok := s.stats[peer.URL()]
+	if !ok {
+		stats = &peerStats{}
+		s.stats[peer.URL()] = stats
+	}
+	return stats
+}
+
+type roundRobin struct {
+	next map[string]int
+}
+
+func (c *roundRobin) choose(peers []fab.Peer, stats map[string]*peerStats) fab.Peer {
+	if c.next == nil {
+		c.next = make(map[string]int)
+	}
+	mspID := peers[0].MSPID()
+	i := c.next[mspID] % len(peers)
+	c.next[mspID] = i + 1
+	return peers[i]
+}
+
+type random struct {
+	rnd *rand.Rand
+}
+
+func (c *random) choose(peers []fab.Peer, stats map[string]*peerStats) fab.Peer {
+	return peers[c.rnd.Intn(len(peers))]
+}
+
+// leastLatency chooses the peer with the lowest average latency. Peers that haven't completed
+// a request yet are chosen first so that they're measured. Ties are broken by the number of
+// requests in flight.
+type leastLatency struct{}
+
+func (c *leastLatency) choose(peers []fab.Peer, stats map[string]*peerStats) fab.Peer {
+	best := peers[0]
+	for _, peer := range peers[1:] {
+		latency, bestLatency := latencyOf(peer, stats), latencyOf(best, stats)
+		if latency < bestLatency || latency == bestLatency && inflightOf(peer, stats) < inflightOf(best, stats) {
+			best = peer
+		}
+	}
+	return best
+}
+
+func latencyOf(peer fab.Peer, stats map[string]*peerStats) time.Duration {
+	if s, ok := stats[peer.URL()]; ok {
+		return s.latency
+	}
+	return 0
+}
+
+// leastInflight chooses the peer with the fewest requests in flight
+type leastInflight struct{}
+
+func (c *leastInflight) choose(peers []fab.Peer, stats map[string]*peerStats) fab.Peer {
+	best := peers[0]
+	bestInflight := inflightOf(best, stats)
+	for _, peer := range peers[1:] {
+		if inflight := inflightOf(peer, stats); inflight < bestInflight {
+			best, bestInflight = peer, inflight
+		}
+	}
+	return best
+}
+
+func inflightOf(peer fab.Peer, stats map[string]*peerStats) int {
+	if s, ok := stats[peer.URL()]; ok {
+		return s.inflight
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package target
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	cliconfig "github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/config"
+	"github.com/securekey/fabric-examples/fabric-cli/cmd/fabric-cli/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelector(t *testing.T) {
+	p1 := testutil.NewPeer("peer0.org1:7051", "Org1MSP")
+	p2 := testutil.NewPeer("peer1.org1:7051", "Org1MSP")
+	p3 := testutil.NewPeer("peer0.org2:7051", "Org2MSP")
+	peers := []fab.Peer{p1, p2, p3}
+
+	_, err := NewSelector("invalid")
+	assert.Error(t, err)
+
+	t.Run("All", func(t *testing.T) {
+		s, err := NewSelector(cliconfig.AllTargetStrategy)
+		require.NoError(t, err)
+		assert.Equal(t, peers, s.Select(peers))
+	})
+
+	t.Run("Round robin", func(t *testing.T) {
+		s, err := NewSelector(cliconfig.RoundRobinTargetStrategy)
+		require.NoError(t, err)
+		assert.Equal(t, []fab.Peer{p1, p3}, s.Select(peers))
+		assert.Equal(t, []fab.Peer{p2, p3}, s.Select(peers))
+		assert.Equal(t, []fab.Peer{p1, p3}, s.Select(peers))
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		s, err := NewSelector(cliconfig.RandomTargetStrategy)
+		require.NoError(t, err)
+		targets := s.Select(peers)
+		require.Len(t, targets, 2)
+		assert.Equal(t, "Org1MSP", targets[0].MSPID())
+		assert.Equal(t, p3, targets[1])
+	})
+
+	t.Run("Least latency", func(t *testing.T) {
+		s, err := NewSelector(cliconfig.LeastLatencyTargetStrategy)
+		require.NoError(t, err)
+
+		// Unmeasured peers are chosen first
+		targets := s.Select(peers)
+		assert.Equal(t, []fab.Peer{p1, p3}, targets)
+		s.Done(targets, 50*time.Millisecond, nil)
+		targets = s.Select(peers)
+		assert.Equal(t, []fab.Peer{p2, p3}, targets)
+		s.Done(targets, 10*time.Millisecond, nil)
+
+		assert.Equal(t, []fab.Peer{p2, p3}, s.Select(peers))
+	})
+
+	t.Run("Failed peers back off", func(t *testing.T) {
+		s, err := NewSelector(cliconfig.LeastLatencyTargetStrategy)
+		require.NoError(t, err)
+
+		now := time.Now()
+		s.(*perOrgSelector).now = func() time.Time { return now }
+
+		// A peer that fails (quickly) isn't chosen again within the backoff period
+		targets := s.Select([]fab.Peer{p1, p2})
+		assert.Equal(t, []fab.Peer{p1}, targets)
+		s.Done(targets, time.Millisecond, errors.New("failed"))
+		targets = s.Select([]fab.Peer{p1, p2})
+		assert.Equal(t, []fab.Peer{p2}, targets)
+		s.Done(targets, 50*time.Millisecond, nil)
+		assert.Equal(t, []fab.Peer{p2}, s.Select([]fab.Peer{p1, p2}))
+
+		// If all of the peers have failed then the failed peers are chosen
+		assert.Equal(t, []fab.Peer{p1}, s.Select([]fab.Peer{p1}))
+
+		// The peer is chosen again after the backoff period
+		now = now.Add(failureBackoff)
+		assert.Equal(t, []fab.Peer{p1}, s.Select([]fab.Peer{p1, p2}))
+	})
+
+	t.Run("Least inflight", func(t *testing.T) {
+		s, err := NewSelector(cliconfig.LeastInflightTargetStrategy)
+		require.NoError(t, err)
+
+		first := s.Select(peers)
+		assert.Equal(t, []fab.Peer{p1, p3}, first)
+		assert.Equal(t, []fab.Peer{p2, p3}, s.Select(peers))
+		s.Done(first, time.Millisecond, nil)
+		assert.Equal(t, []fab.Peer{p1, p3}, s.Select(peers))
+	})
+}
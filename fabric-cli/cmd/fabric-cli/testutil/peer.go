@@ -0,0 +1,20 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package testutil contains fixtures that are shared by the tests of several packages
+package testutil
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+// NewPeer returns a mock peer with the given URL in the given MSP
+func NewPeer(url, mspID string) fab.Peer {
+	p := mocks.NewMockPeer(url, url)
+	p.MockMSP = mspID
+	return p
+}
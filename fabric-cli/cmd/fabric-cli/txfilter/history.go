@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txfilter
+
+import (
+	"sort"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// KeyHistory is the history of a key reconstructed from the transactions that wrote and read it
+type KeyHistory struct {
+	Namespace string
+	Key       string
+
+	// Entries contains the writes and deletes of the key in the order in which they were committed
+	Entries []*KeyEntry
+
+	versions map[Version]*KeyEntry
+}
+
+// KeyEntry is a write or delete of a key along with the transactions that read the version of
+// the key that it created. Writes of invalid transactions are included but don't create a version.
+type KeyEntry struct {
+	// Version is the version created by the write, i.e. the position of the transaction in the ledger
+	Version Version
+
+	// Tx and Write are nil if the version was written before the blocks that were scanned,
+	// in which case the entry only contains the reads of the version
+	Tx    *Tx
+	Write *Write
+
+	// Reads contains the transactions that read this version of the key
+	Reads []*Tx
+}
+
+// NewKeyHistory returns an empty history of the given key in the given namespace (chaincode)
+func NewKeyHistory(namespace, key string) *KeyHistory {
+	return &KeyHistory{
+		Namespace: namespace,
+		Key:       key,
+		versions:  make(map[Version]*KeyEntry),
+	}
+}
+
+// Add adds the transaction to the history if it read or wrote the key. Transactions must be
+// added in the order in which they were committed. Reads of a key that didn't exist are ignored.
+func (h *KeyHistory) Add(tx *Tx) {
+	for _, r := range tx.Reads {
+		if r.Namespace != h.Namespace || r.Key != h.Key || r.Version == nil {
+			continue
+		}
+
+		entry, ok := h.versions[*r.Version]
+		if !ok {
+			entry = &KeyEntry{Version: *r.Version}
+			h.versions[*r.Version] = entry
+			h.Entries = append(h.Entries, entry)
+			h.sort()
+		}
+		entry.Reads = append(entry.Reads, tx)
+	}
+
+	for _, w := range tx.Writes {
+		if w.Namespace != h.Namespace || w.Key != h.Key {
+			continue
+		}
+
+		entry := &KeyEntry{
+			Version: Version{BlockNum: tx.BlockNum, TxNum: uint64(tx.Index)},
+			Tx:      tx,
+			Write:   w,
+		}
+		h.Entries = append(h.Entries, entry)
+		if tx.ValidationCode == pb.TxValidationCode_VALID {
+			h.versions[entry.Version] = entry
+		}
+	}
+}
+
+// sort sorts the entries by version. Entries for versions that were written before the
+// scanned blocks are added when they're first read, so they may be out of order.
+func (h *KeyHistory) sort() {
+	sort.SliceStable(h.Entries, func(i, j int) bool {
+		vi, vj := h.Entries[i].Version, h.Entries[j].Version
+		if vi.BlockNum != vj.BlockNum {
+			return vi.BlockNum < vj.BlockNum
+		}
+		return vi.TxNum < vj.TxNum
+	})
+}
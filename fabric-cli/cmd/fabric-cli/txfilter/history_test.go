@@ -0,0 +1,63 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txfilter
+
+import (
+	"testing"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyHistory(t *testing.T) {
+	h := NewKeyHistory("examplecc", "A")
+
+	// Reads a version written before the scanned blocks and writes a new version
+	tx1 := &Tx{
+		BlockNum: 10, Index: 0, TxID: "tx1", ValidationCode: pb.TxValidationCode_VALID,
+		Reads:  []*Read{{Namespace: "examplecc", Key: "A", Version: &Version{BlockNum: 3, TxNum: 1}}},
+		Writes: []*Write{{Namespace: "examplecc", Key: "A", Value: []byte("1")}, {Namespace: "examplecc", Key: "B"}},
+	}
+	// Reads the version written by tx1 but fails validation so its write doesn't create a version
+	tx2 := &Tx{
+		BlockNum: 11, Index: 2, TxID: "tx2", ValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT,
+		Reads:  []*Read{{Namespace: "examplecc", Key: "A", Version: &Version{BlockNum: 10, TxNum: 0}}},
+		Writes: []*Write{{Namespace: "examplecc", Key: "A", Value: []byte("2")}},
+	}
+	// Deletes the key
+	tx3 := &Tx{
+		BlockNum: 12, Index: 0, TxID: "tx3", ValidationCode: pb.TxValidationCode_VALID,
+		Reads:  []*Read{{Namespace: "examplecc", Key: "A", Version: &Version{BlockNum: 10, TxNum: 0}}},
+		Writes: []*Write{{Namespace: "examplecc", Key: "A", IsDelete: true}},
+	}
+	// Reads the key after it was deleted and reads the same key in another namespace
+	tx4 := &Tx{
+		BlockNum: 13, Index: 0, TxID: "tx4", ValidationCode: pb.TxValidationCode_VALID,
+		Reads: []*Read{{Namespace: "examplecc", Key: "A"}, {Namespace: "othercc", Key: "A", Version: &Version{BlockNum: 1}}},
+	}
+
+	for _, tx := range []*Tx{tx1, tx2, tx3, tx4} {
+		h.Add(tx)
+	}
+
+	require.Len(t, h.Entries, 4)
+
+	assert.Equal(t, Version{BlockNum: 3, TxNum: 1}, h.Entries[0].Version)
+	assert.Nil(t, h.Entries[0].Tx)
+	assert.Equal(t, []*Tx{tx1}, h.Entries[0].Reads)
+
+	assert.Equal(t, tx1, h.Entries[1].Tx)
+	assert.Equal(t, []byte("1"), h.Entries[1].Write.Value)
+	assert.Equal(t, []*Tx{tx2, tx3}, h.Entries[1].Reads)
+
+	assert.Equal(t, tx2, h.Entries[2].Tx)
+	assert.Empty(t, h.Entries[2].Reads)
+
+	assert.Equal(t, tx3, h.Entries[3].Tx)
+	assert.True(t, h.Entries[3].Write.IsDelete)
+}
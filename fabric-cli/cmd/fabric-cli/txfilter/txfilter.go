@@ -0,0 +1,307 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package txfilter extracts a summary of each of the transactions in a block (the chaincode, function,
+// creator, validation code and the keys read and written) so that transactions may be searched for over
+// a range of blocks with a Filter, or the history of a key may be reconstructed with a KeyHistory.
+package txfilter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Valid matches transactions that are valid
+	Valid = "valid"
+
+	// Invalid matches transactions with any validation code other than VALID
+	Invalid = "invalid"
+)
+
+// Tx is a summary of a transaction in a block
+type Tx struct {
+	BlockNum       uint64
+	Index          int
+	TxID           string
+	Type           common.HeaderType
+	Timestamp      time.Time
+	CreatorMSPID   string
+	ChaincodeID    string
+	Function       string
+	ValidationCode pb.TxValidationCode
+
+	// Writes contains the keys written by the transaction in all namespaces
+	Writes []*Write
+
+	// Reads contains the keys read by the transaction in all namespaces
+	Reads []*Read
+
+	// Envelope is the transaction envelope from the block
+	Envelope *common.Envelope
+
+	// DecodeErr is set if the transaction couldn't be decoded (e.g. a transaction that was invalidated with
+	// BAD_PAYLOAD), in which case only the fields that were decoded before the error are set
+	DecodeErr error
+}
+
+// Write is a key written (or deleted) by a transaction
+type Write struct {
+	Namespace string
+	Key       string
+	IsDelete  bool
+	Value     []byte
+}
+
+// Read is a key read by a transaction. Version is nil if the key didn't exist when it was read.
+type Read struct {
+	Namespace string
+	Key       string
+	Version   *Version
+}
+
+// Version is the version of a key, i.e. the position of the transaction that last wrote it
+type Version struct {
+	BlockNum uint64
+	TxNum    uint64
+}
+
+// Filter contains the criteria that a transaction must match. Criteria that aren't set match all transactions.
+type Filter struct {
+	ChaincodeID  string
+	Function     string
+	CreatorMSPID string
+
+	// ValidationCode is "valid", "invalid" or the name of a validation code, e.g. MVCC_READ_CONFLICT
+	ValidationCode string
+
+	// Key and KeyPrefix match transactions that wrote the given key, or a key with the given prefix
+	Key       string
+	KeyPrefix string
+
+	// Since and Until restrict the transactions to those with a timestamp in the given window (inclusive)
+	Since time.Time
+	Until time.Time
+}
+
+// Validate returns an error if the filter's criteria are invalid
+func (f *Filter) Validate() error {
+	code := f.ValidationCode
+	if code != "" && !strings.EqualFold(code, Valid) && !strings.EqualFold(code, Invalid) {
+		if _, ok := pb.TxValidationCode_value[strings.ToUpper(code)]; !ok {
+			return errors.Errorf("invalid validation code [%s] - must be valid, invalid or the name of a validation code", code)
+		}
+	}
+
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return errors.Errorf("the end of the time window [%s] is before the start [%s]", f.Until, f.Since)
+	}
+	return nil
+}
+
+// Match returns true if the transaction matches all of the criteria of the filter
+func (f *Filter) Match(tx *Tx) bool {
+	if f.ChaincodeID != "" && tx.ChaincodeID != f.ChaincodeID {
+		return false
+	}
+	if f.Function != "" && tx.Function != f.Function {
+		return false
+	}
+	if f.CreatorMSPID != "" && tx.CreatorMSPID != f.CreatorMSPID {
+		return false
+	}
+	if !f.matchValidationCode(tx.ValidationCode) {
+		return false
+	}
+	if !f.Since.IsZero() && tx.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && tx.Timestamp.After(f.Until) {
+		return false
+	}
+	return f.matchWrites(tx.Writes)
+}
+
+func (f *Filter) matchValidationCode(code pb.TxValidationCode) bool {
+	switch {
+	case f.ValidationCode == "":
+		return true
+	case strings.EqualFold(f.ValidationCode, Valid):
+		return code == pb.TxValidationCode_VALID
+	case strings.EqualFold(f.ValidationCode, Invalid):
+		return code != pb.TxValidationCode_VALID
+	default:
+		return code.String() == strings.ToUpper(f.ValidationCode)
+	}
+}
+
+func (f *Filter) matchWrites(writes []*Write) bool {
+	if f.Key == "" && f.KeyPrefix == "" {
+		return true
+	}
+	for _, w := range writes {
+		if f.Key != "" && w.Key == f.Key {
+			return true
+		}
+		if f.KeyPrefix != "" && strings.HasPrefix(w.Key, f.KeyPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extract returns a summary of each of the transactions in the block. A transaction that can't be decoded
+// doesn't prevent the other transactions from being extracted; its summary contains the decode error.
+func Extract(block *common.Block) []*Tx {
+	var txFilter []byte
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	}
+
+	var txs []*Tx
+	for i, data := range block.Data.GetData() {
+		tx, err := extractTx(data)
+		if err != nil {
+			tx.DecodeErr = errors.WithMessagef(err, "error decoding transaction %d of block %d", i, block.Header.Number)
+		}
+
+		tx.BlockNum = block.Header.Number
+		tx.Index = i
+		tx.ValidationCode = pb.TxValidationCode_NOT_VALIDATED
+		if i < len(txFilter) {
+			tx.ValidationCode = pb.TxValidationCode(txFilter[i])
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// extractTx decodes the transaction. If an error occurs then the fields that were decoded
+// before the error are returned along with the error.
+func extractTx(data []byte) (*Tx, error) {
+	tx := &Tx{}
+
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return tx, errors.Wrap(err, "error unmarshalling envelope")
+	}
+	tx.Envelope = envelope
+
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return tx, errors.Wrap(err, "error unmarshalling payload")
+	}
+	if payload.Header == nil {
+		return tx, errors.New("payload header is missing")
+	}
+
+	chdr := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, chdr); err != nil {
+		return tx, errors.Wrap(err, "error unmarshalling channel header")
+	}
+	tx.TxID = chdr.TxId
+	tx.Type = common.HeaderType(chdr.Type)
+	if chdr.Timestamp != nil {
+		if ts, err := ptypes.Timestamp(chdr.Timestamp); err == nil {
+			tx.Timestamp = ts
+		}
+	}
+
+	sigHeader := &common.SignatureHeader{}
+	if err := proto.Unmarshal(payload.Header.SignatureHeader, sigHeader); err != nil {
+		return tx, errors.Wrap(err, "error unmarshalling signature header")
+	}
+	creator := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(sigHeader.Creator, creator); err == nil {
+		tx.CreatorMSPID = creator.Mspid
+	}
+
+	if tx.Type != common.HeaderType_ENDORSER_TRANSACTION {
+		return tx, nil
+	}
+
+	ccHdrExt := &pb.ChaincodeHeaderExtension{}
+	if err := proto.Unmarshal(chdr.Extension, ccHdrExt); err != nil {
+		return tx, errors.Wrap(err, "error unmarshalling chaincode header extension")
+	}
+	tx.ChaincodeID = ccHdrExt.ChaincodeId.GetName()
+
+	transaction := &pb.Transaction{}
+	if err := proto.Unmarshal(payload.Data, transaction); err != nil {
+		return tx, errors.Wrap(err, "error unmarshalling transaction")
+	}
+	for i, action := range transaction.Actions {
+		if err := tx.addAction(action, i == 0); err != nil {
+			return tx, errors.WithMessagef(err, "error extracting action %d", i)
+		}
+	}
+	return tx, nil
+}
+
+// addAction adds the reads and writes of the transaction action to the transaction. The function is taken
+// from the invocation spec of the first action.
+func (tx *Tx) addAction(action *pb.TransactionAction, first bool) error {
+	ccActionPayload := &pb.ChaincodeActionPayload{}
+	if err := proto.Unmarshal(action.Payload, ccActionPayload); err != nil {
+		return errors.Wrap(err, "error unmarshalling chaincode action payload")
+	}
+
+	if first {
+		cpp := &pb.ChaincodeProposalPayload{}
+		if err := proto.Unmarshal(ccActionPayload.ChaincodeProposalPayload, cpp); err != nil {
+			return errors.Wrap(err, "error unmarshalling chaincode proposal payload")
+		}
+		cis := &pb.ChaincodeInvocationSpec{}
+		if err := proto.Unmarshal(cpp.Input, cis); err != nil {
+			return errors.Wrap(err, "error unmarshalling chaincode invocation spec")
+		}
+		if args := cis.GetChaincodeSpec().GetInput().GetArgs(); len(args) > 0 {
+			tx.Function = string(args[0])
+		}
+	}
+
+	if ccActionPayload.Action == nil {
+		return nil
+	}
+
+	prp := &pb.ProposalResponsePayload{}
+	if err := proto.Unmarshal(ccActionPayload.Action.ProposalResponsePayload, prp); err != nil {
+		return errors.Wrap(err, "error unmarshalling proposal response payload")
+	}
+	ccAction := &pb.ChaincodeAction{}
+	if err := proto.Unmarshal(prp.Extension, ccAction); err != nil {
+		return errors.Wrap(err, "error unmarshalling chaincode action")
+	}
+	if len(ccAction.Results) == 0 {
+		return nil
+	}
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(ccAction.Results); err != nil {
+		return errors.Wrap(err, "error unmarshalling read-write set")
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, w := range nsRWSet.KvRwSet.GetWrites() {
+			tx.Writes = append(tx.Writes, &Write{Namespace: nsRWSet.NameSpace, Key: w.Key, IsDelete: w.IsDelete, Value: w.Value})
+		}
+		for _, r := range nsRWSet.KvRwSet.GetReads() {
+			read := &Read{Namespace: nsRWSet.NameSpace, Key: r.Key}
+			if r.Version != nil {
+				read.Version = &Version{BlockNum: r.Version.BlockNum, TxNum: r.Version.TxNum}
+			}
+			tx.Reads = append(tx.Reads, read)
+		}
+	}
+	return nil
+}
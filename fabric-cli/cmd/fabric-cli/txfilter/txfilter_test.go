@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txfilter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtract(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	block := &common.Block{
+		Header: &common.BlockHeader{Number: 7},
+		Data: &common.BlockData{Data: [][]byte{
+			newEndorserTx(t, "tx1", "Org1MSP", "examplecc", ts, "move", "account_A", "account_B"),
+			newEndorserTx(t, "tx2", "Org2MSP", "othercc", ts.Add(time.Hour), "set", "config"),
+			[]byte("not a transaction"),
+		}},
+		Metadata: &common.BlockMetadata{Metadata: [][]byte{
+			{}, {}, {byte(pb.TxValidationCode_VALID), byte(pb.TxValidationCode_MVCC_READ_CONFLICT), byte(pb.TxValidationCode_BAD_PAYLOAD)},
+		}},
+	}
+
+	txs := Extract(block)
+	require.Len(t, txs, 3)
+
+	tx := txs[0]
+	assert.Equal(t, uint64(7), tx.BlockNum)
+	assert.Equal(t, 0, tx.Index)
+	assert.Equal(t, "tx1", tx.TxID)
+	assert.Equal(t, common.HeaderType_ENDORSER_TRANSACTION, tx.Type)
+	assert.Equal(t, ts, tx.Timestamp)
+	assert.Equal(t, "Org1MSP", tx.CreatorMSPID)
+	assert.Equal(t, "examplecc", tx.ChaincodeID)
+	assert.Equal(t, "move", tx.Function)
+	assert.Equal(t, pb.TxValidationCode_VALID, tx.ValidationCode)
+	assert.Equal(t, []*Write{{Namespace: "examplecc", Key: "account_A", Value: []byte("value")}, {Namespace: "examplecc", Key: "account_B", Value: []byte("value")}}, tx.Writes)
+	assert.Equal(t, []*Read{{Namespace: "examplecc", Key: "account_A", Version: &Version{BlockNum: 3, TxNum: 1}}, {Namespace: "examplecc", Key: "account_B"}}, tx.Reads)
+
+	assert.NoError(t, tx.DecodeErr)
+
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, txs[1].ValidationCode)
+
+	// A transaction that can't be decoded doesn't prevent the others from being extracted
+	assert.Equal(t, 2, txs[2].Index)
+	assert.Equal(t, pb.TxValidationCode_BAD_PAYLOAD, txs[2].ValidationCode)
+	assert.Error(t, txs[2].DecodeErr)
+	txs = txs[:2]
+
+	match := func(f *Filter) []string {
+		require.NoError(t, f.Validate())
+		var ids []string
+		for _, tx := range txs {
+			if f.Match(tx) {
+				ids = append(ids, tx.TxID)
+			}
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"tx1", "tx2"}, match(&Filter{}))
+	assert.Equal(t, []string{"tx2"}, match(&Filter{ChaincodeID: "othercc"}))
+	assert.Equal(t, []string{"tx1"}, match(&Filter{Function: "move"}))
+	assert.Equal(t, []string{"tx2"}, match(&Filter{CreatorMSPID: "Org2MSP"}))
+	assert.Equal(t, []string{"tx1"}, match(&Filter{ValidationCode: "VALID"}))
+	assert.Equal(t, []string{"tx2"}, match(&Filter{ValidationCode: "invalid"}))
+	assert.Equal(t, []string{"tx2"}, match(&Filter{ValidationCode: "mvcc_read_conflict"}))
+	assert.Equal(t, []string{"tx1"}, match(&Filter{Key: "account_B"}))
+	assert.Equal(t, []string{"tx1"}, match(&Filter{KeyPrefix: "account_"}))
+	assert.Empty(t, match(&Filter{Key: "account_"}))
+	assert.Equal(t, []string{"tx2"}, match(&Filter{Since: ts.Add(time.Minute)}))
+	assert.Equal(t, []string{"tx1"}, match(&Filter{Until: ts}))
+	assert.Empty(t, match(&Filter{ChaincodeID: "examplecc", ValidationCode: "invalid"}))
+
+	assert.Error(t, (&Filter{ValidationCode: "bogus"}).Validate())
+	assert.Error(t, (&Filter{Since: ts, Until: ts.Add(-time.Second)}).Validate())
+}
+
+func newEndorserTx(t *testing.T, txID, mspID, ccID string, ts time.Time, fn string, keys ...string) []byte {
+	timestamp, err := ptypes.TimestampProto(ts)
+	require.NoError(t, err)
+
+	kvRWSet := &kvrwset.KVRWSet{}
+	for i, key := range keys {
+		// The first key is read at version 3:1 and the others are read before they exist
+		read := &kvrwset.KVRead{Key: key}
+		if i == 0 {
+			read.Version = &kvrwset.Version{BlockNum: 3, TxNum: 1}
+		}
+		kvRWSet.Reads = append(kvRWSet.Reads, read)
+		kvRWSet.Writes = append(kvRWSet.Writes, &kvrwset.KVWrite{Key: key, Value: []byte("value")})
+	}
+	txRWSet := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{NameSpace: ccID, KvRwSet: kvRWSet}}}
+	results, err := txRWSet.ToProtoBytes()
+	require.NoError(t, err)
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: ccID},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(fn), []byte("arg")}},
+		},
+	}
+	ccAction := &pb.ChaincodeAction{Results: results, ChaincodeId: &pb.ChaincodeID{Name: ccID}}
+	prp := &pb.ProposalResponsePayload{Extension: marshal(t, ccAction)}
+	ccActionPayload := &pb.ChaincodeActionPayload{
+		ChaincodeProposalPayload: marshal(t, &pb.ChaincodeProposalPayload{Input: marshal(t, cis)}),
+		Action:                   &pb.ChaincodeEndorsedAction{ProposalResponsePayload: marshal(t, prp)},
+	}
+	tx := &pb.Transaction{Actions: []*pb.TransactionAction{{Payload: marshal(t, ccActionPayload)}}}
+
+	chdr := &common.ChannelHeader{
+		Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+		TxId:      txID,
+		Timestamp: timestamp,
+		Extension: marshal(t, &pb.ChaincodeHeaderExtension{ChaincodeId: &pb.ChaincodeID{Name: ccID}}),
+	}
+	sigHeader := &common.SignatureHeader{Creator: marshal(t, &msp.SerializedIdentity{Mspid: mspID})}
+	payload := &common.Payload{
+		Header: &common.Header{ChannelHeader: marshal(t, chdr), SignatureHeader: marshal(t, sigHeader)},
+		Data:   marshal(t, tx),
+	}
+	return marshal(t, &common.Envelope{Payload: marshal(t, payload)})
+}
+
+func marshal(t *testing.T, msg proto.Message) []byte {
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return data
+}